@@ -0,0 +1,340 @@
+package core
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	oauth2 "github.com/MontFerret/contrib/modules/security/oauth2/core"
+	ferrethttp "github.com/MontFerret/ferret/v2/pkg/net/http"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+type (
+	// Auth authenticates every request sent by a client.
+	Auth struct {
+		oauth2   *tokenSource
+		Kind     AuthKind
+		Username string
+		Password string
+		Token    string
+		Scheme   string
+		Name     string
+		Key      string
+		InQuery  bool
+	}
+
+	// tokenSource acquires OAuth2 access tokens and caches them until they expire.
+	tokenSource struct {
+		client   *oauth2.Client
+		token    *oauth2.TokenSet
+		now      func() time.Time
+		audience string
+		scope    []string
+		mu       sync.Mutex
+		stale    bool
+	}
+)
+
+const (
+	authOwner = clientConfigOwner + ".auth"
+
+	// tokenExpirySkew renews tokens shortly before they expire.
+	tokenExpirySkew = 30 * time.Second
+)
+
+// NewOAuth2Auth authenticates requests with tokens from an OAuth2 client.
+// An initial token is used until it expires and is then refreshed with its refresh token.
+// Without a refresh token, tokens are acquired with the client credentials grant.
+func NewOAuth2Auth(client *oauth2.Client, token *oauth2.TokenSet, scope []string, audience string) *Auth {
+	return &Auth{
+		Kind: AuthOAuth2,
+		oauth2: &tokenSource{
+			client:   client,
+			token:    token,
+			scope:    scope,
+			audience: audience,
+			now:      time.Now,
+		},
+	}
+}
+
+// DecodeAuth decodes the auth block of a client configuration.
+func DecodeAuth(ctx context.Context, value runtime.Value) (*Auth, error) {
+	if runtime.TypeNone.Is(value) {
+		return nil, nil
+	}
+
+	obj, err := requireMap(ctx, value, authOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	kindValue, found, err := lookupString(ctx, obj, "type", authOwner)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%s.type is required", authOwner)
+	}
+
+	kind, err := parseAuthKind(kindValue)
+	if err != nil {
+		return nil, fmt.Errorf("%s.type: %w", authOwner, err)
+	}
+
+	auth := &Auth{Kind: kind}
+
+	switch kind {
+	case AuthBasic:
+		if err := decodeAuthStrings(ctx, obj, map[string]*string{
+			"username": &auth.Username,
+			"password": &auth.Password,
+		}, "username"); err != nil {
+			return nil, err
+		}
+	case AuthBearer:
+		auth.Scheme = "Bearer"
+
+		if err := decodeAuthStrings(ctx, obj, map[string]*string{
+			"token":  &auth.Token,
+			"scheme": &auth.Scheme,
+		}, "token"); err != nil {
+			return nil, err
+		}
+
+		if auth.Scheme == "" {
+			auth.Scheme = "Bearer"
+		}
+	case AuthAPIKey:
+		in := "header"
+
+		if err := decodeAuthStrings(ctx, obj, map[string]*string{
+			"name": &auth.Name,
+			"key":  &auth.Key,
+			"in":   &in,
+		}, "name", "key"); err != nil {
+			return nil, err
+		}
+
+		switch strings.ToLower(in) {
+		case "header":
+		case "query":
+			auth.InQuery = true
+		default:
+			return nil, fmt.Errorf("%s.in must be \"header\" or \"query\"", authOwner)
+		}
+	case AuthOAuth2:
+		return decodeOAuth2Auth(ctx, obj)
+	}
+
+	return auth, nil
+}
+
+func decodeOAuth2Auth(ctx context.Context, obj runtime.Map) (*Auth, error) {
+	clientValue, found, err := lookupValue(ctx, obj, "client")
+	if err != nil {
+		return nil, err
+	}
+
+	client, ok := oauth2.ClientFrom(clientValue)
+	if !found || !ok {
+		return nil, fmt.Errorf("%s.client must be a SECURITY::OAUTH2 client", authOwner)
+	}
+
+	var token *oauth2.TokenSet
+
+	if tokenValue, found, err := lookupValue(ctx, obj, "token"); err != nil {
+		return nil, err
+	} else if found && !runtime.TypeNone.Is(tokenValue) {
+		if token, ok = oauth2.TokenFrom(tokenValue); !ok {
+			return nil, fmt.Errorf("%s.token must be a SECURITY::OAUTH2 token", authOwner)
+		}
+	}
+
+	var scope []string
+
+	if value, found, err := lookupValue(ctx, obj, "scope"); err != nil {
+		return nil, err
+	} else if found {
+		if scope, err = decodeScope(ctx, value); err != nil {
+			return nil, err
+		}
+	}
+
+	audience, _, err := lookupString(ctx, obj, "audience", authOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewOAuth2Auth(client, token, scope, strings.TrimSpace(audience)), nil
+}
+
+// decodeAuthStrings decodes string fields of the auth block. Only the fields that select how credentials are sent
+// are trimmed, so usernames, passwords, tokens and keys are used exactly as given.
+func decodeAuthStrings(ctx context.Context, obj runtime.Map, fields map[string]*string, required ...string) error {
+	for key, target := range fields {
+		str, found, err := lookupString(ctx, obj, key, authOwner)
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			continue
+		}
+
+		switch key {
+		case "in", "scheme", "name":
+			str = strings.TrimSpace(str)
+		}
+
+		*target = str
+	}
+
+	for _, key := range required {
+		if *fields[key] == "" {
+			return fmt.Errorf("%s.%s is required", authOwner, key)
+		}
+	}
+
+	return nil
+}
+
+func decodeScope(ctx context.Context, value runtime.Value) ([]string, error) {
+	if str, ok := value.(runtime.String); ok {
+		return strings.Fields(str.String()), nil
+	}
+
+	list, ok := value.(runtime.List)
+	if !ok {
+		return nil, fmt.Errorf("%s.scope must be a string or an array of strings", authOwner)
+	}
+
+	scope := make([]string, 0)
+	err := list.ForEach(ctx, func(_ context.Context, item runtime.Value, _ runtime.Int) (runtime.Boolean, error) {
+		str, ok := item.(runtime.String)
+		if !ok {
+			return runtime.False, fmt.Errorf("%s.scope must be a string or an array of strings", authOwner)
+		}
+
+		scope = append(scope, str.String())
+
+		return runtime.True, nil
+	})
+
+	return scope, err
+}
+
+// apply authenticates a request. Authorization headers set explicitly on the client or request take precedence.
+// It returns the OAuth2 access token it used, if any.
+func (a *Auth) apply(ctx context.Context, httpClient ferrethttp.Client, headers http.Header, requestURL string) (http.Header, string, string, error) {
+	if a == nil {
+		return headers, requestURL, "", nil
+	}
+
+	out := headers.Clone()
+
+	switch a.Kind {
+	case AuthBasic:
+		if !hasHeader(out, "Authorization") {
+			out.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(a.Username+":"+a.Password)))
+		}
+	case AuthBearer:
+		if !hasHeader(out, "Authorization") {
+			out.Set("Authorization", a.Scheme+" "+a.Token)
+		}
+	case AuthAPIKey:
+		if !a.InQuery {
+			if !hasHeader(out, a.Name) {
+				out.Set(a.Name, a.Key)
+			}
+
+			break
+		}
+
+		parsed, err := url.Parse(requestURL)
+		if err != nil {
+			return nil, "", "", err
+		}
+
+		values := parsed.Query()
+		values.Set(a.Name, a.Key)
+		parsed.RawQuery = values.Encode()
+		requestURL = parsed.String()
+	case AuthOAuth2:
+		if hasHeader(out, "Authorization") {
+			break
+		}
+
+		token, err := a.oauth2.accessToken(ctx, httpClient)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("acquire OAuth2 token: %w", err)
+		}
+
+		header, err := oauth2.AuthorizationHeader(token)
+		if err != nil {
+			return nil, "", "", err
+		}
+
+		out.Set("Authorization", header["Authorization"])
+
+		return out, requestURL, token.AccessToken, nil
+	}
+
+	return out, requestURL, "", nil
+}
+
+// accessToken returns the cached token or acquires a new one when it is missing, expired or rejected.
+func (s *tokenSource) accessToken(ctx context.Context, httpClient ferrethttp.Client) (*oauth2.TokenSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && !s.stale && !s.token.Expired(s.now(), tokenExpirySkew) {
+		return s.token, nil
+	}
+
+	executor, err := oauth2.NewExecutor(httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	var token *oauth2.TokenSet
+
+	if s.token != nil && s.token.RefreshToken != "" {
+		token, err = executor.Refresh(ctx, s.client, s.token, oauth2.RefreshOptions{Scope: s.scope})
+	} else {
+		token, err = executor.ClientCredentials(ctx, s.client, oauth2.ClientCredentialsOptions{
+			Scope:    s.scope,
+			Audience: s.audience,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if token.RefreshToken == "" && s.token != nil {
+		// providers may omit the refresh token when it does not rotate
+		token.RefreshToken = s.token.RefreshToken
+	}
+
+	s.token = token
+	s.stale = false
+
+	return token, nil
+}
+
+// invalidate marks a rejected token so the next request acquires a new one.
+// A token that was already replaced by a concurrent request is left alone.
+func (s *tokenSource) invalidate(accessToken string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && s.token.AccessToken == accessToken {
+		s.stale = true
+	}
+}
@@ -0,0 +1,260 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	oauth2 "github.com/MontFerret/contrib/modules/security/oauth2/core"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+func TestClientStaticAuth(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"authorization": r.Header.Get("Authorization"),
+			"key":           r.Header.Get("X-API-Key"),
+			"query":         r.URL.RawQuery,
+		})
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+
+	cases := []struct {
+		auth  map[string]runtime.Value
+		field string
+		want  string
+	}{
+		{
+			auth: map[string]runtime.Value{
+				"type":     runtime.NewString("basic"),
+				"username": runtime.NewString("ada"),
+				"password": runtime.NewString("secret"),
+			},
+			field: "authorization",
+			want:  "Basic YWRhOnNlY3JldA==",
+		},
+		{
+			auth: map[string]runtime.Value{
+				"type":  runtime.NewString("bearer"),
+				"token": runtime.NewString("abc"),
+			},
+			field: "authorization",
+			want:  "Bearer abc",
+		},
+		{
+			auth: map[string]runtime.Value{
+				"type": runtime.NewString("apiKey"),
+				"name": runtime.NewString("X-API-Key"),
+				"key":  runtime.NewString("k1"),
+			},
+			field: "key",
+			want:  "k1",
+		},
+		{
+			auth: map[string]runtime.Value{
+				"type": runtime.NewString("apiKey"),
+				"name": runtime.NewString("api_key"),
+				"key":  runtime.NewString("k2"),
+				"in":   runtime.NewString("query"),
+			},
+			field: "query",
+			want:  "api_key=k2&page=1",
+		},
+	}
+
+	for _, tc := range cases {
+		cfg, err := DecodeClientConfig(ctx, object(t, map[string]runtime.Value{
+			"baseUrl": runtime.NewString(server.URL),
+			"auth":    object(t, tc.auth),
+		}))
+		if err != nil {
+			t.Fatalf("unexpected config error: %v", err)
+		}
+
+		out, err := NewClient(cfg).QueryOne(ctx, runtime.Query{
+			Expression: runtime.NewString("/me"),
+			Params: object(t, map[string]runtime.Value{
+				"query": object(t, map[string]runtime.Value{
+					"page": runtime.NewInt(1),
+				}),
+			}),
+		})
+		if err != nil {
+			t.Fatalf("unexpected query error: %v", err)
+		}
+		if got := field(t, out, tc.field); got != runtime.NewString(tc.want) {
+			t.Fatalf("expected %s %q, got %s", tc.field, tc.want, got.String())
+		}
+	}
+}
+
+func TestClientExplicitAuthorizationHeaderWins(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+	cfg.ResponseEncoding = EncodingText
+	cfg.Auth = &Auth{Kind: AuthBearer, Scheme: "Bearer", Token: "client"}
+
+	out, err := NewClient(cfg).QueryOne(ctx, runtime.Query{
+		Expression: runtime.NewString("/me"),
+		Params: object(t, map[string]runtime.Value{
+			"headers": object(t, map[string]runtime.Value{
+				"Authorization": runtime.NewString("Bearer request"),
+			}),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+	if out != runtime.NewString("Bearer request") {
+		t.Fatalf("expected the request header to win, got %s", out.String())
+	}
+}
+
+func TestClientOAuth2Auth(t *testing.T) {
+	t.Parallel()
+
+	var issued atomic.Int32
+	var rejected atomic.Bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if err := r.ParseForm(); err != nil {
+				t.Fatalf("failed to parse token request: %v", err)
+			}
+			if r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("scope") != "read" {
+				t.Fatalf("unexpected token request: %v", r.PostForm)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"access_token": fmt.Sprintf("token-%d", issued.Add(1)),
+				"token_type":   "Bearer",
+				"expires_in":   3600,
+			})
+
+			return
+		}
+
+		// the first token is revoked after its first use
+		if r.Header.Get("Authorization") == "Bearer token-1" && !rejected.CompareAndSwap(false, true) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer server.Close()
+
+	provider, err := oauth2.NewProvider(oauth2.ProviderConfig{
+		TokenEndpoint:     server.URL + "/token",
+		InsecureAllowHTTP: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	oauthClient, err := oauth2.NewClient(provider, oauth2.ClientConfig{
+		ClientID:     "client",
+		ClientSecret: "secret",
+	})
+	if err != nil {
+		t.Fatalf("failed to create OAuth2 client: %v", err)
+	}
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+	cfg.ResponseEncoding = EncodingText
+	cfg.Auth = NewOAuth2Auth(oauthClient, nil, []string{"read"}, "")
+	client := NewClient(cfg)
+
+	for _, want := range []string{"Bearer token-1", "Bearer token-2", "Bearer token-2"} {
+		out, err := client.QueryOne(ctx, runtime.Query{Expression: runtime.NewString("/me")})
+		if err != nil {
+			t.Fatalf("unexpected query error: %v", err)
+		}
+		if out != runtime.NewString(want) {
+			t.Fatalf("expected %q, got %s", want, out.String())
+		}
+	}
+
+	if got := issued.Load(); got != 2 {
+		t.Fatalf("expected a cached token and a single renewal after 401, got %d token requests", got)
+	}
+}
+
+func TestDecodeAuth(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	auth, err := DecodeAuth(ctx, object(t, map[string]runtime.Value{
+		"type":   runtime.NewString("bearer"),
+		"token":  runtime.NewString("abc"),
+		"scheme": runtime.NewString("Token"),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected auth error: %v", err)
+	}
+	if auth.Kind != AuthBearer || auth.Scheme != "Token" {
+		t.Fatalf("unexpected auth: %+v", auth)
+	}
+
+	auth, err = DecodeAuth(ctx, object(t, map[string]runtime.Value{
+		"type": runtime.NewString(" apiKey "),
+		"name": runtime.NewString(" X-Key "),
+		"key":  runtime.NewString(" k3 "),
+		"in":   runtime.NewString(" query "),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected auth error: %v", err)
+	}
+	if auth.Name != "X-Key" || auth.Key != " k3 " || !auth.InQuery {
+		t.Fatalf("expected settings to be trimmed and the key kept as given: %+v", auth)
+	}
+
+	auth, err = DecodeAuth(ctx, object(t, map[string]runtime.Value{
+		"type":     runtime.NewString("basic"),
+		"username": runtime.NewString("ada "),
+		"password": runtime.NewString(" secret\t"),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected auth error: %v", err)
+	}
+	if auth.Username != "ada " || auth.Password != " secret\t" {
+		t.Fatalf("expected credentials to be kept as given: %+v", auth)
+	}
+
+	for _, invalid := range []map[string]runtime.Value{
+		{"username": runtime.NewString("ada")},
+		{"type": runtime.NewString("digest")},
+		{"type": runtime.NewString("basic")},
+		{"type": runtime.NewString("bearer")},
+		{"type": runtime.NewString("apiKey"), "name": runtime.NewString("X-Key")},
+		{"type": runtime.NewString("apiKey"), "name": runtime.NewString("X-Key"), "key": runtime.NewString("k"), "in": runtime.NewString("cookie")},
+		{"type": runtime.NewString("oauth2"), "client": runtime.NewString("client")},
+	} {
+		if _, err := DecodeAuth(ctx, object(t, invalid)); err == nil {
+			t.Fatalf("expected auth block %v to fail", invalid)
+		}
+	}
+}
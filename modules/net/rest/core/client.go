@@ -9,6 +9,7 @@ import (
 
 // Client is an opaque HTTP API client exposed to Ferret.
 type Client struct {
+	api    *OpenAPI
 	config Config
 	id     uint64
 }
@@ -21,31 +22,86 @@ func NewClient(config Config) *Client {
 	}
 }
 
+// NewOpenAPIClient creates a client whose queries call the operations of an OpenAPI document.
+func NewOpenAPIClient(config Config, api *OpenAPI) *Client {
+	client := NewClient(config)
+	client.api = api
+
+	return client
+}
+
 func (c *Client) Query(ctx context.Context, q runtime.Query) (runtime.List, error) {
-	value, flatten, err := executeQuery(ctx, c, q)
+	ex, err := prepareQuery(ctx, c, q)
 	if err != nil {
 		return nil, err
 	}
 
-	if flatten {
-		if list, ok := value.(runtime.List); ok {
-			return list, nil
-		}
-	}
-
-	return runtime.NewArrayWith(value), nil
+	return ex.list(ctx, q)
 }
 
 func (c *Client) QueryOne(ctx context.Context, q runtime.Query) (runtime.Value, error) {
-	return runtime.DefaultQueryOne(ctx, q, c.Query)
+	ex, err := prepareQuery(ctx, c, q)
+	if err != nil {
+		return runtime.None, err
+	}
+
+	if ex.options.Paginate == nil {
+		return runtime.DefaultQueryOne(ctx, q, ex.one)
+	}
+
+	item, _, err := ex.pages().first(ctx)
+
+	return item, err
 }
 
 func (c *Client) QueryCount(ctx context.Context, q runtime.Query) (runtime.Int, error) {
-	return runtime.DefaultQueryCount(ctx, q, c.Query)
+	ex, err := prepareQuery(ctx, c, q)
+	if err != nil {
+		return 0, err
+	}
+
+	if ex.options.Paginate == nil {
+		return runtime.DefaultQueryCount(ctx, q, ex.list)
+	}
+
+	return ex.pages().count(ctx)
 }
 
 func (c *Client) QueryExists(ctx context.Context, q runtime.Query) (runtime.Boolean, error) {
-	return runtime.DefaultQueryExists(ctx, q, c.Query)
+	ex, err := prepareQuery(ctx, c, q)
+	if err != nil {
+		return runtime.False, err
+	}
+
+	if ex.options.Paginate == nil {
+		return runtime.DefaultQueryExists(ctx, q, ex.list)
+	}
+
+	_, found, err := ex.pages().first(ctx)
+
+	return runtime.NewBoolean(found), err
+}
+
+// Paginate returns a lazy iterable over the items of a paginated query.
+// Queries without a paginate option follow Link headers.
+func (c *Client) Paginate(ctx context.Context, q runtime.Query) (*Pages, error) {
+	ex, err := prepareQuery(ctx, c, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if ex.options.Paginate == nil {
+		switch {
+		case ex.graphql != nil:
+			return nil, OperationErrorf("PAGINATE", "GraphQL queries require relay pagination options")
+		case ex.jsonrpc != nil:
+			return nil, OperationErrorf("PAGINATE", "JSON-RPC queries do not support pagination")
+		}
+
+		ex.options.Paginate, _ = DecodePagination(ctx, runtime.True)
+	}
+
+	return ex.pages(), nil
 }
 
 func (c *Client) ResourceID() uint64 {
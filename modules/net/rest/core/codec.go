@@ -8,6 +8,7 @@ import (
 	"net/url"
 
 	"github.com/MontFerret/ferret/v2/pkg/runtime"
+	"github.com/MontFerret/ferret/v2/pkg/sdk"
 )
 
 func encodeRequestBody(ctx context.Context, value runtime.Value, encoding Encoding) ([]byte, string, error) {
@@ -43,12 +44,16 @@ func encodeRequestBody(ctx context.Context, value runtime.Value, encoding Encodi
 		}
 
 		return []byte(values.Encode()), "application/x-www-form-urlencoded", nil
+	case EncodingMultipart:
+		return encodeMultipartBody(ctx, value)
+	case EncodingXML, EncodingYAML, EncodingCSV, EncodingMsgpack:
+		return encodeFormatBody(ctx, value, encoding)
 	default:
 		return nil, "", fmt.Errorf("unsupported request encoding %q", encoding)
 	}
 }
 
-func decodeResponseBody(body []byte, encoding Encoding) (runtime.Value, error) {
+func decodeResponseBody(ctx context.Context, body []byte, encoding Encoding) (runtime.Value, error) {
 	switch encoding {
 	case EncodingJSON:
 		return decodeJSONBody(body)
@@ -61,6 +66,10 @@ func decodeResponseBody(body []byte, encoding Encoding) (runtime.Value, error) {
 		return runtime.NewBinary(out), nil
 	case EncodingForm:
 		return decodeFormBody(body)
+	case EncodingNDJSON, EncodingSSE:
+		return sdk.NewIterableValue(newRecords(body, encoding)), nil
+	case EncodingXML, EncodingYAML, EncodingCSV, EncodingMsgpack:
+		return decodeFormatBody(ctx, body, encoding)
 	default:
 		return runtime.None, fmt.Errorf("unsupported response encoding %q", encoding)
 	}
@@ -4,17 +4,22 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/MontFerret/ferret/v2/pkg/runtime"
 )
 
 type Config struct {
+	Auth             *Auth
 	BaseURL          string
+	GraphQLEndpoint  string
+	JSONRPCEndpoint  string
 	Headers          http.Header
 	RequestEncoding  Encoding
 	ResponseEncoding Encoding
 	ResponseMode     ResponseMode
 	ErrorMode        ErrorMode
+	Retry            RetryPolicy
 	Timeout          int64
 }
 
@@ -22,11 +27,13 @@ const clientConfigOwner = "NET::REST::CLIENT config"
 
 func DefaultConfig() Config {
 	return Config{
+		GraphQLEndpoint:  defaultGraphQLEndpoint,
 		Headers:          make(http.Header),
 		RequestEncoding:  EncodingJSON,
 		ResponseEncoding: EncodingJSON,
 		ResponseMode:     ResponseModeBody,
 		ErrorMode:        ErrorModeRaise,
+		Retry:            DefaultRetryPolicy(),
 	}
 }
 
@@ -43,77 +50,116 @@ func DecodeClientConfig(ctx context.Context, value runtime.Value) (Config, error
 		return cfg, fmt.Errorf("%s or a string", err.Error())
 	}
 
-	if baseURL, found, err := lookupString(ctx, obj, "baseUrl", clientConfigOwner); err != nil {
+	if err := decodeConfig(ctx, &cfg, obj, clientConfigOwner); err != nil {
 		return cfg, err
-	} else if found {
-		cfg.BaseURL = baseURL
 	}
 	if cfg.BaseURL == "" {
 		return cfg, fmt.Errorf("%s.baseUrl is required", clientConfigOwner)
 	}
 
+	return cfg, nil
+}
+
+// decodeConfig decodes the client settings of a config object. The base URL is optional here.
+func decodeConfig(ctx context.Context, cfg *Config, obj runtime.Map, owner string) error {
+	if baseURL, found, err := lookupString(ctx, obj, "baseUrl", owner); err != nil {
+		return err
+	} else if found {
+		cfg.BaseURL = baseURL
+	}
+
+	if endpoint, found, err := lookupString(ctx, obj, "graphqlEndpoint", owner); err != nil {
+		return err
+	} else if found {
+		cfg.GraphQLEndpoint = strings.TrimSpace(endpoint)
+	}
+
+	if endpoint, found, err := lookupString(ctx, obj, "jsonrpcEndpoint", owner); err != nil {
+		return err
+	} else if found {
+		cfg.JSONRPCEndpoint = strings.TrimSpace(endpoint)
+	}
+
 	if headers, found, err := lookupValue(ctx, obj, "headers"); err != nil {
-		return cfg, err
+		return err
 	} else if found {
-		cfg.Headers, err = decodeHeaders(ctx, headers, clientConfigOwner+".headers")
+		cfg.Headers, err = decodeHeaders(ctx, headers, owner+".headers")
 		if err != nil {
-			return cfg, err
+			return err
 		}
 	}
 
-	if encoding, found, err := lookupString(ctx, obj, "encoding", clientConfigOwner); err != nil {
-		return cfg, err
+	if encoding, found, err := lookupString(ctx, obj, "encoding", owner); err != nil {
+		return err
 	} else if found {
 		enc, err := parseEncoding(encoding)
 		if err != nil {
-			return cfg, fmt.Errorf("%s.encoding: %w", clientConfigOwner, err)
+			return fmt.Errorf("%s.encoding: %w", owner, err)
 		}
 
 		cfg.RequestEncoding = enc
 		cfg.ResponseEncoding = enc
 	}
 
-	if encoding, found, err := lookupString(ctx, obj, "requestEncoding", clientConfigOwner); err != nil {
-		return cfg, err
+	if encoding, found, err := lookupString(ctx, obj, "requestEncoding", owner); err != nil {
+		return err
 	} else if found {
-		cfg.RequestEncoding, err = parseEncoding(encoding)
+		cfg.RequestEncoding, err = parseRequestEncoding(encoding)
 		if err != nil {
-			return cfg, fmt.Errorf("%s.requestEncoding: %w", clientConfigOwner, err)
+			return fmt.Errorf("%s.requestEncoding: %w", owner, err)
 		}
 	}
 
-	if encoding, found, err := lookupString(ctx, obj, "responseEncoding", clientConfigOwner); err != nil {
-		return cfg, err
+	if encoding, found, err := lookupString(ctx, obj, "responseEncoding", owner); err != nil {
+		return err
 	} else if found {
-		cfg.ResponseEncoding, err = parseEncoding(encoding)
+		cfg.ResponseEncoding, err = parseResponseEncoding(encoding)
 		if err != nil {
-			return cfg, fmt.Errorf("%s.responseEncoding: %w", clientConfigOwner, err)
+			return fmt.Errorf("%s.responseEncoding: %w", owner, err)
 		}
 	}
 
-	if timeout, found, err := lookupDuration(ctx, obj, "timeout", clientConfigOwner); err != nil {
-		return cfg, err
+	if timeout, found, err := lookupDuration(ctx, obj, "timeout", owner); err != nil {
+		return err
 	} else if found {
 		cfg.Timeout = int64(timeout)
 	}
 
-	if response, found, err := lookupString(ctx, obj, "response", clientConfigOwner); err != nil {
-		return cfg, err
+	if response, found, err := lookupString(ctx, obj, "response", owner); err != nil {
+		return err
 	} else if found {
 		cfg.ResponseMode, err = parseResponseMode(response)
 		if err != nil {
-			return cfg, fmt.Errorf("%s.response: %w", clientConfigOwner, err)
+			return fmt.Errorf("%s.response: %w", owner, err)
 		}
 	}
 
-	if errorMode, found, err := lookupString(ctx, obj, "errorMode", clientConfigOwner); err != nil {
-		return cfg, err
+	if errorMode, found, err := lookupString(ctx, obj, "errorMode", owner); err != nil {
+		return err
 	} else if found {
 		cfg.ErrorMode, err = parseErrorMode(errorMode)
 		if err != nil {
-			return cfg, fmt.Errorf("%s.errorMode: %w", clientConfigOwner, err)
+			return fmt.Errorf("%s.errorMode: %w", owner, err)
 		}
 	}
 
-	return cfg, nil
+	if retry, found, err := lookupValue(ctx, obj, "retry"); err != nil {
+		return err
+	} else if found {
+		cfg.Retry, err = DecodeRetryPolicy(ctx, cfg.Retry, retry, owner+".retry")
+		if err != nil {
+			return err
+		}
+	}
+
+	if auth, found, err := lookupValue(ctx, obj, "auth"); err != nil {
+		return err
+	} else if found {
+		cfg.Auth, err = DecodeAuth(ctx, auth)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
@@ -48,6 +48,10 @@ func TestDecodeClientConfigObject(t *testing.T) {
 		"timeout":          runtime.NewInt(2500),
 		"response":         runtime.NewString("full"),
 		"errorMode":        runtime.NewString("response"),
+		"retry": object(t, map[string]runtime.Value{
+			"attempts": runtime.NewInt(4),
+			"methods":  runtime.NewArrayWith(runtime.NewString("get"), runtime.NewString("post")),
+		}),
 	}))
 	if err != nil {
 		t.Fatalf("unexpected config error: %v", err)
@@ -77,6 +81,12 @@ func TestDecodeClientConfigObject(t *testing.T) {
 	if cfg.ErrorMode != ErrorModeResponse {
 		t.Fatalf("expected response error mode, got %q", cfg.ErrorMode)
 	}
+	if cfg.Retry.MaxAttempts != 4 {
+		t.Fatalf("expected 4 retry attempts, got %d", cfg.Retry.MaxAttempts)
+	}
+	if got := cfg.Retry.Methods; len(got) != 2 || got[0] != "GET" || got[1] != "POST" {
+		t.Fatalf("unexpected retry methods: %v", got)
+	}
 }
 
 func TestDecodeClientConfigErrors(t *testing.T) {
@@ -92,12 +102,12 @@ func TestDecodeClientConfigErrors(t *testing.T) {
 
 	_, err = DecodeClientConfig(context.Background(), object(t, map[string]runtime.Value{
 		"baseUrl":  runtime.NewString("https://api.example.test"),
-		"encoding": runtime.NewString("xml"),
+		"encoding": runtime.NewString("protobuf"),
 	}))
 	if err == nil {
 		t.Fatal("expected unsupported encoding error")
 	}
-	if !strings.Contains(err.Error(), `NET::REST::CLIENT config.encoding: unsupported encoding "xml"`) {
+	if !strings.Contains(err.Error(), `NET::REST::CLIENT config.encoding: unsupported encoding "protobuf"`) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
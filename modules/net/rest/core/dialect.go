@@ -7,13 +7,22 @@ import (
 	"github.com/MontFerret/ferret/v2/pkg/runtime"
 )
 
-const dialectHTTP = "http"
+const (
+	dialectHTTP    = "http"
+	dialectGraphQL = "graphql"
+	dialectJSONRPC = "jsonrpc"
+)
+
+// parseQueryDialect returns the normalized USING dialect of a query. Queries without one use http.
+func parseQueryDialect(kind runtime.String) (string, error) {
+	dialect := strings.ToLower(strings.TrimSpace(kind.String()))
 
-func validateQueryDialect(kind runtime.String) error {
-	dialect := strings.TrimSpace(kind.String())
-	if dialect == "" || strings.EqualFold(dialect, dialectHTTP) {
-		return nil
+	switch dialect {
+	case "", dialectHTTP:
+		return dialectHTTP, nil
+	case dialectGraphQL, dialectJSONRPC:
+		return dialect, nil
 	}
 
-	return fmt.Errorf("unsupported dialect %q; expected %q", dialect, dialectHTTP)
+	return "", fmt.Errorf("unsupported dialect %q; expected %q, %q or %q", strings.TrimSpace(kind.String()), dialectHTTP, dialectGraphQL, dialectJSONRPC)
 }
@@ -8,10 +8,22 @@ import (
 type Encoding string
 
 const (
-	EncodingJSON  Encoding = "json"
-	EncodingText  Encoding = "text"
-	EncodingBytes Encoding = "bytes"
-	EncodingForm  Encoding = "form"
+	EncodingJSON    Encoding = "json"
+	EncodingText    Encoding = "text"
+	EncodingBytes   Encoding = "bytes"
+	EncodingForm    Encoding = "form"
+	EncodingXML     Encoding = "xml"
+	EncodingYAML    Encoding = "yaml"
+	EncodingCSV     Encoding = "csv"
+	EncodingMsgpack Encoding = "msgpack"
+	// EncodingMultipart is a request-only encoding for multipart/form-data bodies.
+	EncodingMultipart Encoding = "multipart"
+	// EncodingNDJSON is a response-only encoding for newline-delimited JSON records, also known as JSON Lines.
+	EncodingNDJSON Encoding = "ndjson"
+	// EncodingSSE is a response-only encoding for Server-Sent Events.
+	EncodingSSE Encoding = "sse"
+	// EncodingAuto is a response-only encoding that decodes by the Content-Type of the response.
+	EncodingAuto Encoding = "auto"
 )
 
 type ResponseMode string
@@ -28,15 +40,64 @@ const (
 	ErrorModeResponse ErrorMode = "response"
 )
 
+type Backoff string
+
+const (
+	BackoffConstant    Backoff = "constant"
+	BackoffExponential Backoff = "exponential"
+)
+
+type AuthKind string
+
+const (
+	AuthBasic  AuthKind = "basic"
+	AuthBearer AuthKind = "bearer"
+	AuthAPIKey AuthKind = "apiKey"
+	AuthOAuth2 AuthKind = "oauth2"
+)
+
+type PaginationKind string
+
+const (
+	PaginationLink   PaginationKind = "link"
+	PaginationCursor PaginationKind = "cursor"
+	PaginationPage   PaginationKind = "page"
+	PaginationOffset PaginationKind = "offset"
+	PaginationRelay  PaginationKind = "relay"
+)
+
 func parseEncoding(input string) (Encoding, error) {
 	switch enc := Encoding(strings.ToLower(strings.TrimSpace(input))); enc {
-	case EncodingJSON, EncodingText, EncodingBytes, EncodingForm:
+	case EncodingJSON, EncodingText, EncodingBytes, EncodingForm, EncodingXML, EncodingYAML, EncodingCSV, EncodingMsgpack:
 		return enc, nil
+	case "messagepack":
+		return EncodingMsgpack, nil
 	default:
 		return "", fmt.Errorf("unsupported encoding %q", input)
 	}
 }
 
+// parseRequestEncoding accepts the shared encodings and the request-only multipart encoding.
+func parseRequestEncoding(input string) (Encoding, error) {
+	if enc := Encoding(strings.ToLower(strings.TrimSpace(input))); enc == EncodingMultipart {
+		return enc, nil
+	}
+
+	return parseEncoding(input)
+}
+
+// parseResponseEncoding accepts the shared encodings, the response-only record encodings and auto.
+func parseResponseEncoding(input string) (Encoding, error) {
+	switch enc := Encoding(strings.ToLower(strings.TrimSpace(input))); enc {
+	case EncodingNDJSON, EncodingSSE, EncodingAuto:
+		return enc, nil
+	case "jsonl", "jsonlines":
+		return EncodingNDJSON, nil
+	}
+
+	return parseEncoding(input)
+}
+
 func parseResponseMode(input string) (ResponseMode, error) {
 	switch mode := ResponseMode(strings.ToLower(strings.TrimSpace(input))); mode {
 	case ResponseModeBody, ResponseModeFull:
@@ -54,3 +115,36 @@ func parseErrorMode(input string) (ErrorMode, error) {
 		return "", fmt.Errorf("unsupported error mode %q", input)
 	}
 }
+
+func parseBackoff(input string) (Backoff, error) {
+	switch backoff := Backoff(strings.ToLower(strings.TrimSpace(input))); backoff {
+	case BackoffConstant, BackoffExponential:
+		return backoff, nil
+	default:
+		return "", fmt.Errorf("unsupported backoff %q", input)
+	}
+}
+
+func parsePaginationKind(input string) (PaginationKind, error) {
+	switch kind := PaginationKind(strings.ToLower(strings.TrimSpace(input))); kind {
+	case PaginationLink, PaginationCursor, PaginationPage, PaginationOffset, PaginationRelay:
+		return kind, nil
+	default:
+		return "", fmt.Errorf("unsupported pagination type %q", input)
+	}
+}
+
+func parseAuthKind(input string) (AuthKind, error) {
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case string(AuthBasic):
+		return AuthBasic, nil
+	case string(AuthBearer):
+		return AuthBearer, nil
+	case "apikey", "api_key":
+		return AuthAPIKey, nil
+	case string(AuthOAuth2):
+		return AuthOAuth2, nil
+	default:
+		return "", fmt.Errorf("unsupported auth type %q", input)
+	}
+}
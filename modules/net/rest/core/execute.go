@@ -2,70 +2,257 @@ package core
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 
 	ferretnet "github.com/MontFerret/ferret/v2/pkg/net"
 	ferrethttp "github.com/MontFerret/ferret/v2/pkg/net/http"
 	"github.com/MontFerret/ferret/v2/pkg/runtime"
 )
 
-func executeQuery(ctx context.Context, client *Client, q runtime.Query) (runtime.Value, bool, error) {
-	if err := validateQueryDialect(q.Kind); err != nil {
-		return runtime.None, false, OperationError("QUERY", err)
+// exchange is a decoded query that is ready to be sent, once or once per page.
+// GraphQL queries keep their request to set the cursor variable of each page,
+// and OpenAPI queries keep their operation to validate responses.
+type exchange struct {
+	httpClient ferrethttp.Client
+	auth       *Auth
+	graphql    *graphQLRequest
+	jsonrpc    *jsonRPCRequest
+	openapi    *openAPIOperation
+	headers    http.Header
+	method     string
+	url        string
+	body       []byte
+	options    ExecutionOptions
+}
+
+func prepareQuery(ctx context.Context, client *Client, q runtime.Query) (*exchange, error) {
+	dialect, err := parseQueryDialect(q.Kind)
+	if err != nil {
+		return nil, OperationError("QUERY", err)
 	}
 
+	cfg := client.config
+
+	var operation *openAPIOperation
+
+	// OpenAPI clients query the operation whose operationId is the query expression
+	if dialect == dialectHTTP && client.api != nil {
+		if operation, err = client.api.operation(q.Expression.String()); err != nil {
+			return nil, OperationError("QUERY", err)
+		}
+
+		cfg = client.api.operationConfig(cfg, operation)
+	}
+
+	options, err := DecodeExecutionOptions(ctx, cfg, q.Options)
+	if err != nil {
+		return nil, OperationError("QUERY", err)
+	}
+
+	ex := &exchange{
+		auth:    cfg.Auth,
+		options: options,
+	}
+
+	switch {
+	case dialect == dialectGraphQL:
+		err = ex.prepareGraphQL(ctx, cfg, q)
+	case dialect == dialectJSONRPC:
+		err = ex.prepareJSONRPC(ctx, cfg, q)
+	case operation != nil:
+		err = ex.prepareOpenAPI(ctx, cfg, q, operation)
+	default:
+		err = ex.prepareHTTP(ctx, cfg, q)
+	}
+	if err != nil {
+		return nil, OperationError("QUERY", err)
+	}
+
+	if ex.httpClient, err = ferretnet.HTTPClientFrom(ctx); err != nil {
+		return nil, OperationError("QUERY", err)
+	}
+
+	return ex, nil
+}
+
+func (ex *exchange) prepareHTTP(ctx context.Context, cfg Config, q runtime.Query) error {
 	requestData, err := DecodeRequestData(ctx, q.Params)
 	if err != nil {
-		return runtime.None, false, OperationError("QUERY", err)
+		return err
+	}
+
+	return ex.prepareRequest(ctx, cfg, q.Expression.String(), requestData)
+}
+
+// prepareRequest expands the URI template of a request and encodes its body.
+func (ex *exchange) prepareRequest(ctx context.Context, cfg Config, template string, requestData RequestData) error {
+	if p := ex.options.Paginate; p != nil && p.Kind == PaginationRelay {
+		return fmt.Errorf("relay pagination requires USING %s", dialectGraphQL)
+	}
+
+	if ex.options.Paginate != nil && isRecordEncoding(ex.options.ResponseEncoding) {
+		return fmt.Errorf("pagination does not support the %q response encoding", ex.options.ResponseEncoding)
 	}
 
-	options, err := DecodeExecutionOptions(ctx, client.config, q.Options)
+	expression, err := expandURITemplate(ctx, template, requestData.Path)
 	if err != nil {
-		return runtime.None, false, OperationError("QUERY", err)
+		return err
 	}
 
-	requestURL, err := resolveRequestURL(ctx, client.config.BaseURL, q.Expression.String(), requestData.Query)
+	requestURL, err := resolveRequestURL(ctx, cfg.BaseURL, expression, requestData.Query)
 	if err != nil {
-		return runtime.None, false, OperationError("QUERY", err)
+		return err
 	}
 
-	body, contentType, err := encodeRequestBody(ctx, requestData.Body, options.RequestEncoding)
+	body, contentType, err := encodeRequestBody(ctx, requestData.Body, ex.options.RequestEncoding)
 	if err != nil {
-		return runtime.None, false, OperationError("QUERY", err)
+		return err
+	}
+
+	headers := mergeHeaders(cfg.Headers, requestData.Headers)
+	// a multipart body is only readable with the boundary of its own content type
+	if contentType != "" && (!hasHeader(headers, "Content-Type") || ex.options.RequestEncoding == EncodingMultipart) {
+		headers.Set("Content-Type", contentType)
+	}
+
+	if isRecordEncoding(ex.options.ResponseEncoding) && !hasHeader(headers, "Accept") {
+		headers.Set("Accept", recordsAccept(ex.options.ResponseEncoding))
+	}
+
+	ex.headers = headers
+	ex.method = requestData.Method
+	ex.url = requestURL
+	ex.body = body
+
+	return nil
+}
+
+// list returns the items of a query: the pages of a paginated query or the flattened response body.
+// Records are all decoded.
+func (ex *exchange) list(ctx context.Context, _ runtime.Query) (runtime.List, error) {
+	if ex.options.Paginate != nil {
+		return ex.pages().collect(ctx)
 	}
 
-	httpClient, err := ferretnet.HTTPClientFrom(ctx)
+	value, flatten, err := ex.execute(ctx)
 	if err != nil {
-		return runtime.None, false, OperationError("QUERY", err)
+		return nil, err
+	}
+
+	if flatten {
+		switch items := value.(type) {
+		case runtime.List:
+			return items, nil
+		case runtime.Iterable:
+			return collectItems(ctx, items)
+		}
 	}
 
-	requestCtx := ctx
-	cancel := func() {}
+	return runtime.NewArrayWith(value), nil
+}
 
-	if options.Timeout > 0 {
-		requestCtx, cancel = context.WithTimeout(ctx, options.Timeout)
+// one returns the items of QUERY ONE, which keeps the records of a response as its single item, so they are decoded as a loop consumes them.
+func (ex *exchange) one(ctx context.Context, q runtime.Query) (runtime.List, error) {
+	if !ex.streamsRecords() {
+		return ex.list(ctx, q)
 	}
 
-	defer cancel()
+	value, _, err := ex.execute(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	headers := mergeHeaders(client.config.Headers, requestData.Headers)
-	if contentType != "" && !hasHeader(headers, "Content-Type") {
-		headers.Set("Content-Type", contentType)
+	return runtime.NewArrayWith(value), nil
+}
+
+// streamsRecords reports whether the HTTP response of a query is decoded into records.
+func (ex *exchange) streamsRecords() bool {
+	return ex.graphql == nil && ex.jsonrpc == nil && isRecordEncoding(ex.options.ResponseEncoding)
+}
+
+func collectItems(ctx context.Context, items runtime.Iterable) (runtime.List, error) {
+	iter, err := items.Iterate(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	resp, err := httpClient.Do(requestCtx, &ferrethttp.Request{
-		Method:  requestData.Method,
-		URL:     requestURL,
-		Headers: ferrethttp.Headers(headers),
-		Body:    body,
-	})
+	out := runtime.NewArray(0)
+
+	for {
+		item, _, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err := out.Append(ctx, item); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (ex *exchange) pages() *Pages {
+	return &Pages{
+		exchange:   ex,
+		pagination: *ex.options.Paginate,
+	}
+}
+
+func (ex *exchange) execute(ctx context.Context) (runtime.Value, bool, error) {
+	resp, attempts, err := ex.send(ctx, ex.url, ex.body)
 	if err != nil {
 		return runtime.None, false, OperationError("QUERY", err)
 	}
 
-	value, flatten, err := decodeHTTPResponse(ctx, requestURL, resp, options)
+	var value runtime.Value
+	var flatten bool
+
+	switch {
+	case ex.graphql != nil:
+		value, _, err = decodeGraphQLResponse(ctx, ex.url, resp, attempts, ex.options)
+	case ex.jsonrpc != nil:
+		value, flatten, err = ex.jsonrpc.decodeResponse(ctx, ex.url, resp, attempts, ex.options)
+	default:
+		value, flatten, err = ex.decodeHTTPResponse(ctx, ex.url, resp, attempts)
+	}
 	if err != nil {
 		return runtime.None, false, OperationError("QUERY", err)
 	}
 
 	return value, flatten, nil
 }
+
+func (ex *exchange) send(ctx context.Context, requestURL string, body []byte) (*ferrethttp.Response, int, error) {
+	resp, attempts, accessToken, err := ex.sendAuthorized(ctx, requestURL, body)
+	if err != nil || accessToken == "" || resp.StatusCode != http.StatusUnauthorized {
+		return resp, attempts, err
+	}
+
+	// the server rejected a cached OAuth2 token, retry once with a new one
+	ex.auth.oauth2.invalidate(accessToken)
+
+	resp, retried, _, err := ex.sendAuthorized(ctx, requestURL, body)
+
+	return resp, attempts + retried, err
+}
+
+func (ex *exchange) sendAuthorized(ctx context.Context, requestURL string, body []byte) (*ferrethttp.Response, int, string, error) {
+	headers, requestURL, accessToken, err := ex.auth.apply(ctx, ex.httpClient, ex.headers, requestURL)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	resp, attempts, err := doWithRetry(ctx, ex.httpClient, &ferrethttp.Request{
+		Method:  ex.method,
+		URL:     requestURL,
+		Headers: ferrethttp.Headers(headers),
+		Body:    body,
+	}, ex.options)
+
+	return resp, attempts, accessToken, err
+}
@@ -2,26 +2,34 @@ package core
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/MontFerret/ferret/v2/pkg/runtime"
 )
 
 type ExecutionOptions struct {
+	Paginate         *Pagination
+	GraphQLEndpoint  string
+	JSONRPCEndpoint  string
 	ResponseMode     ResponseMode
 	RequestEncoding  Encoding
 	ResponseEncoding Encoding
 	ErrorMode        ErrorMode
+	Retry            RetryPolicy
 	Timeout          time.Duration
 }
 
 func DefaultExecutionOptions(cfg Config) ExecutionOptions {
 	return ExecutionOptions{
 		Timeout:          time.Duration(cfg.Timeout),
+		GraphQLEndpoint:  cfg.GraphQLEndpoint,
+		JSONRPCEndpoint:  cfg.JSONRPCEndpoint,
 		ResponseMode:     cfg.ResponseMode,
 		RequestEncoding:  cfg.RequestEncoding,
 		ResponseEncoding: cfg.ResponseEncoding,
 		ErrorMode:        cfg.ErrorMode,
+		Retry:            cfg.Retry,
 	}
 }
 
@@ -55,7 +63,7 @@ func DecodeExecutionOptions(ctx context.Context, cfg Config, value runtime.Value
 	if encoding, found, err := lookupString(ctx, obj, "requestEncoding", "HTTP query OPTIONS"); err != nil {
 		return opts, err
 	} else if found {
-		opts.RequestEncoding, err = parseEncoding(encoding)
+		opts.RequestEncoding, err = parseRequestEncoding(encoding)
 		if err != nil {
 			return opts, OperationError("OPTIONS", err)
 		}
@@ -64,7 +72,7 @@ func DecodeExecutionOptions(ctx context.Context, cfg Config, value runtime.Value
 	if encoding, found, err := lookupString(ctx, obj, "responseEncoding", "HTTP query OPTIONS"); err != nil {
 		return opts, err
 	} else if found {
-		opts.ResponseEncoding, err = parseEncoding(encoding)
+		opts.ResponseEncoding, err = parseResponseEncoding(encoding)
 		if err != nil {
 			return opts, OperationError("OPTIONS", err)
 		}
@@ -79,5 +87,35 @@ func DecodeExecutionOptions(ctx context.Context, cfg Config, value runtime.Value
 		}
 	}
 
+	if endpoint, found, err := lookupString(ctx, obj, "graphqlEndpoint", "HTTP query OPTIONS"); err != nil {
+		return opts, err
+	} else if found {
+		opts.GraphQLEndpoint = strings.TrimSpace(endpoint)
+	}
+
+	if endpoint, found, err := lookupString(ctx, obj, "jsonrpcEndpoint", "HTTP query OPTIONS"); err != nil {
+		return opts, err
+	} else if found {
+		opts.JSONRPCEndpoint = strings.TrimSpace(endpoint)
+	}
+
+	if retry, found, err := lookupValue(ctx, obj, "retry"); err != nil {
+		return opts, err
+	} else if found {
+		opts.Retry, err = DecodeRetryPolicy(ctx, opts.Retry, retry, "HTTP query OPTIONS.retry")
+		if err != nil {
+			return opts, OperationError("OPTIONS", err)
+		}
+	}
+
+	if paginate, found, err := lookupValue(ctx, obj, "paginate"); err != nil {
+		return opts, err
+	} else if found {
+		opts.Paginate, err = DecodePagination(ctx, paginate)
+		if err != nil {
+			return opts, OperationError("OPTIONS", err)
+		}
+	}
+
 	return opts, nil
 }
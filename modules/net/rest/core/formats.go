@@ -0,0 +1,219 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	csvcore "github.com/MontFerret/contrib/modules/csv/core"
+	xmlcore "github.com/MontFerret/contrib/modules/xml/core"
+	yamlcore "github.com/MontFerret/contrib/modules/yaml/core"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+// encodeFormatBody encodes a request body with the XML, YAML, CSV or MessagePack codec.
+// XML bodies are XML::DECODE documents or elements, or strings that are sent unchanged.
+func encodeFormatBody(ctx context.Context, value runtime.Value, encoding Encoding) ([]byte, string, error) {
+	switch encoding {
+	case EncodingXML:
+		if str, ok := value.(runtime.String); ok {
+			return []byte(str.String()), "application/xml; charset=utf-8", nil
+		}
+
+		text, err := xmlcore.Encode(ctx, value)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return []byte(text), "application/xml; charset=utf-8", nil
+	case EncodingYAML:
+		text, err := yamlcore.Encode(ctx, value)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return []byte(text), "application/yaml; charset=utf-8", nil
+	case EncodingCSV:
+		result, err := csvcore.Encode(ctx, value, csvcore.DefaultOptions())
+		if err != nil {
+			return nil, "", err
+		}
+
+		return []byte(result.Text), "text/csv; charset=utf-8", nil
+	case EncodingMsgpack:
+		converted, err := runtimeToNative(ctx, value)
+		if err != nil {
+			return nil, "", err
+		}
+
+		data, err := msgpack.Marshal(converted)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return data, "application/msgpack", nil
+	default:
+		return nil, "", fmt.Errorf("unsupported request encoding %q", encoding)
+	}
+}
+
+// decodeFormatBody decodes a response body with the XML, YAML, CSV or MessagePack codec.
+// Empty bodies decode to NONE, and CSV bodies decode to an array of objects keyed by the header row.
+func decodeFormatBody(ctx context.Context, body []byte, encoding Encoding) (runtime.Value, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return runtime.None, nil
+	}
+
+	switch encoding {
+	case EncodingXML:
+		return xmlcore.Decode(ctx, runtime.NewString(string(body)))
+	case EncodingYAML:
+		return yamlcore.Decode(ctx, runtime.NewString(string(body)))
+	case EncodingCSV:
+		return csvcore.Decode(ctx, runtime.NewString(string(body)), csvcore.DefaultOptions())
+	case EncodingMsgpack:
+		var decoded any
+		if err := msgpack.Unmarshal(body, &decoded); err != nil {
+			return runtime.None, err
+		}
+
+		return msgpackToValue(decoded)
+	default:
+		return runtime.None, fmt.Errorf("unsupported response encoding %q", encoding)
+	}
+}
+
+func msgpackToValue(input any) (runtime.Value, error) {
+	switch value := input.(type) {
+	case nil:
+		return runtime.None, nil
+	case bool:
+		return runtime.NewBoolean(value), nil
+	case string:
+		return runtime.NewString(value), nil
+	case []byte:
+		return runtime.NewBinary(value), nil
+	case int8:
+		return runtime.NewInt64(int64(value)), nil
+	case int16:
+		return runtime.NewInt64(int64(value)), nil
+	case int32:
+		return runtime.NewInt64(int64(value)), nil
+	case int64:
+		return runtime.NewInt64(value), nil
+	case uint8:
+		return runtime.NewInt64(int64(value)), nil
+	case uint16:
+		return runtime.NewInt64(int64(value)), nil
+	case uint32:
+		return runtime.NewInt64(int64(value)), nil
+	case uint64:
+		if value > math.MaxInt64 {
+			return runtime.NewFloat(float64(value)), nil
+		}
+
+		return runtime.NewInt64(int64(value)), nil
+	case float32:
+		return runtime.NewFloat(float64(value)), nil
+	case float64:
+		return runtime.NewFloat(value), nil
+	case time.Time:
+		return runtime.NewDateTime(value), nil
+	case []any:
+		items := make([]runtime.Value, 0, len(value))
+		for idx, item := range value {
+			converted, err := msgpackToValue(item)
+			if err != nil {
+				return runtime.None, fmt.Errorf("at index %d: %w", idx, err)
+			}
+
+			items = append(items, converted)
+		}
+
+		return runtime.NewArrayWith(items...), nil
+	case map[string]any:
+		props := make(map[string]runtime.Value, len(value))
+		for key, item := range value {
+			converted, err := msgpackToValue(item)
+			if err != nil {
+				return runtime.None, fmt.Errorf("at key %q: %w", key, err)
+			}
+
+			props[key] = converted
+		}
+
+		return runtime.NewObjectWith(props), nil
+	case map[any]any:
+		// maps with non-string keys are keyed by the string form of their keys
+		props := make(map[string]runtime.Value, len(value))
+		for key, item := range value {
+			converted, err := msgpackToValue(item)
+			if err != nil {
+				return runtime.None, fmt.Errorf("at key %v: %w", key, err)
+			}
+
+			props[fmt.Sprint(key)] = converted
+		}
+
+		return runtime.NewObjectWith(props), nil
+	default:
+		return runtime.None, fmt.Errorf("unsupported MessagePack value %T", input)
+	}
+}
+
+// responseEncodingFor resolves the auto encoding from the Content-Type of a response.
+// Unknown textual types decode as text and anything else as bytes.
+func responseEncodingFor(encoding Encoding, headers http.Header) Encoding {
+	if encoding != EncodingAuto {
+		return encoding
+	}
+
+	mediaType, _, err := mime.ParseMediaType(headers.Get("Content-Type"))
+	if err != nil {
+		return EncodingBytes
+	}
+
+	return encodingForMediaType(mediaType)
+}
+
+// encodingForMediaType returns the codec of a parsed media type.
+func encodingForMediaType(mediaType string) Encoding {
+	switch mediaType {
+	case "application/json":
+		return EncodingJSON
+	case "application/xml", "text/xml":
+		return EncodingXML
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return EncodingYAML
+	case "text/csv":
+		return EncodingCSV
+	case "application/msgpack", "application/x-msgpack", "application/vnd.msgpack":
+		return EncodingMsgpack
+	case "application/x-ndjson", "application/jsonl":
+		return EncodingNDJSON
+	case "text/event-stream":
+		return EncodingSSE
+	case "application/x-www-form-urlencoded":
+		return EncodingForm
+	}
+
+	switch {
+	case strings.HasSuffix(mediaType, "+json"):
+		return EncodingJSON
+	case strings.HasSuffix(mediaType, "+xml"):
+		return EncodingXML
+	case strings.HasSuffix(mediaType, "+yaml"):
+		return EncodingYAML
+	case strings.HasPrefix(mediaType, "text/"):
+		return EncodingText
+	default:
+		return EncodingBytes
+	}
+}
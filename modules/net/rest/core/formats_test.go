@@ -0,0 +1,200 @@
+package core
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+func TestClientXMLEncoding(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Content-Type"); !strings.HasPrefix(got, "application/xml") {
+			t.Fatalf("expected XML content type, got %q", got)
+		}
+		if body := readBody(t, r); !strings.Contains(body, "<GetUser>") {
+			t.Fatalf("expected the XML string body unchanged, got %q", body)
+		}
+
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		_, _ = w.Write([]byte(`<User id="1"><Name>Ada</Name></User>`))
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+	cfg.RequestEncoding = EncodingXML
+	cfg.ResponseEncoding = EncodingAuto
+
+	out, err := NewClient(cfg).QueryOne(ctx, runtime.Query{
+		Expression: runtime.NewString("/soap"),
+		Params: object(t, map[string]runtime.Value{
+			"body": runtime.NewString("<Envelope><GetUser></GetUser></Envelope>"),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+
+	root := field(t, out, "root")
+	if got := field(t, root, "name"); got != runtime.NewString("User") {
+		t.Fatalf("expected the User root element, got %s", got.String())
+	}
+	if got := field(t, field(t, root, "attrs"), "id"); got != runtime.NewString("1") {
+		t.Fatalf("expected id attribute, got %s", got.String())
+	}
+}
+
+func TestClientCSVAndYAMLEncodings(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/export":
+			w.Header().Set("Content-Type", "text/csv")
+			_, _ = w.Write([]byte("id,name\n1,Ada\n2,Grace\n"))
+		case "/echo":
+			w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+			_, _ = io.Copy(w, r.Body)
+		}
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+	client := NewClient(cfg)
+
+	rows, err := client.Query(ctx, runtime.Query{
+		Expression: runtime.NewString("/export"),
+		Options: object(t, map[string]runtime.Value{
+			"responseEncoding": runtime.NewString("csv"),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+
+	names := make([]string, 0, 2)
+	iterateList(t, ctx, rows, func(row runtime.Value) {
+		names = append(names, field(t, row, "name").String())
+	})
+	if strings.Join(names, ",") != "Ada,Grace" {
+		t.Fatalf("expected CSV rows as query results, got %v", names)
+	}
+
+	out, err := client.QueryOne(ctx, runtime.Query{
+		Expression: runtime.NewString("/echo"),
+		Params: object(t, map[string]runtime.Value{
+			"body": object(t, map[string]runtime.Value{
+				"name": runtime.NewString("Ada"),
+				"tags": runtime.NewArrayWith(runtime.NewString("math")),
+			}),
+		}),
+		Options: object(t, map[string]runtime.Value{
+			"requestEncoding":  runtime.NewString("yaml"),
+			"responseEncoding": runtime.NewString("auto"),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+	if got := field(t, out, "name"); got != runtime.NewString("Ada") {
+		t.Fatalf("expected the YAML body to round-trip, got %s", out.String())
+	}
+}
+
+func TestClientMsgpackEncoding(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var decoded map[string]any
+		if err := msgpack.NewDecoder(r.Body).Decode(&decoded); err != nil {
+			t.Fatalf("failed to decode MessagePack body: %v", err)
+		}
+		if decoded["name"] != "Ada" {
+			t.Fatalf("unexpected MessagePack body: %v", decoded)
+		}
+
+		data, err := msgpack.Marshal(map[string]any{
+			"id":    int8(7),
+			"ratio": 0.5,
+			"raw":   []byte{1, 2},
+			"tags":  []string{"a", "b"},
+		})
+		if err != nil {
+			t.Fatalf("failed to encode MessagePack response: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/msgpack")
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+	cfg.RequestEncoding = EncodingMsgpack
+	cfg.ResponseEncoding = EncodingAuto
+
+	out, err := NewClient(cfg).QueryOne(ctx, runtime.Query{
+		Expression: runtime.NewString("/users"),
+		Params: object(t, map[string]runtime.Value{
+			"body": object(t, map[string]runtime.Value{
+				"name": runtime.NewString("Ada"),
+			}),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+
+	if got := field(t, out, "id"); got != runtime.NewInt(7) {
+		t.Fatalf("expected integer id, got %s", got.String())
+	}
+	if got := field(t, out, "ratio"); got != runtime.NewFloat(0.5) {
+		t.Fatalf("expected float ratio, got %s", got.String())
+	}
+	if _, ok := field(t, out, "raw").(runtime.Binary); !ok {
+		t.Fatalf("expected binary raw field, got %T", field(t, out, "raw"))
+	}
+}
+
+func TestResponseEncodingFor(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]Encoding{
+		"application/json; charset=utf-8": EncodingJSON,
+		"application/problem+json":        EncodingJSON,
+		"application/soap+xml":            EncodingXML,
+		"application/x-yaml":              EncodingYAML,
+		"text/csv":                        EncodingCSV,
+		"application/vnd.msgpack":         EncodingMsgpack,
+		"application/x-ndjson":            EncodingNDJSON,
+		"text/event-stream":               EncodingSSE,
+		"text/html":                       EncodingText,
+		"image/png":                       EncodingBytes,
+		"":                                EncodingBytes,
+	}
+
+	for contentType, want := range cases {
+		headers := make(http.Header)
+		headers.Set("Content-Type", contentType)
+
+		if got := responseEncodingFor(EncodingAuto, headers); got != want {
+			t.Fatalf("expected %q to decode as %s, got %s", contentType, want, got)
+		}
+	}
+
+	if got := responseEncodingFor(EncodingText, http.Header{"Content-Type": {"application/json"}}); got != EncodingText {
+		t.Fatalf("expected explicit encodings to win, got %s", got)
+	}
+}
@@ -0,0 +1,235 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+
+	ferrethttp "github.com/MontFerret/ferret/v2/pkg/net/http"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+type (
+	// GraphQLError is returned when a GraphQL response reports errors and the error mode is raise.
+	// It unwraps to runtime.ErrUnexpected.
+	GraphQLError struct {
+		Errors []GraphQLErrorEntry
+	}
+
+	// GraphQLErrorEntry is a single entry of the errors array of a GraphQL response.
+	GraphQLErrorEntry struct {
+		Extensions map[string]any `json:"extensions,omitempty"`
+		Message    string         `json:"message"`
+		Path       []any          `json:"path,omitempty"`
+	}
+
+	// graphQLRequest is the document and the WITH values of a USING graphql query.
+	graphQLRequest struct {
+		variables     map[string]any
+		document      string
+		operationName string
+	}
+)
+
+const (
+	graphQLOwner = "GraphQL query WITH"
+
+	defaultGraphQLEndpoint = "/graphql"
+)
+
+func (e *GraphQLError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+
+	for _, entry := range e.Errors {
+		message := entry.Message
+		if len(entry.Path) > 0 {
+			path := make([]string, 0, len(entry.Path))
+			for _, segment := range entry.Path {
+				path = append(path, fmt.Sprint(segment))
+			}
+
+			message = fmt.Sprintf("%s (at %s)", message, strings.Join(path, "."))
+		}
+
+		messages = append(messages, message)
+	}
+
+	return "GraphQL errors: " + strings.Join(messages, "; ")
+}
+
+func (e *GraphQLError) Unwrap() error {
+	return runtime.ErrUnexpected
+}
+
+// prepareGraphQL posts the query expression as a GraphQL document to the configured endpoint.
+func (ex *exchange) prepareGraphQL(ctx context.Context, cfg Config, q runtime.Query) error {
+	if p := ex.options.Paginate; p != nil && p.Kind != PaginationRelay {
+		return fmt.Errorf("GraphQL queries only support relay pagination, got %q", p.Kind)
+	}
+
+	request, headers, err := decodeGraphQLRequest(ctx, q.Expression.String(), q.Params)
+	if err != nil {
+		return err
+	}
+
+	endpoint := ex.options.GraphQLEndpoint
+	if endpoint == "" {
+		endpoint = defaultGraphQLEndpoint
+	}
+
+	requestURL, err := resolveRequestURL(ctx, cfg.BaseURL, endpoint, runtime.None)
+	if err != nil {
+		return err
+	}
+
+	body, err := request.encode("", "")
+	if err != nil {
+		return err
+	}
+
+	ex.graphql = request
+	ex.headers = mergeHeaders(cfg.Headers, headers)
+	ex.method = http.MethodPost
+	ex.url = requestURL
+	ex.body = body
+
+	// GraphQL always speaks JSON, whatever the client encodings are
+	ex.headers.Set("Content-Type", "application/json")
+	if !hasHeader(ex.headers, "Accept") {
+		ex.headers.Set("Accept", "application/json")
+	}
+
+	return nil
+}
+
+// decodeGraphQLRequest decodes the variables, operationName and headers of WITH.
+func decodeGraphQLRequest(ctx context.Context, document string, value runtime.Value) (*graphQLRequest, http.Header, error) {
+	request := &graphQLRequest{document: strings.TrimSpace(document)}
+	headers := make(http.Header)
+
+	if request.document == "" {
+		return nil, nil, errors.New("GraphQL document must not be empty")
+	}
+
+	if runtime.TypeNone.Is(value) {
+		return request, headers, nil
+	}
+
+	obj, err := requireMap(ctx, value, graphQLOwner)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if variables, found, err := lookupValue(ctx, obj, "variables"); err != nil {
+		return nil, nil, err
+	} else if found && !runtime.TypeNone.Is(variables) {
+		vars, err := requireMap(ctx, variables, graphQLOwner+".variables")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if request.variables, err = mapToNative(ctx, vars); err != nil {
+			return nil, nil, fmt.Errorf("%s.variables: %w", graphQLOwner, err)
+		}
+	}
+
+	if operationName, found, err := lookupString(ctx, obj, "operationName", graphQLOwner); err != nil {
+		return nil, nil, err
+	} else if found {
+		request.operationName = strings.TrimSpace(operationName)
+	}
+
+	if value, found, err := lookupValue(ctx, obj, "headers"); err != nil {
+		return nil, nil, err
+	} else if found {
+		if headers, err = decodeHeaders(ctx, value, graphQLOwner+".headers"); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return request, headers, nil
+}
+
+// encode returns the JSON request body. A non-empty cursor is set as the given variable.
+func (r *graphQLRequest) encode(cursorVariable, cursor string) ([]byte, error) {
+	variables := r.variables
+
+	if cursor != "" {
+		variables = maps.Clone(r.variables)
+		if variables == nil {
+			variables = make(map[string]any, 1)
+		}
+
+		variables[cursorVariable] = cursor
+	}
+
+	return json.Marshal(struct {
+		Variables     map[string]any `json:"variables,omitempty"`
+		Query         string         `json:"query"`
+		OperationName string         `json:"operationName,omitempty"`
+	}{
+		Query:         r.document,
+		Variables:     variables,
+		OperationName: r.operationName,
+	})
+}
+
+// decodeGraphQLResponse returns the data of a GraphQL response.
+// Failed responses are raised or, like full responses, returned with the whole GraphQL response as body.
+// The boolean reports whether the returned value is the data.
+func decodeGraphQLResponse(ctx context.Context, requestURL string, resp *ferrethttp.Response, attempts int, opts ExecutionOptions) (runtime.Value, bool, error) {
+	ok := resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusBadRequest
+
+	decoded, err := decodeJSONBody(resp.Body)
+	if err != nil {
+		if !ok && opts.ErrorMode == ErrorModeRaise {
+			return runtime.None, false, fmt.Errorf("unexpected status %s", resp.Status)
+		}
+
+		return runtime.None, false, fmt.Errorf("decode response body: %w", err)
+	}
+
+	graphQLErr := graphQLErrors(resp.Body)
+	failed := !ok || graphQLErr != nil
+
+	if failed && opts.ErrorMode == ErrorModeRaise {
+		if graphQLErr != nil {
+			return runtime.None, false, graphQLErr
+		}
+
+		return runtime.None, false, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	if failed || opts.ResponseMode == ResponseModeFull {
+		value, err := buildFullResponse(ctx, requestURL, resp, attempts, decoded)
+		if err != nil {
+			return runtime.None, false, err
+		}
+
+		return value, false, nil
+	}
+
+	data, found, err := lookupPath(ctx, decoded, "data")
+	if err != nil || !found {
+		return runtime.None, true, err
+	}
+
+	return data, true, nil
+}
+
+// graphQLErrors returns the errors of a GraphQL response, or nil when it has none.
+func graphQLErrors(body []byte) *GraphQLError {
+	var response struct {
+		Errors []GraphQLErrorEntry `json:"errors"`
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil || len(response.Errors) == 0 {
+		return nil
+	}
+
+	return &GraphQLError{Errors: response.Errors}
+}
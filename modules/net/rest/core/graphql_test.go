@@ -0,0 +1,271 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+type graphQLTestRequest struct {
+	Variables     map[string]any `json:"variables"`
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+}
+
+func TestClientGraphQLQuery(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/graphql" {
+			t.Fatalf("expected POST /api/graphql, got %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Content-Type"); got != "application/json" {
+			t.Fatalf("expected JSON content type, got %q", got)
+		}
+		if got := r.Header.Get("X-Trace"); got != "1" {
+			t.Fatalf("expected request header, got %q", got)
+		}
+
+		var req graphQLTestRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode GraphQL request: %v", err)
+		}
+		if !strings.Contains(req.Query, "user(login: $login)") || req.OperationName != "User" || req.Variables["login"] != "ada" {
+			t.Fatalf("unexpected GraphQL request: %+v", req)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"user": map[string]any{"name": "Ada"}},
+		})
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg, err := DecodeClientConfig(ctx, object(t, map[string]runtime.Value{
+		"baseUrl":         runtime.NewString(server.URL),
+		"graphqlEndpoint": runtime.NewString("/api/graphql"),
+		"encoding":        runtime.NewString("text"),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected config error: %v", err)
+	}
+
+	out, err := NewClient(cfg).QueryOne(ctx, runtime.Query{
+		Kind:       runtime.NewString("graphql"),
+		Expression: runtime.NewString("query User($login: String!) { user(login: $login) { name } }"),
+		Params: object(t, map[string]runtime.Value{
+			"operationName": runtime.NewString("User"),
+			"variables": object(t, map[string]runtime.Value{
+				"login": runtime.NewString("ada"),
+			}),
+			"headers": object(t, map[string]runtime.Value{
+				"X-Trace": runtime.NewString("1"),
+			}),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+	if got := field(t, field(t, out, "user"), "name"); got != runtime.NewString("Ada") {
+		t.Fatalf("expected unwrapped data, got %s", out.String())
+	}
+}
+
+func TestClientGraphQLErrors(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"user": nil},
+			"errors": []map[string]any{{
+				"message":    "user not found",
+				"path":       []any{"user"},
+				"extensions": map[string]any{"code": "NOT_FOUND"},
+			}},
+		})
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+	client := NewClient(cfg)
+
+	query := runtime.Query{
+		Kind:       runtime.NewString("graphql"),
+		Expression: runtime.NewString("{ user(login: \"nobody\") { name } }"),
+	}
+
+	_, err := client.QueryOne(ctx, query)
+
+	var graphQLErr *GraphQLError
+	if !errors.As(err, &graphQLErr) {
+		t.Fatalf("expected a GraphQL error, got %v", err)
+	}
+	if !errors.Is(err, runtime.ErrUnexpected) {
+		t.Fatalf("expected GraphQL errors to be unexpected errors, got %v", err)
+	}
+	if len(graphQLErr.Errors) != 1 || graphQLErr.Errors[0].Extensions["code"] != "NOT_FOUND" {
+		t.Fatalf("unexpected GraphQL error entries: %+v", graphQLErr.Errors)
+	}
+	if !strings.Contains(err.Error(), "user not found (at user)") {
+		t.Fatalf("unexpected error message: %v", err)
+	}
+
+	query.Options = object(t, map[string]runtime.Value{
+		"errorMode": runtime.NewString("response"),
+	})
+
+	out, err := client.QueryOne(ctx, query)
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+
+	errorsValue, ok := field(t, field(t, out, "body"), "errors").(runtime.List)
+	if !ok {
+		t.Fatalf("expected the full GraphQL response, got %s", out.String())
+	}
+	if length, _ := errorsValue.Length(ctx); length != 1 {
+		t.Fatalf("expected 1 GraphQL error, got %d", length)
+	}
+}
+
+func TestClientGraphQLRelayPagination(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		var req graphQLTestRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode GraphQL request: %v", err)
+		}
+		if req.Variables["first"] != float64(2) {
+			t.Fatalf("expected the first variable on every page, got %v", req.Variables)
+		}
+
+		var edges []map[string]any
+		var pageInfo map[string]any
+
+		switch req.Variables["after"] {
+		case nil:
+			edges = []map[string]any{{"node": map[string]any{"id": 1}}, {"node": map[string]any{"id": 2}}}
+			pageInfo = map[string]any{"hasNextPage": true, "endCursor": "c2"}
+		case "c2":
+			edges = []map[string]any{{"node": map[string]any{"id": 3}}}
+			pageInfo = map[string]any{"hasNextPage": false, "endCursor": "c3"}
+		default:
+			t.Fatalf("unexpected cursor %v", req.Variables["after"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{
+				"repository": map[string]any{
+					"issues": map[string]any{"edges": edges, "pageInfo": pageInfo},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+
+	out, err := NewClient(cfg).Query(ctx, runtime.Query{
+		Kind:       runtime.NewString("graphql"),
+		Expression: runtime.NewString("query($first: Int, $after: String) { repository { issues(first: $first, after: $after) { edges { node { id } } pageInfo { hasNextPage endCursor } } } }"),
+		Params: object(t, map[string]runtime.Value{
+			"variables": object(t, map[string]runtime.Value{
+				"first": runtime.NewInt(2),
+			}),
+		}),
+		Options: object(t, map[string]runtime.Value{
+			"paginate": object(t, map[string]runtime.Value{
+				"connectionPath": runtime.NewString("repository.issues"),
+			}),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+
+	assertIDs(t, ctx, out, 1, 2, 3)
+
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("expected 2 page requests, got %d", got)
+	}
+}
+
+func TestConnectionNodes(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	connection, err := decodeJSONBody([]byte(`{"nodes": [{"id": 1}, {"id": 2}]}`))
+	if err != nil {
+		t.Fatalf("failed to decode connection: %v", err)
+	}
+
+	nodes, err := connectionNodes(ctx, connection)
+	if err != nil {
+		t.Fatalf("unexpected nodes error: %v", err)
+	}
+
+	assertIDs(t, ctx, runtime.NewArrayWith(nodes...), 1, 2)
+}
+
+func TestClientRejectsMismatchedGraphQLPagination(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient(DefaultConfig())
+
+	cases := []struct {
+		kind     string
+		paginate runtime.Value
+		want     string
+	}{
+		{
+			kind:     "graphql",
+			paginate: runtime.NewString("page"),
+			want:     "only support relay pagination",
+		},
+		{
+			kind: "http",
+			paginate: object(t, map[string]runtime.Value{
+				"connectionPath": runtime.NewString("items"),
+			}),
+			want: "relay pagination requires USING graphql",
+		},
+		{
+			kind:     "graphql",
+			paginate: runtime.NewString("relay"),
+			want:     "connectionPath is required",
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := client.Query(context.Background(), runtime.Query{
+			Kind:       runtime.NewString(tc.kind),
+			Expression: runtime.NewString("{ items { id } }"),
+			Options: object(t, map[string]runtime.Value{
+				"paginate": tc.paginate,
+			}),
+		})
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("expected error containing %q, got %v", tc.want, err)
+		}
+	}
+}
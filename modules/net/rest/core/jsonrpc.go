@@ -0,0 +1,369 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	commonresource "github.com/MontFerret/contrib/pkg/common/resource"
+	ferrethttp "github.com/MontFerret/ferret/v2/pkg/net/http"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+type (
+	// JSONRPCError is the error object of a JSON-RPC 2.0 response.
+	// It unwraps to the Ferret error that matches its code.
+	JSONRPCError struct {
+		Data    any    `json:"data,omitempty"`
+		Message string `json:"message"`
+		Code    int64  `json:"code"`
+	}
+
+	// jsonRPCRequest is a single call or a batch of calls of a USING jsonrpc query.
+	jsonRPCRequest struct {
+		calls []jsonRPCCall
+		batch bool
+	}
+
+	jsonRPCCall struct {
+		params any
+		method string
+		id     uint64
+	}
+
+	jsonRPCResponse struct {
+		Error  *JSONRPCError   `json:"error"`
+		ID     json.RawMessage `json:"id"`
+		Result json.RawMessage `json:"result"`
+	}
+)
+
+const (
+	jsonRPCOwner   = "JSON-RPC query WITH"
+	jsonRPCVersion = "2.0"
+)
+
+// Error codes reserved by the JSON-RPC 2.0 specification.
+const (
+	jsonRPCParseError     = -32700
+	jsonRPCInvalidRequest = -32600
+	jsonRPCMethodNotFound = -32601
+	jsonRPCInvalidParams  = -32602
+)
+
+var jsonRPCIDs commonresource.IDGenerator
+
+func (e *JSONRPCError) Error() string {
+	return fmt.Sprintf("JSON-RPC error %d: %s", e.Code, e.Message)
+}
+
+func (e *JSONRPCError) Unwrap() error {
+	switch e.Code {
+	case jsonRPCMethodNotFound:
+		return runtime.ErrNotFound
+	case jsonRPCParseError, jsonRPCInvalidRequest, jsonRPCInvalidParams:
+		return runtime.ErrInvalidArgument
+	default:
+		return runtime.ErrUnexpected
+	}
+}
+
+// prepareJSONRPC posts the query expression as a JSON-RPC method call, or WITH.batch as a batch of calls.
+func (ex *exchange) prepareJSONRPC(ctx context.Context, cfg Config, q runtime.Query) error {
+	if ex.options.Paginate != nil {
+		return errors.New("JSON-RPC queries do not support pagination")
+	}
+
+	request, headers, err := decodeJSONRPCRequest(ctx, q.Expression.String(), q.Params)
+	if err != nil {
+		return err
+	}
+
+	requestURL, err := resolveRequestURL(ctx, cfg.BaseURL, ex.options.JSONRPCEndpoint, runtime.None)
+	if err != nil {
+		return err
+	}
+
+	body, err := request.encode()
+	if err != nil {
+		return err
+	}
+
+	ex.jsonrpc = request
+	ex.headers = mergeHeaders(cfg.Headers, headers)
+	ex.method = http.MethodPost
+	ex.url = requestURL
+	ex.body = body
+
+	// JSON-RPC always speaks JSON, whatever the client encodings are
+	ex.headers.Set("Content-Type", "application/json")
+	if !hasHeader(ex.headers, "Accept") {
+		ex.headers.Set("Accept", "application/json")
+	}
+
+	return nil
+}
+
+// decodeJSONRPCRequest decodes the params, batch and headers of WITH and assigns an id to every call.
+// Batch calls without a method call the method of the query expression.
+func decodeJSONRPCRequest(ctx context.Context, method string, value runtime.Value) (*jsonRPCRequest, http.Header, error) {
+	headers := make(http.Header)
+	params := runtime.Value(runtime.None)
+	hasParams := false
+
+	var batch []runtime.Value
+	hasBatch := false
+
+	if !runtime.TypeNone.Is(value) {
+		obj, err := requireMap(ctx, value, jsonRPCOwner)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if value, found, err := lookupValue(ctx, obj, "headers"); err != nil {
+			return nil, nil, err
+		} else if found {
+			if headers, err = decodeHeaders(ctx, value, jsonRPCOwner+".headers"); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if params, hasParams, err = lookupValue(ctx, obj, "params"); err != nil {
+			return nil, nil, err
+		}
+
+		if batch, hasBatch, err = lookupList(ctx, obj, "batch", jsonRPCOwner); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if !hasBatch {
+		call, err := newJSONRPCCall(ctx, method, params)
+		if err != nil {
+			return nil, nil, fmt.Errorf("JSON-RPC call: %w", err)
+		}
+
+		return &jsonRPCRequest{calls: []jsonRPCCall{call}}, headers, nil
+	}
+
+	if hasParams {
+		return nil, nil, fmt.Errorf("%s must have either params or batch, not both", jsonRPCOwner)
+	}
+
+	if len(batch) == 0 {
+		return nil, nil, fmt.Errorf("%s.batch must not be empty", jsonRPCOwner)
+	}
+
+	request := &jsonRPCRequest{batch: true}
+
+	for idx, item := range batch {
+		call, err := decodeJSONRPCCall(ctx, method, item)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s.batch[%d]: %w", jsonRPCOwner, idx, err)
+		}
+
+		request.calls = append(request.calls, call)
+	}
+
+	return request, headers, nil
+}
+
+// decodeJSONRPCCall decodes a {method, params} call of a batch.
+func decodeJSONRPCCall(ctx context.Context, method string, value runtime.Value) (jsonRPCCall, error) {
+	obj, err := requireMap(ctx, value, "call")
+	if err != nil {
+		return jsonRPCCall{}, err
+	}
+
+	if name, found, err := lookupString(ctx, obj, "method", "call"); err != nil {
+		return jsonRPCCall{}, err
+	} else if found {
+		method = name
+	}
+
+	params, _, err := lookupValue(ctx, obj, "params")
+	if err != nil {
+		return jsonRPCCall{}, err
+	}
+
+	return newJSONRPCCall(ctx, method, params)
+}
+
+// newJSONRPCCall assigns a new id to a call.
+// Params are by-position as an array or by-name as an object.
+func newJSONRPCCall(ctx context.Context, method string, params runtime.Value) (jsonRPCCall, error) {
+	call := jsonRPCCall{method: strings.TrimSpace(method), id: jsonRPCIDs.Next()}
+	if call.method == "" {
+		return call, errors.New("method must not be empty")
+	}
+
+	var err error
+
+	switch typed := params.(type) {
+	case runtime.List:
+		call.params, err = listToNative(ctx, typed)
+	case runtime.Map:
+		call.params, err = mapToNative(ctx, typed)
+	default:
+		if !runtime.TypeNone.Is(params) {
+			return call, errors.New("params must be an array or an object")
+		}
+	}
+	if err != nil {
+		return call, fmt.Errorf("params: %w", err)
+	}
+
+	return call, nil
+}
+
+// encode returns the JSON request body: one request object, or an array of them for a batch.
+func (r *jsonRPCRequest) encode() ([]byte, error) {
+	type message struct {
+		Params  any    `json:"params,omitempty"`
+		JSONRPC string `json:"jsonrpc"`
+		Method  string `json:"method"`
+		ID      uint64 `json:"id"`
+	}
+
+	messages := make([]message, 0, len(r.calls))
+	for _, call := range r.calls {
+		messages = append(messages, message{
+			JSONRPC: jsonRPCVersion,
+			Method:  call.method,
+			Params:  call.params,
+			ID:      call.id,
+		})
+	}
+
+	if !r.batch {
+		return json.Marshal(messages[0])
+	}
+
+	return json.Marshal(messages)
+}
+
+// decodeResponse returns the result of a call, or the results of a batch in call order.
+// Failed calls are raised or, like full responses, returned with the whole JSON-RPC response as body.
+func (r *jsonRPCRequest) decodeResponse(ctx context.Context, requestURL string, resp *ferrethttp.Response, attempts int, opts ExecutionOptions) (runtime.Value, bool, error) {
+	ok := resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusBadRequest
+
+	var rpcErr *JSONRPCError
+
+	responses, err := r.match(resp.Body)
+	if err != nil && ok && !errors.As(err, &rpcErr) {
+		return runtime.None, false, fmt.Errorf("decode response body: %w", err)
+	}
+
+	failure := err
+	if failure == nil {
+		failure = r.callError(responses)
+	}
+	if failure == nil && !ok {
+		failure = fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	if failure != nil && opts.ErrorMode == ErrorModeRaise {
+		// a failed status without a JSON-RPC error object is reported as such
+		if !ok && !errors.As(failure, &rpcErr) {
+			return runtime.None, false, fmt.Errorf("unexpected status %s", resp.Status)
+		}
+
+		return runtime.None, false, failure
+	}
+
+	if failure != nil || opts.ResponseMode == ResponseModeFull {
+		decoded, err := decodeJSONBody(resp.Body)
+		if err != nil && ok {
+			return runtime.None, false, fmt.Errorf("decode response body: %w", err)
+		}
+		// a failed status may come with a gateway or proxy error page, which is returned as text
+		if err != nil {
+			decoded = runtime.NewString(string(resp.Body))
+		}
+
+		value, err := buildFullResponse(ctx, requestURL, resp, attempts, decoded)
+		if err != nil {
+			return runtime.None, false, err
+		}
+
+		return value, false, nil
+	}
+
+	results := make([]runtime.Value, 0, len(responses))
+	for _, response := range responses {
+		result, err := decodeJSONBody(response.Result)
+		if err != nil {
+			return runtime.None, false, fmt.Errorf("decode result: %w", err)
+		}
+
+		results = append(results, result)
+	}
+
+	if !r.batch {
+		return results[0], true, nil
+	}
+
+	return runtime.NewArrayWith(results...), true, nil
+}
+
+// match returns the responses in call order. Batch responses may arrive in any order and are matched by id.
+func (r *jsonRPCRequest) match(body []byte) ([]jsonRPCResponse, error) {
+	if !r.batch {
+		var response jsonRPCResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, err
+		}
+
+		return []jsonRPCResponse{response}, nil
+	}
+
+	var responses []jsonRPCResponse
+	if err := json.Unmarshal(body, &responses); err != nil {
+		// a batch that cannot be processed at all is answered with a single error response
+		var response jsonRPCResponse
+		if json.Unmarshal(body, &response) == nil && response.Error != nil {
+			return nil, response.Error
+		}
+
+		return nil, err
+	}
+
+	byID := make(map[string]jsonRPCResponse, len(responses))
+	for _, response := range responses {
+		byID[string(bytes.TrimSpace(response.ID))] = response
+	}
+
+	out := make([]jsonRPCResponse, 0, len(r.calls))
+	for idx, call := range r.calls {
+		response, found := byID[strconv.FormatUint(call.id, 10)]
+		if !found {
+			return nil, fmt.Errorf("missing response to call %d (%s)", idx, call.method)
+		}
+
+		out = append(out, response)
+	}
+
+	return out, nil
+}
+
+// callError returns the error of the first failed call.
+func (r *jsonRPCRequest) callError(responses []jsonRPCResponse) error {
+	for idx, response := range responses {
+		if response.Error == nil {
+			continue
+		}
+
+		if !r.batch {
+			return response.Error
+		}
+
+		return fmt.Errorf("call %d (%s): %w", idx, r.calls[idx].method, response.Error)
+	}
+
+	return nil
+}
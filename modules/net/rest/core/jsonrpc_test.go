@@ -0,0 +1,233 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+type jsonRPCTestCall struct {
+	ID      any    `json:"id"`
+	Params  any    `json:"params"`
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+}
+
+func TestClientJSONRPCCall(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rpc" {
+			t.Fatalf("expected POST /rpc, got %s %s", r.Method, r.URL.Path)
+		}
+
+		var call jsonRPCTestCall
+		if err := json.NewDecoder(r.Body).Decode(&call); err != nil {
+			t.Fatalf("failed to decode call: %v", err)
+		}
+		if call.JSONRPC != "2.0" || call.Method != "eth_getBalance" || call.ID == nil {
+			t.Fatalf("unexpected call: %+v", call)
+		}
+		if params, ok := call.Params.([]any); !ok || len(params) != 2 || params[0] != "0xabc" {
+			t.Fatalf("unexpected params: %v", call.Params)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": call.ID, "result": "0x10"})
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+	cfg.JSONRPCEndpoint = "/rpc"
+
+	out, err := NewClient(cfg).QueryOne(ctx, runtime.Query{
+		Kind:       runtime.NewString("jsonrpc"),
+		Expression: runtime.NewString("eth_getBalance"),
+		Params: object(t, map[string]runtime.Value{
+			"params": runtime.NewArrayWith(runtime.NewString("0xabc"), runtime.NewString("latest")),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+	if out != runtime.NewString("0x10") {
+		t.Fatalf("expected the call result, got %s", out.String())
+	}
+}
+
+func TestClientJSONRPCBatch(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var calls []jsonRPCTestCall
+		if err := json.NewDecoder(r.Body).Decode(&calls); err != nil {
+			t.Fatalf("failed to decode batch: %v", err)
+		}
+		if len(calls) != 3 || calls[0].Method != "block" || calls[2].Method != "gasPrice" {
+			t.Fatalf("unexpected batch: %+v", calls)
+		}
+		if calls[0].ID == calls[1].ID {
+			t.Fatalf("expected unique ids, got %v", calls[0].ID)
+		}
+
+		// respond in reverse order
+		responses := make([]map[string]any, 0, len(calls))
+		for i := len(calls) - 1; i >= 0; i-- {
+			call := calls[i]
+			result := map[string]any{"method": call.Method, "params": call.Params}
+
+			if call.Method == "gasPrice" && r.URL.Query().Get("fail") == "1" {
+				responses = append(responses, map[string]any{
+					"jsonrpc": "2.0",
+					"id":      call.ID,
+					"error":   map[string]any{"code": -32601, "message": "method not found"},
+				})
+
+				continue
+			}
+
+			responses = append(responses, map[string]any{"jsonrpc": "2.0", "id": call.ID, "result": result})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(responses)
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+	client := NewClient(cfg)
+
+	query := runtime.Query{
+		Kind:       runtime.NewString("jsonrpc"),
+		Expression: runtime.NewString("block"),
+		Params: object(t, map[string]runtime.Value{
+			"batch": runtime.NewArrayWith(
+				object(t, map[string]runtime.Value{"params": runtime.NewArrayWith(runtime.NewInt(1))}),
+				object(t, map[string]runtime.Value{"params": runtime.NewArrayWith(runtime.NewInt(2))}),
+				object(t, map[string]runtime.Value{"method": runtime.NewString("gasPrice")}),
+			),
+		}),
+	}
+
+	out, err := client.Query(ctx, query)
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+
+	methods := make([]string, 0, 3)
+	iterateList(t, ctx, out, func(item runtime.Value) {
+		methods = append(methods, field(t, item, "method").String())
+	})
+	if len(methods) != 3 || methods[0] != "block" || methods[1] != "block" || methods[2] != "gasPrice" {
+		t.Fatalf("expected results in call order, got %v", methods)
+	}
+
+	second, err := out.At(ctx, runtime.NewInt(1))
+	if err != nil {
+		t.Fatalf("unexpected item error: %v", err)
+	}
+	if got := field(t, second, "params").String(); got != "[2]" {
+		t.Fatalf("expected the second result to match the second call, got %s", got)
+	}
+
+	cfg.BaseURL = server.URL + "/?fail=1"
+	failing := NewClient(cfg)
+
+	_, err = failing.Query(ctx, query)
+
+	var rpcErr *JSONRPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32601 {
+		t.Fatalf("expected a JSON-RPC error, got %v", err)
+	}
+	if !errors.Is(err, runtime.ErrNotFound) {
+		t.Fatalf("expected method not found to be a not found error, got %v", err)
+	}
+
+	query.Options = object(t, map[string]runtime.Value{
+		"errorMode": runtime.NewString("response"),
+	})
+
+	full, err := failing.QueryOne(ctx, query)
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+	if _, ok := field(t, full, "body").(runtime.List); !ok {
+		t.Fatalf("expected the full batch response, got %s", full.String())
+	}
+}
+
+func TestJSONRPCErrorModeResponseKeepsTextBodies(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`<h1>Forbidden</h1>`))
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+
+	client := NewClient(cfg)
+	query := runtime.Query{
+		Kind:       runtime.NewString("jsonrpc"),
+		Expression: runtime.NewString("eth_blockNumber"),
+	}
+
+	if _, err := client.Query(ctx, query); err == nil || !strings.Contains(err.Error(), "unexpected status") {
+		t.Fatalf("expected an unexpected status error, got %v", err)
+	}
+
+	query.Options = object(t, map[string]runtime.Value{
+		"errorMode": runtime.NewString("response"),
+	})
+
+	full, err := client.QueryOne(ctx, query)
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+	if got := field(t, full, "status"); got != runtime.NewInt(http.StatusForbidden) {
+		t.Fatalf("expected the failed status, got %s", full.String())
+	}
+	if got := field(t, full, "body"); got != runtime.NewString(`<h1>Forbidden</h1>`) {
+		t.Fatalf("expected the raw body, got %s", got.String())
+	}
+}
+
+func TestDecodeJSONRPCRequestErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	cases := []struct {
+		method string
+		with   runtime.Value
+	}{
+		{method: "", with: runtime.None},
+		{method: "m", with: object(t, map[string]runtime.Value{"params": runtime.NewString("x")})},
+		{method: "m", with: object(t, map[string]runtime.Value{"batch": runtime.NewArray(0)})},
+		{method: "", with: object(t, map[string]runtime.Value{"batch": runtime.NewArrayWith(object(t, map[string]runtime.Value{}))})},
+		{method: "m", with: object(t, map[string]runtime.Value{
+			"params": runtime.NewArray(0),
+			"batch":  runtime.NewArrayWith(object(t, map[string]runtime.Value{})),
+		})},
+	}
+
+	for _, tc := range cases {
+		if _, _, err := decodeJSONRPCRequest(ctx, tc.method, tc.with); err == nil {
+			t.Fatalf("expected JSON-RPC request %q %s to fail", tc.method, tc.with.String())
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// nextLink returns the target of the rel="next" link in RFC 8288 Link headers, resolved against the request URL.
+// Links to another origin are not followed, since the request credentials would be sent to it.
+func nextLink(headers http.Header, requestURL string) string {
+	for _, header := range headers.Values("Link") {
+		for _, link := range splitLinks(header) {
+			target, params, ok := parseLink(link)
+			if !ok || !hasRelation(params["rel"], "next") {
+				continue
+			}
+
+			base, err := url.Parse(requestURL)
+			if err != nil {
+				return ""
+			}
+
+			ref, err := url.Parse(target)
+			if err != nil {
+				return ""
+			}
+
+			next := base.ResolveReference(ref)
+			if !strings.EqualFold(next.Scheme, base.Scheme) || !strings.EqualFold(next.Host, base.Host) {
+				return ""
+			}
+
+			return next.String()
+		}
+	}
+
+	return ""
+}
+
+// splitLinks splits a Link header on the commas that separate links, skipping commas inside URIs and quoted values.
+func splitLinks(header string) []string {
+	links := make([]string, 0, 2)
+	start := 0
+	inURI := false
+	inQuotes := false
+
+	for i := 0; i < len(header); i++ {
+		switch c := header[i]; {
+		case inQuotes:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inQuotes = false
+			}
+		case c == '<':
+			inURI = true
+		case c == '>':
+			inURI = false
+		case c == '"':
+			inQuotes = true
+		case c == ',' && !inURI:
+			links = append(links, header[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(links, header[start:])
+}
+
+func parseLink(link string) (string, map[string]string, bool) {
+	link = strings.TrimSpace(link)
+	if !strings.HasPrefix(link, "<") {
+		return "", nil, false
+	}
+
+	end := strings.IndexByte(link, '>')
+	if end < 0 {
+		return "", nil, false
+	}
+
+	target := strings.TrimSpace(link[1:end])
+	params := make(map[string]string)
+
+	for _, param := range strings.Split(link[end+1:], ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		if name == "" {
+			continue
+		}
+
+		// the first occurrence of a parameter wins
+		if _, found := params[name]; !found {
+			params[name] = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+
+	return target, params, true
+}
+
+// hasRelation reports whether a space-separated rel value contains the relation type.
+func hasRelation(rel, relation string) bool {
+	for _, value := range strings.Fields(rel) {
+		if strings.EqualFold(value, relation) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	commonobject "github.com/MontFerret/contrib/pkg/common/object"
@@ -23,3 +24,55 @@ func lookupString(ctx context.Context, obj runtime.Map, key, owner string) (stri
 func lookupDuration(ctx context.Context, obj runtime.Map, key, owner string) (time.Duration, bool, error) {
 	return commonobject.MillisDuration(ctx, obj, key, owner)
 }
+
+func lookupInt(ctx context.Context, obj runtime.Map, key, owner string) (int64, bool, error) {
+	value, found, err := lookupValue(ctx, obj, key)
+	if err != nil || !found {
+		return 0, found, err
+	}
+
+	integer, ok := value.(runtime.Int)
+	if !ok {
+		return 0, true, fmt.Errorf("%s.%s must be an integer", owner, key)
+	}
+
+	return int64(integer), true, nil
+}
+
+func lookupBool(ctx context.Context, obj runtime.Map, key, owner string) (bool, bool, error) {
+	value, found, err := lookupValue(ctx, obj, key)
+	if err != nil || !found {
+		return false, found, err
+	}
+
+	boolean, ok := value.(runtime.Boolean)
+	if !ok {
+		return false, true, fmt.Errorf("%s.%s must be a boolean", owner, key)
+	}
+
+	return bool(boolean), true, nil
+}
+
+func lookupList(ctx context.Context, obj runtime.Map, key, owner string) ([]runtime.Value, bool, error) {
+	value, found, err := lookupValue(ctx, obj, key)
+	if err != nil || !found {
+		return nil, found, err
+	}
+
+	list, ok := value.(runtime.List)
+	if !ok {
+		return nil, true, fmt.Errorf("%s.%s must be an array", owner, key)
+	}
+
+	out := make([]runtime.Value, 0)
+	err = list.ForEach(ctx, func(_ context.Context, item runtime.Value, _ runtime.Int) (runtime.Boolean, error) {
+		out = append(out, item)
+
+		return runtime.True, nil
+	})
+	if err != nil {
+		return nil, true, fmt.Errorf("%s.%s: %w", owner, key, err)
+	}
+
+	return out, true, nil
+}
@@ -0,0 +1,194 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	stdfs "io/fs"
+	"maps"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"path"
+	"slices"
+	"strings"
+
+	ferretfs "github.com/MontFerret/ferret/v2/pkg/fs"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+const multipartOwner = "HTTP request body"
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// filePart is a multipart file described by {filename, contentType, data} or {filename, contentType, path}.
+type filePart struct {
+	data        runtime.Value
+	filename    string
+	contentType string
+	path        string
+}
+
+// encodeMultipartBody writes a multipart/form-data body.
+// Scalars become form fields, binaries and file objects become file parts and arrays repeat the field.
+// Fields are written in name order, so the body is stable across requests. The whole body, including
+// the content of files read from paths, is buffered in memory before it is sent.
+func encodeMultipartBody(ctx context.Context, value runtime.Value) ([]byte, string, error) {
+	obj, err := requireMap(ctx, value, multipartOwner)
+	if err != nil {
+		return nil, "", err
+	}
+
+	fields := make(map[string]runtime.Value)
+
+	err = obj.ForEach(ctx, func(_ context.Context, value, key runtime.Value) (runtime.Boolean, error) {
+		fields[key.String()] = value
+
+		return runtime.True, nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		if err := writeMultipartValue(ctx, writer, name, fields[name]); err != nil {
+			return nil, "", fmt.Errorf("%s.%s: %w", multipartOwner, name, err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return body.Bytes(), writer.FormDataContentType(), nil
+}
+
+func writeMultipartValue(ctx context.Context, writer *multipart.Writer, name string, value runtime.Value) error {
+	switch typed := value.(type) {
+	case runtime.Binary:
+		return writeFilePart(ctx, writer, name, filePart{data: typed})
+	case runtime.Map:
+		part, err := decodeFilePart(ctx, typed)
+		if err != nil {
+			return err
+		}
+
+		return writeFilePart(ctx, writer, name, part)
+	case runtime.List:
+		return typed.ForEach(ctx, func(ctx context.Context, item runtime.Value, idx runtime.Int) (runtime.Boolean, error) {
+			if _, nested := item.(runtime.List); nested {
+				return runtime.False, fmt.Errorf("at index %d: nested arrays are not supported", idx)
+			}
+
+			if err := writeMultipartValue(ctx, writer, name, item); err != nil {
+				return runtime.False, fmt.Errorf("at index %d: %w", idx, err)
+			}
+
+			return runtime.True, nil
+		})
+	}
+
+	if runtime.TypeNone.Is(value) {
+		return nil
+	}
+
+	return writer.WriteField(name, value.String())
+}
+
+func decodeFilePart(ctx context.Context, obj runtime.Map) (filePart, error) {
+	var part filePart
+
+	for key, target := range map[string]*string{
+		"filename":    &part.filename,
+		"contentType": &part.contentType,
+		"path":        &part.path,
+	} {
+		if str, found, err := lookupString(ctx, obj, key, "file part"); err != nil {
+			return part, err
+		} else if found {
+			*target = strings.TrimSpace(str)
+		}
+	}
+
+	data, found, err := lookupValue(ctx, obj, "data")
+	if err != nil {
+		return part, err
+	}
+
+	if found && !runtime.TypeNone.Is(data) {
+		part.data = data
+	}
+
+	switch {
+	case part.data != nil && part.path != "":
+		return part, errors.New("file part must have either data or path, not both")
+	case part.data == nil && part.path == "":
+		return part, errors.New("file part must have data or path")
+	}
+
+	return part, nil
+}
+
+// writeFilePart copies the file content into the body. Paths are read through the Ferret filesystem.
+func writeFilePart(ctx context.Context, writer *multipart.Writer, name string, part filePart) error {
+	filename := part.filename
+	if filename == "" && part.path != "" {
+		filename = path.Base(part.path)
+	}
+	if filename == "" {
+		filename = name
+	}
+
+	contentType := part.contentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(filename))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, quoteEscaper.Replace(name), quoteEscaper.Replace(filename)))
+	header.Set("Content-Type", contentType)
+
+	dst, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	if part.path == "" {
+		if binary, ok := part.data.(runtime.Binary); ok {
+			_, err = dst.Write([]byte(binary))
+		} else {
+			_, err = io.WriteString(dst, part.data.String())
+		}
+
+		return err
+	}
+
+	reader, err := ferretfs.ReaderFrom(ctx)
+	if err != nil {
+		return fmt.Errorf("resolve filesystem: %w", err)
+	}
+
+	file, err := reader.Open(part.path)
+	if err != nil {
+		if errors.Is(err, stdfs.ErrNotExist) {
+			return fmt.Errorf("failed to open file %q: file does not exist", part.path)
+		}
+
+		return fmt.Errorf("failed to open file %q: %w", part.path, err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(dst, file); err != nil {
+		return fmt.Errorf("failed to read file %q: %w", part.path, err)
+	}
+
+	return nil
+}
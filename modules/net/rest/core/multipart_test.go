@@ -0,0 +1,190 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ferretfs "github.com/MontFerret/ferret/v2/pkg/fs"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+func TestClientMultipartRequestEncoding(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "reports"), 0o755); err != nil {
+		t.Fatalf("failed to create reports directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "reports", "q1.pdf"), []byte("%PDF-1.7"), 0o644); err != nil {
+		t.Fatalf("failed to write report: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Fatalf("expected POST, got %s", r.Method)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("failed to parse multipart body: %v", err)
+		}
+
+		if got := r.MultipartForm.Value["title"]; len(got) != 1 || got[0] != "Q1" {
+			t.Fatalf("unexpected title field: %v", got)
+		}
+		if got := r.MultipartForm.Value["tag"]; len(got) != 2 || got[0] != "finance" || got[1] != "2026" {
+			t.Fatalf("unexpected tag fields: %v", got)
+		}
+
+		assertFilePart(t, r, "sheet", "report.xlsx", "application/vnd.ms-excel", "PK")
+		assertFilePart(t, r, "pdf", "q1.pdf", "application/pdf", "%PDF-1.7")
+		assertFilePart(t, r, "raw", "raw", "application/octet-stream", "raw bytes")
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	filesystem, err := ferretfs.New(ferretfs.WithRoot(root))
+	if err != nil {
+		t.Fatalf("failed to create filesystem: %v", err)
+	}
+
+	ctx := ferretfs.WithFileSystem(networkContext(t), filesystem)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+	cfg.Headers.Set("Content-Type", "multipart/form-data")
+
+	_, err = NewClient(cfg).QueryOne(ctx, runtime.Query{
+		Expression: runtime.NewString("/documents"),
+		Params: object(t, map[string]runtime.Value{
+			"body": object(t, map[string]runtime.Value{
+				"title": runtime.NewString("Q1"),
+				"tag":   runtime.NewArrayWith(runtime.NewString("finance"), runtime.NewInt(2026)),
+				"sheet": object(t, map[string]runtime.Value{
+					"filename":    runtime.NewString("report.xlsx"),
+					"contentType": runtime.NewString("application/vnd.ms-excel"),
+					"data":        runtime.NewBinary([]byte("PK")),
+				}),
+				"pdf": object(t, map[string]runtime.Value{
+					"path": runtime.NewString("reports/q1.pdf"),
+				}),
+				"raw": runtime.NewBinary([]byte("raw bytes")),
+			}),
+		}),
+		Options: object(t, map[string]runtime.Value{
+			"requestEncoding":  runtime.NewString("multipart"),
+			"responseEncoding": runtime.NewString("text"),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+}
+
+func TestMultipartRequestEncodingErrors(t *testing.T) {
+	t.Parallel()
+
+	filesystem, err := ferretfs.New(ferretfs.WithRoot(t.TempDir()))
+	if err != nil {
+		t.Fatalf("failed to create filesystem: %v", err)
+	}
+
+	ctx := ferretfs.WithFileSystem(context.Background(), filesystem)
+
+	for _, invalid := range []runtime.Value{
+		runtime.NewString("plain"),
+		object(t, map[string]runtime.Value{"file": object(t, map[string]runtime.Value{"filename": runtime.NewString("a.txt")})}),
+		object(t, map[string]runtime.Value{"file": object(t, map[string]runtime.Value{
+			"path": runtime.NewString("a.txt"),
+			"data": runtime.NewString("a"),
+		})}),
+		object(t, map[string]runtime.Value{"file": object(t, map[string]runtime.Value{"path": runtime.NewString("missing.txt")})}),
+		object(t, map[string]runtime.Value{"tags": runtime.NewArrayWith(runtime.NewArrayWith(runtime.NewString("a")))}),
+	} {
+		if _, _, err := encodeRequestBody(ctx, invalid, EncodingMultipart); err == nil {
+			t.Fatalf("expected multipart body %s to fail", invalid.String())
+		}
+	}
+
+	if _, err := DecodeClientConfig(ctx, object(t, map[string]runtime.Value{
+		"responseEncoding": runtime.NewString("multipart"),
+	})); err == nil {
+		t.Fatal("expected multipart to be rejected as a response encoding")
+	}
+}
+
+func TestMultipartRequestEncodingOrder(t *testing.T) {
+	t.Parallel()
+
+	body, contentType, err := encodeMultipartBody(context.Background(), object(t, map[string]runtime.Value{
+		"zeta":  runtime.NewString("z"),
+		"alpha": runtime.NewString("a"),
+		"mid":   runtime.NewArrayWith(runtime.NewString("1"), runtime.NewString("2")),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	}
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("unexpected content type %q: %v", contentType, err)
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	names := make([]string, 0, 4)
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected part error: %v", err)
+		}
+
+		names = append(names, part.FormName())
+	}
+
+	if got := strings.Join(names, ","); got != "alpha,mid,mid,zeta" {
+		t.Fatalf("expected parts in name order, got %s", got)
+	}
+}
+
+func assertFilePart(t *testing.T, r *http.Request, field, filename, contentType, content string) {
+	t.Helper()
+
+	files := r.MultipartForm.File[field]
+	if len(files) != 1 {
+		t.Fatalf("expected one %s file part, got %d", field, len(files))
+	}
+
+	file := files[0]
+	if file.Filename != filename {
+		t.Fatalf("expected %s filename %q, got %q", field, filename, file.Filename)
+	}
+	if got := file.Header.Get("Content-Type"); !strings.HasPrefix(got, contentType) {
+		t.Fatalf("expected %s content type %q, got %q", field, contentType, got)
+	}
+
+	opened, err := file.Open()
+	if err != nil {
+		t.Fatalf("failed to open %s part: %v", field, err)
+	}
+	defer opened.Close()
+
+	data, err := io.ReadAll(opened)
+	if err != nil {
+		t.Fatalf("failed to read %s part: %v", field, err)
+	}
+	if string(data) != content {
+		t.Fatalf("expected %s content %q, got %q", field, content, string(data))
+	}
+}
@@ -0,0 +1,797 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"mime"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	jsonschema "github.com/santhosh-tekuri/jsonschema/v6"
+
+	yamlcore "github.com/MontFerret/contrib/modules/yaml/core"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+type (
+	// OpenAPI is an OpenAPI 3.x document whose operations are queried by operationId.
+	OpenAPI struct {
+		operations         map[string]*openAPIOperation
+		requestEncodingSet bool
+	}
+
+	openAPIOperation struct {
+		requestBody       *openAPIRequestBody
+		responses         map[string]*openAPIResponse
+		id                string
+		method            string
+		baseURL           string
+		template          string
+		mediaType         string
+		accept            string
+		encoding          Encoding
+		parameters        []openAPIParameter
+		validateRequests  bool
+		validateResponses bool
+	}
+
+	openAPIParameter struct {
+		schema   *jsonschema.Schema
+		name     string
+		in       string
+		required bool
+	}
+
+	openAPIRequestBody struct {
+		// content maps the media types of the body to their schemas, which are nil without validation
+		content  map[string]*jsonschema.Schema
+		required bool
+	}
+
+	openAPIResponse struct {
+		content map[string]*jsonschema.Schema
+	}
+
+	// openAPIDocument reads the operations of a document and compiles their schemas when validation is enabled.
+	openAPIDocument struct {
+		root     map[string]any
+		compiler *jsonschema.Compiler
+	}
+
+	// openAPISettings are the FROM_OPENAPI config settings that are not client settings.
+	openAPISettings struct {
+		server            runtime.Value
+		variables         map[string]string
+		validateRequests  bool
+		validateResponses bool
+	}
+)
+
+const (
+	openAPIConfigOwner    = "NET::REST::FROM_OPENAPI config"
+	openAPISchemaLocation = "urn:net-rest:openapi"
+)
+
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// DecodeOpenAPIConfig decodes an OpenAPI document and the client config of FROM_OPENAPI.
+// The config accepts the NET::REST::CLIENT settings. Its base URL replaces the absolute servers of the document,
+// and relative server URLs are resolved against it.
+func DecodeOpenAPIConfig(ctx context.Context, spec, value runtime.Value) (Config, *OpenAPI, error) {
+	cfg := DefaultConfig()
+	cfg.ResponseEncoding = EncodingAuto
+	api := &OpenAPI{}
+	settings := openAPISettings{
+		server:    runtime.None,
+		variables: make(map[string]string),
+	}
+
+	if !runtime.TypeNone.Is(value) {
+		obj, err := requireMap(ctx, value, openAPIConfigOwner)
+		if err != nil {
+			return cfg, nil, err
+		}
+
+		if err := decodeConfig(ctx, &cfg, obj, openAPIConfigOwner); err != nil {
+			return cfg, nil, err
+		}
+
+		if settings, err = decodeOpenAPISettings(ctx, obj); err != nil {
+			return cfg, nil, err
+		}
+
+		for _, key := range []string{"encoding", "requestEncoding"} {
+			if _, found, err := lookupValue(ctx, obj, key); err != nil {
+				return cfg, nil, err
+			} else if found {
+				api.requestEncodingSet = true
+			}
+		}
+	}
+
+	root, err := decodeOpenAPIDocument(ctx, spec)
+	if err != nil {
+		return cfg, nil, err
+	}
+
+	doc := &openAPIDocument{root: root}
+	if settings.validateRequests || settings.validateResponses {
+		if doc.compiler, err = newOpenAPICompiler(root); err != nil {
+			return cfg, nil, err
+		}
+	}
+
+	// a configured server replaces the servers of paths and operations
+	useServers := runtime.TypeNone.Is(settings.server)
+	configuredBaseURL := cfg.BaseURL
+
+	serverURL, relative, err := doc.serverURL(root["servers"], settings.server, settings.variables, configuredBaseURL)
+	if err != nil {
+		return cfg, nil, err
+	}
+
+	if configuredBaseURL == "" || relative {
+		cfg.BaseURL = serverURL
+	}
+	if cfg.BaseURL == "" {
+		return cfg, nil, fmt.Errorf("%s.baseUrl is required when the document has no servers", openAPIConfigOwner)
+	}
+
+	if api.operations, err = doc.operations(cfg.BaseURL, configuredBaseURL, useServers, settings); err != nil {
+		return cfg, nil, err
+	}
+
+	return cfg, api, nil
+}
+
+func decodeOpenAPISettings(ctx context.Context, obj runtime.Map) (openAPISettings, error) {
+	settings := openAPISettings{
+		server:    runtime.None,
+		variables: make(map[string]string),
+	}
+
+	if server, found, err := lookupValue(ctx, obj, "server"); err != nil {
+		return settings, err
+	} else if found {
+		settings.server = server
+	}
+
+	if variables, found, err := lookupValue(ctx, obj, "serverVariables"); err != nil {
+		return settings, err
+	} else if found {
+		values, err := requireMap(ctx, variables, openAPIConfigOwner+".serverVariables")
+		if err != nil {
+			return settings, err
+		}
+
+		err = values.ForEach(ctx, func(_ context.Context, value, key runtime.Value) (runtime.Boolean, error) {
+			settings.variables[key.String()] = value.String()
+
+			return runtime.True, nil
+		})
+		if err != nil {
+			return settings, err
+		}
+	}
+
+	if validate, found, err := lookupValue(ctx, obj, "validate"); err != nil {
+		return settings, err
+	} else if found {
+		switch mode := validate.(type) {
+		case runtime.Boolean:
+			settings.validateRequests = bool(mode)
+			settings.validateResponses = bool(mode)
+		case runtime.String:
+			switch strings.ToLower(strings.TrimSpace(mode.String())) {
+			case "request":
+				settings.validateRequests = true
+			case "response":
+				settings.validateResponses = true
+			default:
+				return settings, fmt.Errorf("%s.validate: unsupported mode %q; expected \"request\" or \"response\"", openAPIConfigOwner, mode.String())
+			}
+		default:
+			return settings, fmt.Errorf("%s.validate must be a boolean or a string", openAPIConfigOwner)
+		}
+	}
+
+	return settings, nil
+}
+
+// decodeOpenAPIDocument decodes a JSON or YAML document, or an object, into its generic JSON form.
+func decodeOpenAPIDocument(ctx context.Context, spec runtime.Value) (map[string]any, error) {
+	var native any
+
+	switch value := spec.(type) {
+	case runtime.String:
+		text := strings.TrimSpace(value.String())
+
+		if strings.HasPrefix(text, "{") {
+			decoder := json.NewDecoder(strings.NewReader(text))
+			decoder.UseNumber()
+
+			if err := decoder.Decode(&native); err != nil {
+				return nil, fmt.Errorf("decode OpenAPI document: %w", err)
+			}
+
+			break
+		}
+
+		decoded, err := yamlcore.Decode(ctx, runtime.NewString(text))
+		if err != nil {
+			return nil, fmt.Errorf("decode OpenAPI document: %w", err)
+		}
+
+		if native, err = runtimeToNative(ctx, decoded); err != nil {
+			return nil, fmt.Errorf("decode OpenAPI document: %w", err)
+		}
+	case runtime.Map:
+		converted, err := mapToNative(ctx, value)
+		if err != nil {
+			return nil, fmt.Errorf("decode OpenAPI document: %w", err)
+		}
+
+		native = converted
+	default:
+		return nil, fmt.Errorf("OpenAPI document must be a string or an object")
+	}
+
+	normalized, err := openAPIJSON(native)
+	if err != nil {
+		return nil, fmt.Errorf("decode OpenAPI document: %w", err)
+	}
+
+	root, ok := normalized.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("OpenAPI document must be an object")
+	}
+
+	if version, _ := root["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q; expected 3.x", version)
+	}
+
+	return root, nil
+}
+
+// openAPIJSON converts a native value to the generic JSON form that schemas validate, with numbers as json.Number.
+func openAPIJSON(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var out any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// operation returns the operation with an operationId.
+func (api *OpenAPI) operation(id string) (*openAPIOperation, error) {
+	op, found := api.operations[strings.TrimSpace(id)]
+	if !found {
+		return nil, fmt.Errorf("unknown OpenAPI operation %q", id)
+	}
+
+	return op, nil
+}
+
+// operationConfig returns the client config of an operation, with its server and its request encoding
+// unless the client sets one.
+func (api *OpenAPI) operationConfig(cfg Config, op *openAPIOperation) Config {
+	cfg.BaseURL = op.baseURL
+
+	if !api.requestEncodingSet && op.encoding != "" {
+		cfg.RequestEncoding = op.encoding
+	}
+
+	return cfg
+}
+
+// prepareOpenAPI sends a query to the path and method of its operation, with the documented content type and
+// Accept header unless headers set them. The request is validated first when request validation is enabled.
+func (ex *exchange) prepareOpenAPI(ctx context.Context, cfg Config, q runtime.Query, op *openAPIOperation) error {
+	requestData, err := DecodeRequestData(ctx, q.Params)
+	if err != nil {
+		return err
+	}
+
+	headers := mergeHeaders(cfg.Headers, requestData.Headers)
+
+	if op.validateRequests {
+		if err := op.validateRequest(ctx, requestData, headers, ex.options.RequestEncoding); err != nil {
+			return err
+		}
+	}
+
+	if requestData.HasBody && op.mediaType != "" && ex.options.RequestEncoding == op.encoding && !hasHeader(headers, "Content-Type") {
+		requestData.Headers.Set("Content-Type", op.mediaType)
+	}
+
+	if op.accept != "" && !hasHeader(headers, "Accept") {
+		requestData.Headers.Set("Accept", op.accept)
+	}
+
+	requestData.Method = op.method
+	ex.openapi = op
+
+	return ex.prepareRequest(ctx, cfg, op.template, requestData)
+}
+
+// serverURL selects a server of a servers list and substitutes its variables.
+// The selector is an index or a server description or URL, and the first server is used without one.
+// A relative server URL is resolved against the base URL and reported as relative.
+func (d *openAPIDocument) serverURL(value any, selector runtime.Value, variables map[string]string, baseURL string) (string, bool, error) {
+	servers, _ := value.([]any)
+	if len(servers) == 0 {
+		return "", false, nil
+	}
+
+	var server map[string]any
+
+	switch selected := selector.(type) {
+	case runtime.Int:
+		if selected < 0 || int(selected) >= len(servers) {
+			return "", false, fmt.Errorf("%s.server: index %d is out of range of %d servers", openAPIConfigOwner, selected, len(servers))
+		}
+
+		server, _ = servers[selected].(map[string]any)
+	case runtime.String:
+		name := strings.TrimSpace(selected.String())
+
+		for _, item := range servers {
+			candidate, _ := item.(map[string]any)
+			description, _ := candidate["description"].(string)
+			serverURL, _ := candidate["url"].(string)
+
+			if strings.EqualFold(description, name) || serverURL == name {
+				server = candidate
+
+				break
+			}
+		}
+
+		if server == nil {
+			return "", false, fmt.Errorf("%s.server: no server matches %q", openAPIConfigOwner, name)
+		}
+	default:
+		if !runtime.TypeNone.Is(selector) {
+			return "", false, fmt.Errorf("%s.server must be an integer or a string", openAPIConfigOwner)
+		}
+
+		server, _ = servers[0].(map[string]any)
+	}
+
+	template, _ := server["url"].(string)
+	defined, _ := server["variables"].(map[string]any)
+
+	serverURL, err := expandServerVariables(template, defined, variables)
+
+	var parsed *url.URL
+	if err == nil {
+		if parsed, err = url.Parse(serverURL); err != nil {
+			err = fmt.Errorf("invalid server URL %q: %w", serverURL, err)
+		}
+	}
+
+	if err != nil {
+		// a configured base URL replaces a server that cannot be used
+		if baseURL != "" {
+			return "", false, nil
+		}
+
+		return "", false, err
+	}
+
+	if parsed.IsAbs() {
+		return serverURL, false, nil
+	}
+
+	base, err := url.Parse(baseURL)
+	if err != nil || !base.IsAbs() {
+		return "", false, fmt.Errorf("server URL %q is not absolute; set %s.baseUrl", serverURL, openAPIConfigOwner)
+	}
+
+	return base.ResolveReference(parsed).String(), true, nil
+}
+
+// expandServerVariables substitutes the {name} variables of a server URL with configured values or their defaults.
+func expandServerVariables(template string, defined map[string]any, values map[string]string) (string, error) {
+	var out strings.Builder
+	rest := template
+
+	for {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			out.WriteString(rest)
+
+			return out.String(), nil
+		}
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("server URL %q has an unterminated variable", template)
+		}
+
+		name := rest[start+1 : start+end]
+		variable, _ := defined[name].(map[string]any)
+
+		value, found := values[name]
+		if !found {
+			value, found = variable["default"].(string)
+		}
+		if !found {
+			return "", fmt.Errorf("server variable %q has no value", name)
+		}
+
+		if enum, ok := variable["enum"].([]any); ok && !slices.Contains(enum, any(value)) {
+			return "", fmt.Errorf("server variable %q: %q is not one of %v", name, value, enum)
+		}
+
+		out.WriteString(rest[:start])
+		out.WriteString(value)
+		rest = rest[start+end+1:]
+	}
+}
+
+// operations indexes the operations of the document by operationId. Operations without one cannot be queried.
+// With useServers, servers declared on a path or an operation replace the base URL of their operations,
+// unless they are absolute and a base URL is configured.
+func (d *openAPIDocument) operations(baseURL, configuredBaseURL string, useServers bool, settings openAPISettings) (map[string]*openAPIOperation, error) {
+	out := make(map[string]*openAPIOperation)
+	paths, _ := d.root["paths"].(map[string]any)
+
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		item, itemPointer, err := d.resolve(paths[path], "/paths/"+escapePointerToken(path))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, method := range openAPIMethods {
+			node, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+
+			id, _ := node["operationId"].(string)
+			if id == "" {
+				continue
+			}
+
+			if _, found := out[id]; found {
+				return nil, fmt.Errorf("duplicate OpenAPI operationId %q", id)
+			}
+
+			opBaseURL := baseURL
+			if useServers {
+				// the most specific servers list wins
+				for _, servers := range []any{node["servers"], item["servers"]} {
+					serverURL, relative, err := d.serverURL(servers, runtime.None, settings.variables, configuredBaseURL)
+					if err != nil {
+						return nil, fmt.Errorf("operation %q: %w", id, err)
+					}
+					if serverURL != "" {
+						if configuredBaseURL == "" || relative {
+							opBaseURL = serverURL
+						}
+
+						break
+					}
+				}
+			}
+
+			op := &openAPIOperation{
+				id:                id,
+				method:            strings.ToUpper(method),
+				baseURL:           opBaseURL,
+				template:          strings.TrimSuffix(opBaseURL, "/") + openAPIPathTemplate(path),
+				responses:         make(map[string]*openAPIResponse),
+				validateRequests:  settings.validateRequests,
+				validateResponses: settings.validateResponses,
+			}
+
+			pointer := itemPointer + "/" + method
+			if err := d.readOperation(op, item, itemPointer, node, pointer); err != nil {
+				return nil, fmt.Errorf("operation %q: %w", id, err)
+			}
+
+			out[id] = op
+		}
+	}
+
+	return out, nil
+}
+
+func (d *openAPIDocument) readOperation(op *openAPIOperation, item map[string]any, itemPointer string, node map[string]any, pointer string) error {
+	// operation parameters replace path item parameters with the same name and location
+	shared, err := d.parameters(item["parameters"], itemPointer+"/parameters")
+	if err != nil {
+		return err
+	}
+
+	own, err := d.parameters(node["parameters"], pointer+"/parameters")
+	if err != nil {
+		return err
+	}
+
+	for _, param := range shared {
+		if !slices.ContainsFunc(own, func(p openAPIParameter) bool { return p.name == param.name && p.in == param.in }) {
+			op.parameters = append(op.parameters, param)
+		}
+	}
+
+	op.parameters = append(op.parameters, own...)
+
+	if value, found := node["requestBody"]; found {
+		body, bodyPointer, err := d.resolve(value, pointer+"/requestBody")
+		if err != nil {
+			return err
+		}
+
+		required, _ := body["required"].(bool)
+		op.requestBody = &openAPIRequestBody{required: required}
+
+		if op.requestBody.content, err = d.content(body, bodyPointer); err != nil {
+			return err
+		}
+
+		op.mediaType, op.encoding = preferredMediaType(slices.Sorted(maps.Keys(op.requestBody.content)))
+	}
+
+	responses, _ := node["responses"].(map[string]any)
+	accept := make([]string, 0)
+
+	for code, value := range responses {
+		response, responsePointer, err := d.resolve(value, pointer+"/responses/"+escapePointerToken(code))
+		if err != nil {
+			return err
+		}
+
+		content, err := d.content(response, responsePointer)
+		if err != nil {
+			return err
+		}
+
+		code = strings.ToUpper(code)
+		op.responses[code] = &openAPIResponse{content: content}
+
+		if strings.HasPrefix(code, "2") {
+			for mediaType := range content {
+				if !slices.Contains(accept, mediaType) {
+					accept = append(accept, mediaType)
+				}
+			}
+		}
+	}
+
+	slices.Sort(accept)
+	op.accept = strings.Join(accept, ", ")
+
+	return nil
+}
+
+func (d *openAPIDocument) parameters(value any, pointer string) ([]openAPIParameter, error) {
+	items, _ := value.([]any)
+	out := make([]openAPIParameter, 0, len(items))
+
+	for idx, item := range items {
+		node, nodePointer, err := d.resolve(item, pointer+"/"+strconv.Itoa(idx))
+		if err != nil {
+			return nil, err
+		}
+
+		name, _ := node["name"].(string)
+		in, _ := node["in"].(string)
+		if name == "" || in == "" {
+			return nil, fmt.Errorf("parameter at %s must have a name and a location", nodePointer)
+		}
+
+		required, _ := node["required"].(bool)
+		param := openAPIParameter{
+			name:     name,
+			in:       in,
+			required: required || in == "path",
+		}
+
+		if param.schema, err = d.schema(node, nodePointer); err != nil {
+			return nil, err
+		}
+
+		out = append(out, param)
+	}
+
+	return out, nil
+}
+
+// content returns the media types of a request body or a response with their compiled schemas.
+func (d *openAPIDocument) content(node map[string]any, pointer string) (map[string]*jsonschema.Schema, error) {
+	content, _ := node["content"].(map[string]any)
+	out := make(map[string]*jsonschema.Schema, len(content))
+
+	for mediaType, value := range content {
+		entry, _ := value.(map[string]any)
+
+		schema, err := d.schema(entry, pointer+"/content/"+escapePointerToken(mediaType))
+		if err != nil {
+			return nil, err
+		}
+
+		out[strings.ToLower(mediaType)] = schema
+	}
+
+	return out, nil
+}
+
+// schema compiles the schema of a parameter or a media type. Schemas are only compiled when validation is enabled.
+func (d *openAPIDocument) schema(node map[string]any, pointer string) (*jsonschema.Schema, error) {
+	if d.compiler == nil || node["schema"] == nil {
+		return nil, nil
+	}
+
+	pointer += "/schema"
+	location := openAPISchemaLocation + "#" + (&url.URL{Fragment: pointer}).EscapedFragment()
+
+	schema, err := d.compiler.Compile(location)
+	if err != nil {
+		return nil, fmt.Errorf("compile schema at %s: %w", pointer, err)
+	}
+
+	return schema, nil
+}
+
+// resolve follows the local references of a document object and returns the object with its JSON pointer.
+func (d *openAPIDocument) resolve(value any, pointer string) (map[string]any, string, error) {
+	for range 32 {
+		node, ok := value.(map[string]any)
+		if !ok {
+			return nil, "", fmt.Errorf("expected an object at %s", pointer)
+		}
+
+		ref, ok := node["$ref"].(string)
+		if !ok {
+			return node, pointer, nil
+		}
+
+		if !strings.HasPrefix(ref, "#") {
+			return nil, "", fmt.Errorf("reference %q at %s is not local; only references within the document are supported", ref, pointer)
+		}
+
+		fragment, err := url.PathUnescape(ref[1:])
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid reference %q: %w", ref, err)
+		}
+
+		if value, err = d.lookup(fragment); err != nil {
+			return nil, "", fmt.Errorf("reference %q: %w", ref, err)
+		}
+
+		pointer = fragment
+	}
+
+	return nil, "", fmt.Errorf("too many nested references at %s", pointer)
+}
+
+// lookup returns the value at a JSON pointer of the document.
+func (d *openAPIDocument) lookup(pointer string) (any, error) {
+	var value any = d.root
+	if pointer == "" {
+		return value, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+
+		switch current := value.(type) {
+		case map[string]any:
+			next, found := current[token]
+			if !found {
+				return nil, fmt.Errorf("%s does not exist", pointer)
+			}
+
+			value = next
+		case []any:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(current) {
+				return nil, fmt.Errorf("%s does not exist", pointer)
+			}
+
+			value = current[idx]
+		default:
+			return nil, fmt.Errorf("%s does not exist", pointer)
+		}
+	}
+
+	return value, nil
+}
+
+// openAPIPathTemplate turns an OpenAPI path into a URI template. Path parameter names may contain characters
+// that are not valid in template variable names, like the dash of {user-id}, so those are percent-encoded
+// and the variable still reads the WITH.path key of the parameter.
+func openAPIPathTemplate(path string) string {
+	var out strings.Builder
+	rest := path
+
+	for {
+		start := strings.IndexByte(rest, '{')
+		end := strings.IndexByte(rest, '}')
+		if start < 0 || end < start {
+			out.WriteString(rest)
+
+			return out.String()
+		}
+
+		out.WriteString(rest[:start+1])
+
+		for _, c := range []byte(rest[start+1 : end]) {
+			if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' {
+				out.WriteByte(c)
+			} else {
+				fmt.Fprintf(&out, "%%%02X", c)
+			}
+		}
+
+		out.WriteByte('}')
+		rest = rest[end+1:]
+	}
+}
+
+func escapePointerToken(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// preferredMediaType returns the request media type of an operation: JSON when documented, otherwise the first
+// media type with a request codec.
+func preferredMediaType(mediaTypes []string) (string, Encoding) {
+	var fallback string
+	var fallbackEncoding Encoding
+
+	for _, mediaType := range mediaTypes {
+		encoding, ok := requestEncodingForMediaType(mediaType)
+		if !ok {
+			continue
+		}
+
+		if encoding == EncodingJSON {
+			return mediaType, encoding
+		}
+
+		if fallback == "" {
+			fallback, fallbackEncoding = mediaType, encoding
+		}
+	}
+
+	return fallback, fallbackEncoding
+}
+
+// requestEncodingForMediaType returns the request codec of a documented media type. Wildcards have none.
+func requestEncodingForMediaType(mediaType string) (Encoding, bool) {
+	parsed, _, err := mime.ParseMediaType(mediaType)
+	if err != nil || strings.Contains(parsed, "*") {
+		return "", false
+	}
+
+	if parsed == "multipart/form-data" {
+		return EncodingMultipart, true
+	}
+
+	encoding := encodingForMediaType(parsed)
+	if isRecordEncoding(encoding) {
+		return "", false
+	}
+
+	return encoding, true
+}
@@ -0,0 +1,296 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	jsonschema "github.com/santhosh-tekuri/jsonschema/v6"
+
+	ferrethttp "github.com/MontFerret/ferret/v2/pkg/net/http"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+// OpenAPIValidationError reports a request or a response that does not match its OpenAPI operation.
+// Request errors unwrap to runtime.ErrInvalidArgument and response errors to runtime.ErrUnexpected.
+type OpenAPIValidationError struct {
+	Cause     error
+	Operation string
+	Subject   string
+	Response  bool
+}
+
+func (e *OpenAPIValidationError) Error() string {
+	return fmt.Sprintf("OpenAPI operation %q: invalid %s: %v", e.Operation, e.Subject, e.Cause)
+}
+
+func (e *OpenAPIValidationError) Unwrap() error {
+	if e.Response {
+		return runtime.ErrUnexpected
+	}
+
+	return runtime.ErrInvalidArgument
+}
+
+// newOpenAPICompiler registers the document as a schema resource, so schemas are compiled by their JSON pointers
+// and resolve references to components. OpenAPI 3.0 schemas are an extended subset of draft 4.
+func newOpenAPICompiler(root map[string]any) (*jsonschema.Compiler, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+
+	if version, _ := root["openapi"].(string); strings.HasPrefix(version, "3.0") {
+		compiler.DefaultDraft(jsonschema.Draft4)
+		rewriteNullable(root)
+	}
+
+	if err := compiler.AddResource(openAPISchemaLocation, root); err != nil {
+		return nil, fmt.Errorf("load OpenAPI schemas: %w", err)
+	}
+
+	return compiler, nil
+}
+
+// rewriteNullable replaces the nullable keyword of OpenAPI 3.0 schemas with a null type.
+func rewriteNullable(value any) {
+	switch node := value.(type) {
+	case map[string]any:
+		if nullable, _ := node["nullable"].(bool); nullable {
+			if kind, ok := node["type"].(string); ok {
+				node["type"] = []any{kind, "null"}
+			}
+			if enum, ok := node["enum"].([]any); ok {
+				node["enum"] = append(enum, nil)
+			}
+		}
+
+		for _, item := range node {
+			rewriteNullable(item)
+		}
+	case []any:
+		for _, item := range node {
+			rewriteNullable(item)
+		}
+	}
+}
+
+// validateRequest checks the parameters and the body of a request. Header parameters are only checked for presence.
+func (op *openAPIOperation) validateRequest(ctx context.Context, data RequestData, headers http.Header, encoding Encoding) error {
+	path, err := openAPIValues(ctx, data.Path, "HTTP query WITH.path")
+	if err != nil {
+		return err
+	}
+
+	query, err := openAPIValues(ctx, data.Query, "HTTP query WITH.query")
+	if err != nil {
+		return err
+	}
+
+	for _, param := range op.parameters {
+		var value any
+		var found bool
+
+		switch param.in {
+		case "path":
+			value, found = path[param.name]
+		case "query":
+			value, found = query[param.name]
+		case "header":
+			if param.required && !hasHeader(headers, param.name) {
+				return op.requestError("header "+param.name, fmt.Errorf("required header is missing"))
+			}
+
+			continue
+		default:
+			continue
+		}
+
+		subject := param.in + " parameter " + param.name
+
+		if !found {
+			if param.required {
+				return op.requestError(subject, fmt.Errorf("required parameter is missing"))
+			}
+
+			continue
+		}
+
+		if param.schema != nil {
+			if err := param.schema.Validate(value); err != nil {
+				return op.requestError(subject, err)
+			}
+		}
+	}
+
+	if op.requestBody == nil {
+		return nil
+	}
+
+	if !data.HasBody {
+		if op.requestBody.required {
+			return op.requestError("request body", fmt.Errorf("required body is missing"))
+		}
+
+		return nil
+	}
+
+	schema := op.requestBody.schemaFor(op.mediaType, encoding)
+	if schema == nil || !isSchemaEncoding(encoding) {
+		return nil
+	}
+
+	instance, err := openAPIInstance(ctx, data.Body)
+	if err != nil {
+		return err
+	}
+
+	if err := schema.Validate(instance); err != nil {
+		return op.requestError("request body", err)
+	}
+
+	return nil
+}
+
+// validateResponse checks that the status and the content type of a response are documented and that its decoded
+// body matches the schema of its media type.
+func (op *openAPIOperation) validateResponse(ctx context.Context, resp *ferrethttp.Response, encoding Encoding, body runtime.Value) error {
+	subject := "response " + strconv.Itoa(resp.StatusCode)
+
+	response, found := op.response(resp.StatusCode)
+	if !found {
+		return op.responseError(subject, fmt.Errorf("status is not documented"))
+	}
+
+	if len(response.content) == 0 || len(bytes.TrimSpace(resp.Body)) == 0 {
+		return nil
+	}
+
+	contentType := http.Header(resp.Headers).Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	schema, found := response.schemaFor(mediaType)
+	if !found {
+		return op.responseError(subject, fmt.Errorf("content type %q is not documented", contentType))
+	}
+
+	if schema == nil || !isSchemaEncoding(encoding) {
+		return nil
+	}
+
+	instance, err := openAPIInstance(ctx, body)
+	if err != nil {
+		return err
+	}
+
+	if err := schema.Validate(instance); err != nil {
+		return op.responseError(subject, err)
+	}
+
+	return nil
+}
+
+// response returns the response documented for a status: its exact code, its range like 2XX or the default.
+func (op *openAPIOperation) response(status int) (*openAPIResponse, bool) {
+	for _, code := range []string{strconv.Itoa(status), strconv.Itoa(status/100) + "XX", "DEFAULT"} {
+		if response, found := op.responses[code]; found {
+			return response, true
+		}
+	}
+
+	return nil, false
+}
+
+func (op *openAPIOperation) requestError(subject string, err error) error {
+	return &OpenAPIValidationError{
+		Cause:     err,
+		Operation: op.id,
+		Subject:   subject,
+	}
+}
+
+func (op *openAPIOperation) responseError(subject string, err error) error {
+	return &OpenAPIValidationError{
+		Cause:     err,
+		Operation: op.id,
+		Subject:   subject,
+		Response:  true,
+	}
+}
+
+// schemaFor returns the schema of the media type that is sent with an encoding: the operation media type
+// for its own encoding, otherwise the first media type with the encoding.
+func (b *openAPIRequestBody) schemaFor(mediaType string, encoding Encoding) *jsonschema.Schema {
+	if documented, ok := requestEncodingForMediaType(mediaType); ok && documented == encoding {
+		return b.content[mediaType]
+	}
+
+	for _, candidate := range slices.Sorted(maps.Keys(b.content)) {
+		if documented, ok := requestEncodingForMediaType(candidate); ok && documented == encoding {
+			return b.content[candidate]
+		}
+	}
+
+	return nil
+}
+
+// schemaFor returns the schema of a response media type, matching media ranges like application/* too.
+func (r *openAPIResponse) schemaFor(mediaType string) (*jsonschema.Schema, bool) {
+	kind, _, _ := strings.Cut(mediaType, "/")
+
+	for _, candidate := range []string{mediaType, kind + "/*", "*/*"} {
+		if schema, found := r.content[candidate]; found {
+			return schema, true
+		}
+	}
+
+	return nil, false
+}
+
+// isSchemaEncoding reports whether values of an encoding are described by JSON Schema.
+// XML documents, multipart files, raw bytes and record streams are not.
+func isSchemaEncoding(encoding Encoding) bool {
+	switch encoding {
+	case EncodingXML, EncodingMultipart, EncodingBytes, EncodingNDJSON, EncodingSSE:
+		return false
+	default:
+		return true
+	}
+}
+
+// openAPIValues converts the path or query values of a request to their JSON form.
+func openAPIValues(ctx context.Context, value runtime.Value, owner string) (map[string]any, error) {
+	if runtime.TypeNone.Is(value) {
+		return map[string]any{}, nil
+	}
+
+	obj, err := requireMap(ctx, value, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	native, err := mapToNative(ctx, obj)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", owner, err)
+	}
+
+	values, err := openAPIJSON(native)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", owner, err)
+	}
+
+	return values.(map[string]any), nil
+}
+
+func openAPIInstance(ctx context.Context, value runtime.Value) (any, error) {
+	native, err := runtimeToNative(ctx, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return openAPIJSON(native)
+}
@@ -0,0 +1,460 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+const openAPIUsersSpec = `
+openapi: 3.0.3
+info:
+  title: Users
+  version: "1"
+servers:
+  - url: "{origin}/v1"
+    description: production
+    variables:
+      origin:
+        default: https://api.example.test
+paths:
+  /users:
+    post:
+      operationId: createUser
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/NewUser"
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/User"
+  /users/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+    get:
+      operationId: getUser
+      parameters:
+        - name: fields
+          in: query
+          schema:
+            type: string
+            enum: [name, all]
+      responses:
+        "200":
+          description: A user
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/User"
+        4XX:
+          description: An error
+          content:
+            application/problem+json:
+              schema:
+                type: object
+components:
+  schemas:
+    NewUser:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+    User:
+      type: object
+      required: [id, name]
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+        email:
+          type: string
+          nullable: true
+`
+
+func TestOpenAPIClientOperations(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept"); got != "application/json" {
+			t.Fatalf("expected the documented Accept header, got %q", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v1/users/7":
+			if got := r.URL.Query().Get("fields"); got != "all" {
+				t.Fatalf("expected the fields query parameter, got %q", got)
+			}
+
+			_, _ = w.Write([]byte(`{"id":7,"name":"Ada","email":null}`))
+		case "POST /v1/users":
+			if got := r.Header.Get("Content-Type"); got != "application/json" {
+				t.Fatalf("expected a JSON body, got %q", got)
+			}
+
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":8,"name":"Grace"}`))
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg, api, err := DecodeOpenAPIConfig(ctx, runtime.NewString(openAPIUsersSpec), object(t, map[string]runtime.Value{
+		"server":          runtime.NewString("production"),
+		"serverVariables": object(t, map[string]runtime.Value{"origin": runtime.NewString(server.URL)}),
+		"validate":        runtime.True,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected config error: %v", err)
+	}
+	if cfg.BaseURL != server.URL+"/v1" {
+		t.Fatalf("expected the selected server, got %q", cfg.BaseURL)
+	}
+
+	client := NewOpenAPIClient(cfg, api)
+
+	user, err := client.QueryOne(ctx, runtime.Query{
+		Expression: runtime.NewString("getUser"),
+		Params: object(t, map[string]runtime.Value{
+			"path":  object(t, map[string]runtime.Value{"id": runtime.NewInt(7)}),
+			"query": object(t, map[string]runtime.Value{"fields": runtime.NewString("all")}),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+	if got := field(t, user, "name"); got != runtime.NewString("Ada") {
+		t.Fatalf("expected the user, got %s", user.String())
+	}
+
+	created, err := client.QueryOne(ctx, runtime.Query{
+		Expression: runtime.NewString("createUser"),
+		Params: object(t, map[string]runtime.Value{
+			"body": object(t, map[string]runtime.Value{"name": runtime.NewString("Grace")}),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+	if got := field(t, created, "id"); got != runtime.NewInt(8) {
+		t.Fatalf("expected the created user, got %s", created.String())
+	}
+
+	if _, err := client.Query(ctx, runtime.Query{Expression: runtime.NewString("/users/7")}); err == nil || !strings.Contains(err.Error(), "unknown OpenAPI operation") {
+		t.Fatalf("expected paths to be rejected in favor of operationIds, got %v", err)
+	}
+}
+
+func TestOpenAPIClientValidation(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		switch r.URL.Path {
+		case "/v1/users/1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"one","name":"Ada"}`))
+		case "/v1/users/2":
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`<h1>Not Found</h1>`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg, api, err := DecodeOpenAPIConfig(ctx, runtime.NewString(openAPIUsersSpec), object(t, map[string]runtime.Value{
+		"baseUrl":  runtime.NewString(server.URL + "/v1"),
+		"validate": runtime.True,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected config error: %v", err)
+	}
+
+	client := NewOpenAPIClient(cfg, api)
+
+	invalidRequests := []runtime.Query{
+		{
+			Expression: runtime.NewString("getUser"),
+		},
+		{
+			Expression: runtime.NewString("getUser"),
+			Params: object(t, map[string]runtime.Value{
+				"path":  object(t, map[string]runtime.Value{"id": runtime.NewInt(1)}),
+				"query": object(t, map[string]runtime.Value{"fields": runtime.NewString("email")}),
+			}),
+		},
+		{
+			Expression: runtime.NewString("createUser"),
+			Params: object(t, map[string]runtime.Value{
+				"body": object(t, map[string]runtime.Value{"name": runtime.NewInt(1)}),
+			}),
+		},
+		{
+			Expression: runtime.NewString("createUser"),
+		},
+	}
+
+	for _, query := range invalidRequests {
+		_, err := client.Query(ctx, query)
+
+		var validationErr *OpenAPIValidationError
+		if !errors.As(err, &validationErr) || validationErr.Response {
+			t.Fatalf("expected a request validation error for %s, got %v", query.Expression, err)
+		}
+		if !errors.Is(err, runtime.ErrInvalidArgument) {
+			t.Fatalf("expected an invalid argument error, got %v", err)
+		}
+	}
+
+	if got := requests.Load(); got != 0 {
+		t.Fatalf("expected invalid requests not to be sent, got %d requests", got)
+	}
+
+	for _, id := range []int{1, 2} {
+		_, err = client.Query(ctx, runtime.Query{
+			Expression: runtime.NewString("getUser"),
+			Params: object(t, map[string]runtime.Value{
+				"path": object(t, map[string]runtime.Value{"id": runtime.NewInt(id)}),
+			}),
+			Options: object(t, map[string]runtime.Value{
+				"errorMode": runtime.NewString("response"),
+			}),
+		})
+
+		var validationErr *OpenAPIValidationError
+		if !errors.As(err, &validationErr) || !validationErr.Response {
+			t.Fatalf("expected a response validation error for user %d, got %v", id, err)
+		}
+		if !errors.Is(err, runtime.ErrUnexpected) {
+			t.Fatalf("expected an unexpected error, got %v", err)
+		}
+	}
+}
+
+func TestOpenAPIClientPathParameterNames(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.EscapedPath(); got != "/users/ada%20l/posts/7" {
+			t.Fatalf("unexpected path %s", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	spec, err := json.Marshal(map[string]any{
+		"openapi": "3.1.0",
+		"paths": map[string]any{
+			"/users/{user-id}/posts/{post.id}": map[string]any{"get": map[string]any{"operationId": "getPost"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to encode document: %v", err)
+	}
+
+	ctx := networkContext(t)
+	cfg, api, err := DecodeOpenAPIConfig(ctx, runtime.NewString(string(spec)), object(t, map[string]runtime.Value{
+		"baseUrl": runtime.NewString(server.URL),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected config error: %v", err)
+	}
+
+	post, err := NewOpenAPIClient(cfg, api).QueryOne(ctx, runtime.Query{
+		Expression: runtime.NewString("getPost"),
+		Params: object(t, map[string]runtime.Value{
+			"path": object(t, map[string]runtime.Value{
+				"user-id": runtime.NewString("ada l"),
+				"post.id": runtime.NewInt(7),
+			}),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+	if got := field(t, post, "ok"); got != runtime.True {
+		t.Fatalf("expected the post, got %s", post.String())
+	}
+}
+
+func TestOpenAPIRelativeServersResolveAgainstBaseURL(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/pets", "/legacy/status":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		default:
+			t.Fatalf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	spec, err := json.Marshal(map[string]any{
+		"openapi": "3.1.0",
+		"servers": []any{map[string]any{"url": "/api/v3"}},
+		"paths": map[string]any{
+			"/pets": map[string]any{"get": map[string]any{"operationId": "listPets"}},
+			"/status": map[string]any{
+				"servers": []any{map[string]any{"url": "/legacy"}},
+				"get":     map[string]any{"operationId": "getStatus"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to encode document: %v", err)
+	}
+
+	ctx := networkContext(t)
+	cfg, api, err := DecodeOpenAPIConfig(ctx, runtime.NewString(string(spec)), object(t, map[string]runtime.Value{
+		"baseUrl": runtime.NewString(server.URL),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected config error: %v", err)
+	}
+	if cfg.BaseURL != server.URL+"/api/v3" {
+		t.Fatalf("expected the server to keep its path, got %q", cfg.BaseURL)
+	}
+
+	client := NewOpenAPIClient(cfg, api)
+
+	for _, operation := range []string{"listPets", "getStatus"} {
+		out, err := client.QueryOne(ctx, runtime.Query{Expression: runtime.NewString(operation)})
+		if err != nil {
+			t.Fatalf("unexpected %s error: %v", operation, err)
+		}
+		if got := field(t, out, "ok"); got != runtime.True {
+			t.Fatalf("unexpected %s result %s", operation, out.String())
+		}
+	}
+}
+
+func TestDecodeOpenAPIConfigErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	spec := func(document map[string]any) runtime.Value {
+		data, err := json.Marshal(document)
+		if err != nil {
+			t.Fatalf("failed to encode document: %v", err)
+		}
+
+		return runtime.NewString(string(data))
+	}
+	operation := func(id string) map[string]any {
+		return map[string]any{"operationId": id, "responses": map[string]any{}}
+	}
+
+	cases := []struct {
+		spec    runtime.Value
+		config  runtime.Value
+		message string
+	}{
+		{
+			spec:    spec(map[string]any{"swagger": "2.0"}),
+			message: "unsupported OpenAPI version",
+		},
+		{
+			spec: spec(map[string]any{
+				"openapi": "3.1.0",
+				"paths":   map[string]any{"/a": map[string]any{"get": operation("list")}},
+			}),
+			message: "baseUrl is required",
+		},
+		{
+			spec: spec(map[string]any{
+				"openapi": "3.1.0",
+				"servers": []any{map[string]any{"url": "/api"}},
+			}),
+			message: "is not absolute",
+		},
+		{
+			spec: spec(map[string]any{
+				"openapi": "3.1.0",
+				"servers": []any{map[string]any{"url": "https://api.example.test"}},
+			}),
+			config:  object(t, map[string]runtime.Value{"server": runtime.NewInt(1)}),
+			message: "out of range",
+		},
+		{
+			spec: spec(map[string]any{
+				"openapi": "3.1.0",
+				"paths": map[string]any{
+					"/a": map[string]any{"get": operation("list")},
+					"/b": map[string]any{"get": operation("list")},
+				},
+			}),
+			config:  runtime.NewString("https://api.example.test"),
+			message: "must be an object",
+		},
+		{
+			spec: spec(map[string]any{
+				"openapi": "3.1.0",
+				"paths": map[string]any{
+					"/a": map[string]any{"get": operation("list")},
+					"/b": map[string]any{"get": operation("list")},
+				},
+			}),
+			config:  object(t, map[string]runtime.Value{"baseUrl": runtime.NewString("https://api.example.test")}),
+			message: "duplicate OpenAPI operationId",
+		},
+		{
+			spec: spec(map[string]any{
+				"openapi": "3.1.0",
+				"paths": map[string]any{"/a": map[string]any{"get": map[string]any{
+					"operationId": "list",
+					"parameters":  []any{map[string]any{"$ref": "common.yaml#/Limit"}},
+				}}},
+			}),
+			config:  object(t, map[string]runtime.Value{"baseUrl": runtime.NewString("https://api.example.test")}),
+			message: "is not local",
+		},
+		{
+			spec:    spec(map[string]any{"openapi": "3.1.0"}),
+			config:  object(t, map[string]runtime.Value{"validate": runtime.NewString("always")}),
+			message: "unsupported mode",
+		},
+	}
+
+	for _, tc := range cases {
+		config := tc.config
+		if config == nil {
+			config = runtime.None
+		}
+
+		if _, _, err := DecodeOpenAPIConfig(ctx, tc.spec, config); err == nil || !strings.Contains(err.Error(), tc.message) {
+			t.Fatalf("expected an error containing %q, got %v", tc.message, err)
+		}
+	}
+}
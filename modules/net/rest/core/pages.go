@@ -0,0 +1,422 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	ferrethttp "github.com/MontFerret/ferret/v2/pkg/net/http"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+type (
+	// Pages is a paginated query. Every iteration starts again from the first page.
+	Pages struct {
+		exchange   *exchange
+		pagination Pagination
+	}
+
+	// PageIterator fetches pages on demand and yields their items.
+	PageIterator struct {
+		exchange   *exchange
+		seen       map[string]struct{}
+		pagination Pagination
+		items      []runtime.Value
+		nextURL    string
+		cursor     string
+		pos        int
+		pages      int64
+		yielded    int64
+		number     int64
+		done       bool
+	}
+)
+
+// defaultEagerMaxPages caps the pages read at once when neither maxPages nor maxItems is set,
+// as a collection that never returns an empty page would otherwise be read forever.
+const defaultEagerMaxPages = 100
+
+// Iterate returns an iterator positioned before the first page.
+func (p *Pages) Iterate(_ context.Context) (runtime.Iterator, error) {
+	return p.iterate(p.pagination), nil
+}
+
+func (p *Pages) iterate(pagination Pagination) *PageIterator {
+	return &PageIterator{
+		exchange:   p.exchange,
+		seen:       map[string]struct{}{p.exchange.url: {}},
+		pagination: pagination,
+		nextURL:    p.exchange.url,
+		number:     pagination.Start,
+	}
+}
+
+// eager returns an iterator for reading every page at once, capped at defaultEagerMaxPages unless a limit is set.
+func (p *Pages) eager() *PageIterator {
+	pagination := p.pagination
+
+	if pagination.MaxPages == 0 && pagination.MaxItems == 0 {
+		pagination.MaxPages = defaultEagerMaxPages
+	}
+
+	return p.iterate(pagination)
+}
+
+func (p *Pages) first(ctx context.Context) (runtime.Value, bool, error) {
+	iter, _ := p.Iterate(ctx)
+
+	item, _, err := iter.Next(ctx)
+	if errors.Is(err, io.EOF) {
+		return runtime.None, false, nil
+	}
+	if err != nil {
+		return runtime.None, false, err
+	}
+
+	return item, true, nil
+}
+
+// count walks every page without keeping the items.
+func (p *Pages) count(ctx context.Context) (runtime.Int, error) {
+	iter := p.eager()
+	count := 0
+
+	for {
+		_, _, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			return runtime.NewInt(count), nil
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		count++
+	}
+}
+
+func (p *Pages) collect(ctx context.Context) (runtime.List, error) {
+	iter := p.eager()
+	out := runtime.NewArray(0)
+
+	for {
+		item, _, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err := out.Append(ctx, item); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Next returns the next item and its 1-based position across all pages.
+func (i *PageIterator) Next(ctx context.Context) (runtime.Value, runtime.Value, error) {
+	for i.pos >= len(i.items) {
+		if i.done {
+			return runtime.None, runtime.None, io.EOF
+		}
+
+		if err := i.fetch(ctx); err != nil {
+			i.done = true
+
+			return runtime.None, runtime.None, OperationError("QUERY", err)
+		}
+	}
+
+	item := i.items[i.pos]
+	i.pos++
+	i.yielded++
+
+	if i.pagination.MaxItems > 0 && i.yielded >= i.pagination.MaxItems {
+		i.done = true
+		i.items = i.items[:i.pos]
+	}
+
+	return item, runtime.NewInt(int(i.yielded)), nil
+}
+
+func (i *PageIterator) fetch(ctx context.Context) error {
+	if i.pagination.Kind == PaginationRelay {
+		return i.fetchConnection(ctx)
+	}
+
+	requestURL, err := i.pageURL()
+	if err != nil {
+		return err
+	}
+
+	resp, attempts, err := i.exchange.send(ctx, requestURL, i.exchange.body)
+	if err != nil {
+		return err
+	}
+
+	i.pages++
+	i.items = nil
+	i.pos = 0
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		// the failed response ends the iteration, either as an error or as the last item
+		i.done = true
+
+		failed, _, err := i.exchange.decodeHTTPResponse(ctx, requestURL, resp, attempts)
+		if err != nil {
+			return err
+		}
+
+		i.items = []runtime.Value{failed}
+
+		return nil
+	}
+
+	body, err := i.exchange.decodeBody(ctx, resp)
+	if err != nil {
+		return err
+	}
+
+	if i.items, err = pageItems(ctx, body, i.pagination.ItemsPath); err != nil {
+		return err
+	}
+
+	return i.advance(ctx, resp, requestURL, body)
+}
+
+// pageURL returns the URL of the next page.
+// Link pagination follows the server URL, other types set their parameters on the query URL.
+func (i *PageIterator) pageURL() (string, error) {
+	p := i.pagination
+
+	if p.Kind == PaginationLink {
+		return i.nextURL, nil
+	}
+
+	parsed, err := url.Parse(i.exchange.url)
+	if err != nil {
+		return "", err
+	}
+
+	values := parsed.Query()
+
+	switch p.Kind {
+	case PaginationCursor:
+		if i.cursor != "" {
+			values.Set(p.CursorParam, i.cursor)
+		}
+	case PaginationPage:
+		values.Set(p.PageParam, strconv.FormatInt(i.number, 10))
+	case PaginationOffset:
+		values.Set(p.OffsetParam, strconv.FormatInt(i.number, 10))
+	}
+
+	if p.Limit > 0 {
+		values.Set(p.LimitParam, strconv.FormatInt(p.Limit, 10))
+	}
+
+	parsed.RawQuery = values.Encode()
+
+	return parsed.String(), nil
+}
+
+// fetchConnection fetches the next page of a Relay connection by setting the end cursor of the previous page as a variable.
+func (i *PageIterator) fetchConnection(ctx context.Context) error {
+	ex := i.exchange
+
+	body, err := ex.graphql.encode(i.pagination.CursorVariable, i.cursor)
+	if err != nil {
+		return err
+	}
+
+	resp, attempts, err := ex.send(ctx, ex.url, body)
+	if err != nil {
+		return err
+	}
+
+	i.pages++
+	i.items = nil
+	i.pos = 0
+
+	opts := ex.options
+	opts.ResponseMode = ResponseModeBody
+
+	data, ok, err := decodeGraphQLResponse(ctx, ex.url, resp, attempts, opts)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		// the failed response ends the iteration as the last item
+		i.done = true
+		i.items = []runtime.Value{data}
+
+		return nil
+	}
+
+	connection, found, err := lookupPath(ctx, data, i.pagination.ConnectionPath)
+	if err != nil {
+		return err
+	}
+
+	if !found || runtime.TypeNone.Is(connection) {
+		i.done = true
+
+		return nil
+	}
+
+	if i.items, err = connectionNodes(ctx, connection); err != nil {
+		return err
+	}
+
+	if len(i.items) == 0 || (i.pagination.MaxPages > 0 && i.pages >= i.pagination.MaxPages) {
+		i.done = true
+
+		return nil
+	}
+
+	hasNext, _, err := lookupPath(ctx, connection, "pageInfo.hasNextPage")
+	if err != nil {
+		return err
+	}
+
+	endCursor, found, err := lookupPath(ctx, connection, "pageInfo.endCursor")
+	if err != nil {
+		return err
+	}
+
+	cursor := ""
+	if found && !runtime.TypeNone.Is(endCursor) {
+		cursor = endCursor.String()
+	}
+
+	i.done = hasNext != runtime.True || cursor == "" || i.visit(cursor)
+	i.cursor = cursor
+
+	return nil
+}
+
+// visit records the URL or cursor of the next page and reports whether it was reached before.
+// A link or cursor that leads back to an earlier page would repeat the same pages forever.
+func (i *PageIterator) visit(key string) bool {
+	if _, ok := i.seen[key]; ok {
+		return true
+	}
+
+	i.seen[key] = struct{}{}
+
+	return false
+}
+
+// connectionNodes returns the nodes of a connection from edges[].node or, without edges, from nodes.
+func connectionNodes(ctx context.Context, connection runtime.Value) ([]runtime.Value, error) {
+	if _, found, err := lookupPath(ctx, connection, "edges"); err != nil {
+		return nil, err
+	} else if !found {
+		return pageItems(ctx, connection, "nodes")
+	}
+
+	edges, err := pageItems(ctx, connection, "edges")
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]runtime.Value, 0, len(edges))
+
+	for _, edge := range edges {
+		node, _, err := lookupPath(ctx, edge, "node")
+		if err != nil {
+			return nil, err
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return nodes, nil
+}
+
+func pageItems(ctx context.Context, body runtime.Value, path string) ([]runtime.Value, error) {
+	value, found, err := lookupPath(ctx, body, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found || runtime.TypeNone.Is(value) {
+		return nil, nil
+	}
+
+	list, ok := value.(runtime.List)
+	if !ok {
+		return []runtime.Value{value}, nil
+	}
+
+	items := make([]runtime.Value, 0)
+	iter, err := list.Iterate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		item, _, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			return items, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+}
+
+// advance prepares the next page and stops when a page is empty, the server reports no more pages or a limit is reached.
+func (i *PageIterator) advance(ctx context.Context, resp *ferrethttp.Response, requestURL string, body runtime.Value) error {
+	p := i.pagination
+
+	if len(i.items) == 0 || (p.MaxPages > 0 && i.pages >= p.MaxPages) {
+		i.done = true
+
+		return nil
+	}
+
+	if p.HasMorePath != "" {
+		more, found, err := lookupPath(ctx, body, p.HasMorePath)
+		if err != nil {
+			return err
+		}
+
+		if found && (runtime.TypeNone.Is(more) || more == runtime.False) {
+			i.done = true
+
+			return nil
+		}
+	}
+
+	switch p.Kind {
+	case PaginationLink:
+		i.nextURL = nextLink(http.Header(resp.Headers), requestURL)
+		i.done = i.nextURL == "" || i.visit(i.nextURL)
+	case PaginationCursor:
+		next, found, err := lookupPath(ctx, body, p.NextCursorPath)
+		if err != nil {
+			return err
+		}
+
+		cursor := ""
+		if found && !runtime.TypeNone.Is(next) {
+			cursor = next.String()
+		}
+
+		i.done = cursor == "" || i.visit(cursor)
+		i.cursor = cursor
+	case PaginationPage:
+		i.number++
+	case PaginationOffset:
+		i.number += int64(len(i.items))
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+// Pagination describes how a query advances through the pages of a collection.
+type Pagination struct {
+	Kind           PaginationKind
+	ItemsPath      string
+	NextCursorPath string
+	HasMorePath    string
+	CursorParam    string
+	PageParam      string
+	OffsetParam    string
+	LimitParam     string
+	ConnectionPath string
+	CursorVariable string
+	Limit          int64
+	Start          int64
+	MaxPages       int64
+	MaxItems       int64
+}
+
+const paginationOwner = "HTTP query OPTIONS.paginate"
+
+// DecodePagination decodes a paginate option.
+// true follows Link headers, a string selects the pagination type and an object configures it.
+func DecodePagination(ctx context.Context, value runtime.Value) (*Pagination, error) {
+	p := &Pagination{
+		Kind:           PaginationLink,
+		CursorParam:    "cursor",
+		PageParam:      "page",
+		OffsetParam:    "offset",
+		CursorVariable: "after",
+	}
+
+	switch typed := value.(type) {
+	case runtime.Boolean:
+		if !typed {
+			return nil, nil
+		}
+
+		return p, nil
+	case runtime.String:
+		kind, err := parsePaginationKind(typed.String())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", paginationOwner, err)
+		}
+
+		p.Kind = kind
+
+		if kind == PaginationPage {
+			p.Start = 1
+		}
+
+		return p, p.validate()
+	}
+
+	obj, err := requireMap(ctx, value, paginationOwner)
+	if err != nil {
+		return nil, fmt.Errorf("%s, a string or a boolean", err.Error())
+	}
+
+	for key, target := range map[string]*string{
+		"itemsPath":      &p.ItemsPath,
+		"nextCursorPath": &p.NextCursorPath,
+		"hasMorePath":    &p.HasMorePath,
+		"cursorParam":    &p.CursorParam,
+		"pageParam":      &p.PageParam,
+		"offsetParam":    &p.OffsetParam,
+		"limitParam":     &p.LimitParam,
+		"connectionPath": &p.ConnectionPath,
+		"cursorVariable": &p.CursorVariable,
+	} {
+		if str, found, err := lookupString(ctx, obj, key, paginationOwner); err != nil {
+			return nil, err
+		} else if found {
+			*target = strings.TrimSpace(str)
+		}
+	}
+
+	start := int64(-1)
+
+	for key, target := range map[string]*int64{
+		"limit":    &p.Limit,
+		"start":    &start,
+		"maxPages": &p.MaxPages,
+		"maxItems": &p.MaxItems,
+	} {
+		if integer, found, err := lookupInt(ctx, obj, key, paginationOwner); err != nil {
+			return nil, err
+		} else if found {
+			if integer < 0 {
+				return nil, fmt.Errorf("%s.%s must be greater than or equal to 0", paginationOwner, key)
+			}
+
+			*target = integer
+		}
+	}
+
+	kind, found, err := lookupString(ctx, obj, "type", paginationOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case found:
+		p.Kind, err = parsePaginationKind(kind)
+		if err != nil {
+			return nil, fmt.Errorf("%s.type: %w", paginationOwner, err)
+		}
+	case p.ConnectionPath != "":
+		p.Kind = PaginationRelay
+	case p.NextCursorPath != "":
+		p.Kind = PaginationCursor
+	}
+
+	switch {
+	case start >= 0:
+		p.Start = start
+	case p.Kind == PaginationPage:
+		// page numbers start at 1 unless configured otherwise
+		p.Start = 1
+	}
+
+	return p, p.validate()
+}
+
+func (p *Pagination) validate() error {
+	switch p.Kind {
+	case PaginationCursor:
+		if p.NextCursorPath == "" {
+			return fmt.Errorf("%s.nextCursorPath is required for cursor pagination", paginationOwner)
+		}
+
+		if p.CursorParam == "" {
+			return fmt.Errorf("%s.cursorParam must not be empty", paginationOwner)
+		}
+	case PaginationPage:
+		if p.PageParam == "" {
+			return fmt.Errorf("%s.pageParam must not be empty", paginationOwner)
+		}
+	case PaginationOffset:
+		if p.OffsetParam == "" {
+			return fmt.Errorf("%s.offsetParam must not be empty", paginationOwner)
+		}
+	case PaginationRelay:
+		if p.ConnectionPath == "" {
+			return fmt.Errorf("%s.connectionPath is required for relay pagination", paginationOwner)
+		}
+
+		if p.CursorVariable == "" {
+			return fmt.Errorf("%s.cursorVariable must not be empty", paginationOwner)
+		}
+	}
+
+	if p.Limit > 0 && p.LimitParam == "" {
+		p.LimitParam = "limit"
+	}
+
+	return nil
+}
@@ -0,0 +1,591 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+func TestClientPaginatesLinkHeaders(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+
+		if page < 3 {
+			w.Header().Set("Link", fmt.Sprintf(`</users?page=%d>; rel="next", </users?page=3>; rel="last"`, page+1))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{"id": page*2 - 1},
+			{"id": page * 2},
+		})
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+
+	out, err := NewClient(cfg).Query(ctx, runtime.Query{
+		Expression: runtime.NewString("/users"),
+		Options: object(t, map[string]runtime.Value{
+			"paginate": runtime.True,
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+
+	assertIDs(t, ctx, out, 1, 2, 3, 4, 5, 6)
+
+	if got := requests.Load(); got != 3 {
+		t.Fatalf("expected 3 page requests, got %d", got)
+	}
+}
+
+func TestClientPaginatesCursors(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]map[string]any{
+		"":   {"data": map[string]any{"items": []int{1, 2}}, "meta": map[string]any{"next": "c2"}},
+		"c2": {"data": map[string]any{"items": []int{3}}, "meta": map[string]any{"next": nil}},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("status") != "open" {
+			t.Fatalf("expected request query parameters on every page")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(pages[r.URL.Query().Get("after")])
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+
+	out, err := NewClient(cfg).Query(ctx, runtime.Query{
+		Expression: runtime.NewString("/tickets"),
+		Params: object(t, map[string]runtime.Value{
+			"query": object(t, map[string]runtime.Value{
+				"status": runtime.NewString("open"),
+			}),
+		}),
+		Options: object(t, map[string]runtime.Value{
+			"paginate": object(t, map[string]runtime.Value{
+				"itemsPath":      runtime.NewString("data.items"),
+				"nextCursorPath": runtime.NewString("meta.next"),
+				"cursorParam":    runtime.NewString("after"),
+			}),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+
+	assertValues(t, ctx, out, 1, 2, 3)
+}
+
+func TestClientPaginatesCounters(t *testing.T) {
+	t.Parallel()
+
+	items := []int{1, 2, 3, 4, 5}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		if limit != 2 {
+			t.Fatalf("expected per_page=2, got %q", r.URL.Query().Get("per_page"))
+		}
+
+		start := 0
+		switch {
+		case r.URL.Query().Has("page"):
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			start = (page - 1) * limit
+		case r.URL.Query().Has("offset"):
+			start, _ = strconv.Atoi(r.URL.Query().Get("offset"))
+		}
+
+		end := min(start+limit, len(items))
+		start = min(start, end)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(items[start:end])
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+	client := NewClient(cfg)
+
+	for _, kind := range []string{"page", "offset"} {
+		out, err := client.Query(ctx, runtime.Query{
+			Expression: runtime.NewString("/items"),
+			Options: object(t, map[string]runtime.Value{
+				"paginate": object(t, map[string]runtime.Value{
+					"type":       runtime.NewString(kind),
+					"limit":      runtime.NewInt(2),
+					"limitParam": runtime.NewString("per_page"),
+				}),
+			}),
+		})
+		if err != nil {
+			t.Fatalf("unexpected %s query error: %v", kind, err)
+		}
+
+		assertValues(t, ctx, out, 1, 2, 3, 4, 5)
+	}
+}
+
+func TestClientPaginationStopConditions(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"items":   []int{page*10 + 1, page*10 + 2},
+			"hasMore": page < 2,
+		})
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+	client := NewClient(cfg)
+
+	query := func(paginate map[string]runtime.Value) runtime.List {
+		t.Helper()
+
+		requests.Store(0)
+		paginate["type"] = runtime.NewString("page")
+		paginate["itemsPath"] = runtime.NewString("items")
+
+		out, err := client.Query(ctx, runtime.Query{
+			Expression: runtime.NewString("/items"),
+			Options: object(t, map[string]runtime.Value{
+				"paginate": object(t, paginate),
+			}),
+		})
+		if err != nil {
+			t.Fatalf("unexpected query error: %v", err)
+		}
+
+		return out
+	}
+
+	assertValues(t, ctx, query(map[string]runtime.Value{
+		"hasMorePath": runtime.NewString("hasMore"),
+	}), 11, 12, 21, 22)
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("expected hasMorePath to stop after 2 pages, got %d", got)
+	}
+
+	assertValues(t, ctx, query(map[string]runtime.Value{
+		"maxPages": runtime.NewInt(1),
+	}), 11, 12)
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("expected maxPages to stop after 1 page, got %d", got)
+	}
+
+	assertValues(t, ctx, query(map[string]runtime.Value{
+		"hasMorePath": runtime.NewString("hasMore"),
+		"maxItems":    runtime.NewInt(3),
+	}), 11, 12, 21)
+}
+
+func TestClientPaginatedOneExistsAndCount(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		items := []int{}
+		if page <= 3 {
+			items = []int{page, page}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(items)
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+	client := NewClient(cfg)
+	query := runtime.Query{
+		Expression: runtime.NewString("/items"),
+		Options: object(t, map[string]runtime.Value{
+			"paginate": runtime.NewString("page"),
+		}),
+	}
+
+	exists, err := client.QueryExists(ctx, query)
+	if err != nil {
+		t.Fatalf("unexpected exists error: %v", err)
+	}
+	if exists != runtime.True || requests.Load() != 1 {
+		t.Fatalf("expected EXISTS to fetch a single page, got %v after %d requests", exists, requests.Load())
+	}
+
+	requests.Store(0)
+	one, err := client.QueryOne(ctx, query)
+	if err != nil {
+		t.Fatalf("unexpected one error: %v", err)
+	}
+	if one != runtime.NewInt(1) || requests.Load() != 1 {
+		t.Fatalf("expected ONE to return the first item from a single page, got %s after %d requests", one.String(), requests.Load())
+	}
+
+	requests.Store(0)
+	count, err := client.QueryCount(ctx, query)
+	if err != nil {
+		t.Fatalf("unexpected count error: %v", err)
+	}
+	if count != 6 || requests.Load() != 4 {
+		t.Fatalf("expected COUNT to walk every page, got %d after %d requests", count, requests.Load())
+	}
+}
+
+func TestClientPaginateIsLazy(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := requests.Add(1)
+
+		w.Header().Set("Link", fmt.Sprintf(`</items?page=%d>; rel="next"`, page+1))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]int{1, 2})
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+
+	pages, err := NewClient(cfg).Paginate(ctx, runtime.Query{Expression: runtime.NewString("/items")})
+	if err != nil {
+		t.Fatalf("unexpected paginate error: %v", err)
+	}
+
+	iter, err := pages.Iterate(ctx)
+	if err != nil {
+		t.Fatalf("unexpected iterate error: %v", err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		_, key, err := iter.Next(ctx)
+		if err != nil {
+			t.Fatalf("unexpected next error: %v", err)
+		}
+		if key != runtime.NewInt(i) {
+			t.Fatalf("expected position %d, got %s", i, key.String())
+		}
+	}
+
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("expected 2 page requests for 3 items, got %d", got)
+	}
+}
+
+func TestClientPaginateStopsOnRepeatedLink(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		w.Header().Set("Link", `</items?page=2>; rel="next"`)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]int{1})
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+
+	out, err := NewClient(cfg).Query(ctx, runtime.Query{
+		Expression: runtime.NewString("/items"),
+		Options:    object(t, map[string]runtime.Value{"paginate": runtime.True}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+
+	assertValues(t, ctx, out, 1, 1)
+
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("expected the repeated link to end pagination after 2 requests, got %d", got)
+	}
+}
+
+func TestClientPaginateStopsOnLinkCycle(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		// page 1 links to page 2 and page 2 back to page 1
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+
+		w.Header().Set("Link", fmt.Sprintf(`</items?page=%d>; rel="next"`, 3-page))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]int{page})
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+
+	out, err := NewClient(cfg).Query(ctx, runtime.Query{
+		Expression: runtime.NewString("/items?page=1"),
+		Options:    object(t, map[string]runtime.Value{"paginate": runtime.True}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+
+	assertValues(t, ctx, out, 1, 2)
+
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("expected the link cycle to end pagination after 2 requests, got %d", got)
+	}
+}
+
+func TestClientQueryCapsEagerPagination(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		// the counter never reaches an empty page
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]int{1})
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+
+	out, err := NewClient(cfg).Query(ctx, runtime.Query{
+		Expression: runtime.NewString("/items"),
+		Options:    object(t, map[string]runtime.Value{"paginate": runtime.NewString("page")}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+
+	if got := requests.Load(); got != defaultEagerMaxPages {
+		t.Fatalf("expected %d page requests, got %d", defaultEagerMaxPages, got)
+	}
+
+	length, err := out.Length(ctx)
+	if err != nil {
+		t.Fatalf("unexpected length error: %v", err)
+	}
+
+	if length != defaultEagerMaxPages {
+		t.Fatalf("expected %d items, got %d", defaultEagerMaxPages, length)
+	}
+}
+
+func TestClientPaginationErrors(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": "boom"})
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]int{1})
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+	client := NewClient(cfg)
+
+	_, err := client.Query(ctx, runtime.Query{
+		Expression: runtime.NewString("/items"),
+		Options: object(t, map[string]runtime.Value{
+			"paginate": runtime.NewString("page"),
+		}),
+	})
+	if err == nil {
+		t.Fatal("expected a failed page to raise an error")
+	}
+
+	out, err := client.Query(ctx, runtime.Query{
+		Expression: runtime.NewString("/items"),
+		Options: object(t, map[string]runtime.Value{
+			"paginate":  runtime.NewString("page"),
+			"errorMode": runtime.NewString("response"),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+
+	last, err := out.At(ctx, runtime.NewInt(1))
+	if err != nil {
+		t.Fatalf("unexpected last item error: %v", err)
+	}
+	if got := field(t, last, "status"); got != runtime.NewInt(http.StatusInternalServerError) {
+		t.Fatalf("expected the failed page as the last item, got %s", last.String())
+	}
+}
+
+func TestDecodePagination(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	pagination, err := DecodePagination(ctx, runtime.False)
+	if err != nil || pagination != nil {
+		t.Fatalf("expected false to disable pagination, got %+v, %v", pagination, err)
+	}
+
+	pagination, err = DecodePagination(ctx, object(t, map[string]runtime.Value{
+		"nextCursorPath": runtime.NewString("next"),
+		"limit":          runtime.NewInt(50),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected pagination error: %v", err)
+	}
+	if pagination.Kind != PaginationCursor || pagination.CursorParam != "cursor" || pagination.LimitParam != "limit" {
+		t.Fatalf("unexpected pagination: %+v", pagination)
+	}
+
+	pagination, err = DecodePagination(ctx, runtime.NewString("page"))
+	if err != nil || pagination.Start != 1 {
+		t.Fatalf("expected page numbers to start at 1, got %+v, %v", pagination, err)
+	}
+
+	for _, invalid := range []runtime.Value{
+		runtime.NewString("scroll"),
+		runtime.NewInt(1),
+		object(t, map[string]runtime.Value{"type": runtime.NewString("cursor")}),
+		object(t, map[string]runtime.Value{"maxPages": runtime.NewInt(-1)}),
+		object(t, map[string]runtime.Value{"type": runtime.NewString("page"), "pageParam": runtime.NewString(" ")}),
+	} {
+		if _, err := DecodePagination(ctx, invalid); err == nil {
+			t.Fatalf("expected paginate option %s to fail", invalid.String())
+		}
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		header string
+		want   string
+	}{
+		{`<https://api.example.com/items?page=2>; rel="next"`, "https://api.example.com/items?page=2"},
+		{`</items?page=1>; rel="prev", </items?a=1,2&page=3>; rel="next last"`, "https://api.example.com/items?a=1,2&page=3"},
+		{`<?page=4>; title="a, b"; rel=next`, "https://api.example.com/items?page=4"},
+		{`</items?page=9>; rel="last"`, ""},
+		{`not a link`, ""},
+		{`<https://other.example.com/items?page=2>; rel="next"`, ""},
+		{`<http://api.example.com/items?page=2>; rel="next"`, ""},
+	}
+
+	for _, tc := range cases {
+		headers := http.Header{}
+		headers.Set("Link", tc.header)
+
+		if got := nextLink(headers, "https://api.example.com/items?page=1"); got != tc.want {
+			t.Fatalf("nextLink(%q) = %q, want %q", tc.header, got, tc.want)
+		}
+	}
+}
+
+func assertIDs(t *testing.T, ctx context.Context, list runtime.List, ids ...int) {
+	t.Helper()
+
+	values := make([]int, 0, len(ids))
+	iterateList(t, ctx, list, func(item runtime.Value) {
+		id, err := strconv.Atoi(field(t, item, "id").String())
+		if err != nil {
+			t.Fatalf("unexpected id: %v", err)
+		}
+
+		values = append(values, id)
+	})
+
+	if fmt.Sprint(values) != fmt.Sprint(ids) {
+		t.Fatalf("expected ids %v, got %v", ids, values)
+	}
+}
+
+func assertValues(t *testing.T, ctx context.Context, list runtime.List, want ...int) {
+	t.Helper()
+
+	values := make([]string, 0, len(want))
+	iterateList(t, ctx, list, func(item runtime.Value) {
+		values = append(values, item.String())
+	})
+
+	if fmt.Sprint(values) != fmt.Sprint(want) {
+		t.Fatalf("expected items %v, got %v", want, values)
+	}
+}
+
+func iterateList(t *testing.T, ctx context.Context, list runtime.List, fn func(runtime.Value)) {
+	t.Helper()
+
+	iter, err := list.Iterate(ctx)
+	if err != nil {
+		t.Fatalf("unexpected iterate error: %v", err)
+	}
+
+	for {
+		item, _, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			t.Fatalf("unexpected next error: %v", err)
+		}
+
+		fn(item)
+	}
+}
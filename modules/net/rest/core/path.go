@@ -0,0 +1,55 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+// lookupPath follows a dot-separated path of object keys and array indexes, like "data.items" or "links.0.href".
+// An empty path returns the value itself.
+func lookupPath(ctx context.Context, value runtime.Value, path string) (runtime.Value, bool, error) {
+	if path == "" {
+		return value, true, nil
+	}
+
+	current := value
+
+	for _, segment := range strings.Split(path, ".") {
+		switch typed := current.(type) {
+		case runtime.Map:
+			next, found, err := typed.Lookup(ctx, runtime.NewString(segment))
+			if err != nil || !found {
+				return runtime.None, false, err
+			}
+
+			current = next
+		case runtime.List:
+			idx, err := strconv.Atoi(segment)
+			if err != nil {
+				return runtime.None, false, fmt.Errorf("path %q: %q is not an array index", path, segment)
+			}
+
+			length, err := typed.Length(ctx)
+			if err != nil {
+				return runtime.None, false, err
+			}
+
+			if idx < 0 || idx >= int(length) {
+				return runtime.None, false, nil
+			}
+
+			current, err = typed.At(ctx, runtime.NewInt(idx))
+			if err != nil {
+				return runtime.None, false, err
+			}
+		default:
+			return runtime.None, false, nil
+		}
+	}
+
+	return current, true, nil
+}
@@ -0,0 +1,259 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	commonstream "github.com/MontFerret/contrib/pkg/common/stream"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+type (
+	// Records is a response body of NDJSON records or Server-Sent Events.
+	// The body is received whole, and records are decoded one at a time as an iteration consumes them.
+	// Every iteration starts again from the first record.
+	Records struct {
+		body     []byte
+		encoding Encoding
+	}
+
+	// RecordIterator decodes the next record of a body on demand.
+	RecordIterator struct {
+		reader      *bufio.Reader
+		encoding    Encoding
+		lastEventID string
+		position    int
+		started     bool
+		skipLF      bool
+		done        bool
+	}
+)
+
+// maxRecordSize bounds a line and the data of an event.
+const maxRecordSize = 16 << 20
+
+var utf8BOM = []byte("\xEF\xBB\xBF")
+
+// isRecordEncoding reports whether a response encoding decodes to records instead of a single value.
+func isRecordEncoding(encoding Encoding) bool {
+	return encoding == EncodingNDJSON || encoding == EncodingSSE
+}
+
+// recordsAccept returns the Accept header of a record encoding.
+func recordsAccept(encoding Encoding) string {
+	if encoding == EncodingSSE {
+		return "text/event-stream"
+	}
+
+	return "application/x-ndjson"
+}
+
+func newRecords(body []byte, encoding Encoding) *Records {
+	return &Records{
+		body:     body,
+		encoding: encoding,
+	}
+}
+
+// Iterate returns an iterator positioned before the first record.
+func (r *Records) Iterate(_ context.Context) (runtime.Iterator, error) {
+	return &RecordIterator{
+		reader:   bufio.NewReader(bytes.NewReader(r.body)),
+		encoding: r.encoding,
+	}, nil
+}
+
+// Next returns the next record and its 1-based position.
+func (i *RecordIterator) Next(ctx context.Context) (runtime.Value, runtime.Value, error) {
+	if i.done {
+		return runtime.None, runtime.None, io.EOF
+	}
+
+	if err := ctx.Err(); err != nil {
+		i.done = true
+
+		return runtime.None, runtime.None, err
+	}
+
+	var record runtime.Value
+	var err error
+
+	if i.encoding == EncodingSSE {
+		record, err = i.nextEvent()
+	} else {
+		record, err = i.nextJSONRecord()
+	}
+
+	if err != nil {
+		i.done = true
+
+		return runtime.None, runtime.None, err
+	}
+
+	i.position++
+
+	return record, runtime.NewInt(i.position), nil
+}
+
+// nextJSONRecord decodes the next non-blank line as a JSON value.
+func (i *RecordIterator) nextJSONRecord() (runtime.Value, error) {
+	for {
+		line, err := i.nextLine()
+		if err != nil {
+			return runtime.None, i.recordError(err)
+		}
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		record, err := decodeJSONBody(line)
+		if err != nil {
+			return runtime.None, fmt.Errorf("decode NDJSON record %d: %w", i.position+1, err)
+		}
+
+		return record, nil
+	}
+}
+
+// nextEvent parses lines until a blank line dispatches an event with data.
+// Comments and unknown fields are ignored, and an event that is not terminated by a blank line is discarded.
+func (i *RecordIterator) nextEvent() (runtime.Value, error) {
+	var data strings.Builder
+	hasData := false
+	eventType := ""
+	retry := int64(-1)
+
+	for {
+		line, err := i.nextLine()
+		if err != nil {
+			return runtime.None, i.recordError(err)
+		}
+
+		if len(line) == 0 {
+			if !hasData {
+				eventType = ""
+
+				continue
+			}
+
+			if eventType == "" {
+				eventType = "message"
+			}
+
+			event := map[string]runtime.Value{
+				"event": runtime.NewString(eventType),
+				"data":  runtime.NewString(strings.TrimSuffix(data.String(), "\n")),
+				"id":    runtime.NewString(i.lastEventID),
+			}
+
+			if retry >= 0 {
+				event["retry"] = runtime.NewInt(int(retry))
+			}
+
+			return runtime.NewObjectWith(event), nil
+		}
+
+		if line[0] == ':' {
+			continue
+		}
+
+		field, value, _ := bytes.Cut(line, []byte(":"))
+		value = bytes.TrimPrefix(value, []byte(" "))
+
+		switch string(field) {
+		case "event":
+			eventType = string(value)
+		case "data":
+			if data.Len()+len(value) >= maxRecordSize {
+				return runtime.None, i.recordError(commonstream.ErrLimitExceeded)
+			}
+
+			data.Write(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "id":
+			if bytes.IndexByte(value, 0) < 0 {
+				i.lastEventID = string(value)
+			}
+		case "retry":
+			// only plain digits are valid, which ParseUint enforces by rejecting signs
+			if parsed, err := strconv.ParseUint(string(value), 10, 63); err == nil {
+				retry = int64(parsed)
+			}
+		}
+	}
+}
+
+// recordError reports the failure to read the next record. The end of the body ends the iteration as is.
+func (i *RecordIterator) recordError(err error) error {
+	if err == io.EOF {
+		return err
+	}
+
+	kind := "NDJSON record"
+	if i.encoding == EncodingSSE {
+		kind = "event"
+	}
+
+	return fmt.Errorf("read %s %d: %w", kind, i.position+1, err)
+}
+
+// nextLine returns the next line without its terminator, and without the byte order mark of the first line.
+func (i *RecordIterator) nextLine() ([]byte, error) {
+	line, err := i.readLine()
+	if err != nil || i.started {
+		return line, err
+	}
+
+	i.started = true
+
+	return bytes.TrimPrefix(line, utf8BOM), nil
+}
+
+// readLine reads a line. Lines end with LF, CRLF or CR.
+// An unterminated last line is returned only for NDJSON, SSE discards it with its pending event anyway.
+func (i *RecordIterator) readLine() ([]byte, error) {
+	var line []byte
+
+	for {
+		c, err := i.reader.ReadByte()
+		if err != nil {
+			if err == io.EOF && i.encoding == EncodingNDJSON && len(line) > 0 {
+				// NDJSON bodies commonly omit the final newline
+				return line, nil
+			}
+
+			return nil, err
+		}
+
+		if i.skipLF {
+			i.skipLF = false
+
+			if c == '\n' {
+				continue
+			}
+		}
+
+		switch c {
+		case '\n':
+			return line, nil
+		case '\r':
+			i.skipLF = true
+
+			return line, nil
+		}
+
+		if len(line) >= maxRecordSize {
+			return nil, commonstream.ErrLimitExceeded
+		}
+
+		line = append(line, c)
+	}
+}
@@ -0,0 +1,191 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+func TestClientNDJSONResponse(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept"); got != "application/x-ndjson" {
+			t.Fatalf("expected NDJSON Accept header, got %q", got)
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = w.Write([]byte("{\"id\":1}\n{\"id\":2}\r\n\n{\"id\":3}"))
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+
+	out, err := NewClient(cfg).QueryOne(ctx, runtime.Query{
+		Expression: runtime.NewString("/export"),
+		Options: object(t, map[string]runtime.Value{
+			"responseEncoding": runtime.NewString("jsonl"),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+
+	records, ok := out.(runtime.Iterable)
+	if !ok {
+		t.Fatalf("expected an iterable body, got %T", out)
+	}
+
+	ids := make([]string, 0, 3)
+	for _, record := range collectRecords(t, ctx, records) {
+		ids = append(ids, field(t, record, "id").String())
+	}
+
+	if strings.Join(ids, ",") != "1,2,3" {
+		t.Fatalf("expected records 1,2,3, got %v", ids)
+	}
+}
+
+func TestClientQueryFlattensRecords(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = w.Write([]byte("{\"id\":1}\n{\"id\":2}\n"))
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+
+	out, err := NewClient(cfg).Query(ctx, runtime.Query{
+		Expression: runtime.NewString("/export"),
+		Options: object(t, map[string]runtime.Value{
+			"responseEncoding": runtime.NewString("ndjson"),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+
+	ids := make([]string, 0, 2)
+	iterateList(t, ctx, out, func(item runtime.Value) {
+		ids = append(ids, field(t, item, "id").String())
+	})
+
+	if strings.Join(ids, ",") != "1,2" {
+		t.Fatalf("expected the records as query items, got %v", ids)
+	}
+}
+
+func TestRecordsSSE(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	body := ": keep-alive\r\n" +
+		"event: update\ndata: {\"price\": 1}\ndata: second line\nid: 7\nretry: 3000\n\n" +
+		"data\n\n" +
+		"event: ignored\n\n" +
+		"data: unterminated"
+
+	events := collectRecords(t, ctx, newRecords([]byte(body), EncodingSSE))
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+
+	if got := field(t, events[0], "event"); got != runtime.NewString("update") {
+		t.Fatalf("unexpected event type %s", got.String())
+	}
+	if got := field(t, events[0], "data"); got != runtime.NewString("{\"price\": 1}\nsecond line") {
+		t.Fatalf("unexpected event data %q", got.String())
+	}
+	if got := field(t, events[0], "retry"); got != runtime.NewInt(3000) {
+		t.Fatalf("unexpected retry %s", got.String())
+	}
+
+	if got := field(t, events[1], "event"); got != runtime.NewString("message") {
+		t.Fatalf("expected the default event type, got %s", got.String())
+	}
+	if got := field(t, events[1], "data"); got != runtime.NewString("") {
+		t.Fatalf("expected empty data, got %q", got.String())
+	}
+	if got := field(t, events[1], "id"); got != runtime.NewString("7") {
+		t.Fatalf("expected the last event id to carry over, got %s", got.String())
+	}
+}
+
+func TestRecordsNDJSONErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	iter, err := newRecords([]byte("{\"id\":1}\n{broken\n{\"id\":3}\n"), EncodingNDJSON).Iterate(ctx)
+	if err != nil {
+		t.Fatalf("unexpected iterate error: %v", err)
+	}
+
+	if _, _, err := iter.Next(ctx); err != nil {
+		t.Fatalf("unexpected first record error: %v", err)
+	}
+
+	if _, _, err := iter.Next(ctx); err == nil || !strings.Contains(err.Error(), "record 2") {
+		t.Fatalf("expected a record 2 decode error, got %v", err)
+	}
+
+	if _, _, err := iter.Next(ctx); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected the iteration to end after an error, got %v", err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.BaseURL = "https://api.example.test"
+
+	_, err = NewClient(cfg).Query(ctx, runtime.Query{
+		Expression: runtime.NewString("/export"),
+		Options: object(t, map[string]runtime.Value{
+			"responseEncoding": runtime.NewString("ndjson"),
+			"paginate":         runtime.True,
+		}),
+	})
+	if err == nil || !strings.Contains(err.Error(), "pagination does not support") {
+		t.Fatalf("expected paginated NDJSON to fail, got %v", err)
+	}
+
+	if _, err := DecodeClientConfig(ctx, object(t, map[string]runtime.Value{
+		"baseUrl":         runtime.NewString("https://api.example.test"),
+		"requestEncoding": runtime.NewString("sse"),
+	})); err == nil {
+		t.Fatal("expected sse to be rejected as a request encoding")
+	}
+}
+
+func collectRecords(t *testing.T, ctx context.Context, records runtime.Iterable) []runtime.Value {
+	t.Helper()
+
+	iter, err := records.Iterate(ctx)
+	if err != nil {
+		t.Fatalf("unexpected iterate error: %v", err)
+	}
+
+	out := make([]runtime.Value, 0)
+
+	for {
+		record, _, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			return out
+		}
+		if err != nil {
+			t.Fatalf("unexpected next error: %v", err)
+		}
+
+		out = append(out, record)
+	}
+}
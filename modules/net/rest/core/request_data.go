@@ -9,6 +9,7 @@ import (
 )
 
 type RequestData struct {
+	Path    runtime.Value
 	Query   runtime.Value
 	Body    runtime.Value
 	Headers http.Header
@@ -19,6 +20,7 @@ type RequestData struct {
 func DecodeRequestData(ctx context.Context, value runtime.Value) (RequestData, error) {
 	data := RequestData{
 		Method:  http.MethodGet,
+		Path:    runtime.None,
 		Query:   runtime.None,
 		Headers: make(http.Header),
 		Body:    runtime.None,
@@ -41,6 +43,12 @@ func DecodeRequestData(ctx context.Context, value runtime.Value) (RequestData, e
 		methodProvided = true
 	}
 
+	if path, found, err := lookupValue(ctx, obj, "path"); err != nil {
+		return data, err
+	} else if found {
+		data.Path = path
+	}
+
 	if query, found, err := lookupValue(ctx, obj, "query"); err != nil {
 		return data, err
 	} else if found {
@@ -10,20 +10,23 @@ import (
 	"github.com/MontFerret/ferret/v2/pkg/runtime"
 )
 
-func decodeHTTPResponse(ctx context.Context, requestURL string, resp *ferrethttp.Response, opts ExecutionOptions) (runtime.Value, bool, error) {
+// decodeHTTPResponse returns the result of a response and whether its items are the query items.
+func (ex *exchange) decodeHTTPResponse(ctx context.Context, requestURL string, resp *ferrethttp.Response, attempts int) (runtime.Value, bool, error) {
+	opts := ex.options
+
 	ok := resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusBadRequest
 	if !ok && opts.ErrorMode == ErrorModeRaise {
 		return runtime.None, false, fmt.Errorf("unexpected status %s", resp.Status)
 	}
 
-	decoded, err := decodeResponseBody(resp.Body, opts.ResponseEncoding)
+	decoded, err := ex.decodeBody(ctx, resp)
 	if err != nil {
-		return runtime.None, false, fmt.Errorf("decode response body: %w", err)
+		return runtime.None, false, err
 	}
 
 	fullResponse := opts.ResponseMode == ResponseModeFull || (!ok && opts.ErrorMode == ErrorModeResponse)
 	if fullResponse {
-		value, err := buildFullResponse(ctx, requestURL, resp, decoded)
+		value, err := buildFullResponse(ctx, requestURL, resp, attempts, decoded)
 		if err != nil {
 			return runtime.None, false, err
 		}
@@ -34,12 +37,31 @@ func decodeHTTPResponse(ctx context.Context, requestURL string, resp *ferrethttp
 	return decoded, true, nil
 }
 
-func buildFullResponse(ctx context.Context, requestURL string, resp *ferrethttp.Response, body runtime.Value) (runtime.Value, error) {
+// decodeBody decodes a response body and validates it against the OpenAPI operation of the query.
+func (ex *exchange) decodeBody(ctx context.Context, resp *ferrethttp.Response) (runtime.Value, error) {
+	encoding := responseEncodingFor(ex.options.ResponseEncoding, http.Header(resp.Headers))
+
+	body, err := decodeResponseBody(ctx, resp.Body, encoding)
+	if err != nil {
+		return runtime.None, fmt.Errorf("decode response body: %w", err)
+	}
+
+	if ex.openapi != nil && ex.openapi.validateResponses {
+		if err := ex.openapi.validateResponse(ctx, resp, encoding, body); err != nil {
+			return runtime.None, err
+		}
+	}
+
+	return body, nil
+}
+
+func buildFullResponse(ctx context.Context, requestURL string, resp *ferrethttp.Response, attempts int, body runtime.Value) (runtime.Value, error) {
 	out := runtime.NewObjectWith(map[string]runtime.Value{
-		"ok":     runtime.NewBoolean(resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusBadRequest),
-		"status": runtime.NewInt(resp.StatusCode),
-		"body":   body,
-		"url":    runtime.NewString(requestURL),
+		"ok":       runtime.NewBoolean(resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusBadRequest),
+		"status":   runtime.NewInt(resp.StatusCode),
+		"body":     body,
+		"url":      runtime.NewString(requestURL),
+		"attempts": runtime.NewInt(attempts),
 	})
 
 	headers, err := responseHeaders(ctx, http.Header(resp.Headers))
@@ -0,0 +1,302 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	ferrethttp "github.com/MontFerret/ferret/v2/pkg/net/http"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+// RetryPolicy controls how failed requests are repeated.
+// A policy with MaxAttempts of 1 sends every request once.
+type RetryPolicy struct {
+	Backoff     Backoff
+	Methods     []string
+	Statuses    []int
+	Delay       time.Duration
+	MaxDelay    time.Duration
+	MaxAttempts int
+	Jitter      bool
+	RetryAfter  bool
+}
+
+const defaultRetryAttempts = 3
+
+type httpDoer interface {
+	Do(ctx context.Context, req *ferrethttp.Request) (*ferrethttp.Response, error)
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 1,
+		Backoff:     BackoffExponential,
+		Jitter:      true,
+		Delay:       200 * time.Millisecond,
+		MaxDelay:    30 * time.Second,
+		Statuses: []int{
+			http.StatusRequestTimeout,
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		Methods: []string{
+			http.MethodGet,
+			http.MethodHead,
+			http.MethodOptions,
+			http.MethodPut,
+			http.MethodDelete,
+		},
+		RetryAfter: true,
+	}
+}
+
+// DecodeRetryPolicy applies a retry block on top of a base policy.
+// A boolean enables or disables retries, an object overrides individual fields.
+func DecodeRetryPolicy(ctx context.Context, base RetryPolicy, value runtime.Value, owner string) (RetryPolicy, error) {
+	policy := base
+	policy.Methods = slices.Clone(base.Methods)
+	policy.Statuses = slices.Clone(base.Statuses)
+
+	if enabled, ok := value.(runtime.Boolean); ok {
+		switch {
+		case !enabled:
+			policy.MaxAttempts = 1
+		case policy.MaxAttempts <= 1:
+			policy.MaxAttempts = defaultRetryAttempts
+		}
+
+		return policy, nil
+	}
+
+	obj, err := requireMap(ctx, value, owner)
+	if err != nil {
+		return policy, fmt.Errorf("%s or a boolean", err.Error())
+	}
+
+	if attempts, found, err := lookupInt(ctx, obj, "attempts", owner); err != nil {
+		return policy, err
+	} else if found {
+		if attempts < 1 {
+			return policy, fmt.Errorf("%s.attempts must be greater than or equal to 1", owner)
+		}
+
+		policy.MaxAttempts = int(attempts)
+	} else if policy.MaxAttempts <= 1 {
+		policy.MaxAttempts = defaultRetryAttempts
+	}
+
+	if backoff, found, err := lookupString(ctx, obj, "backoff", owner); err != nil {
+		return policy, err
+	} else if found {
+		policy.Backoff, err = parseBackoff(backoff)
+		if err != nil {
+			return policy, fmt.Errorf("%s.backoff: %w", owner, err)
+		}
+	}
+
+	if jitter, found, err := lookupBool(ctx, obj, "jitter", owner); err != nil {
+		return policy, err
+	} else if found {
+		policy.Jitter = jitter
+	}
+
+	if delay, found, err := lookupDuration(ctx, obj, "delay", owner); err != nil {
+		return policy, err
+	} else if found {
+		policy.Delay = delay
+	}
+
+	if maxDelay, found, err := lookupDuration(ctx, obj, "maxDelay", owner); err != nil {
+		return policy, err
+	} else if found {
+		policy.MaxDelay = maxDelay
+	}
+
+	if policy.MaxDelay < policy.Delay {
+		return policy, fmt.Errorf("%s.maxDelay must be greater than or equal to delay", owner)
+	}
+
+	if statuses, found, err := lookupList(ctx, obj, "statuses", owner); err != nil {
+		return policy, err
+	} else if found {
+		policy.Statuses = make([]int, 0, len(statuses))
+
+		for _, item := range statuses {
+			status, ok := item.(runtime.Int)
+			if !ok || status < 100 || status > 599 {
+				return policy, fmt.Errorf("%s.statuses must contain HTTP status codes", owner)
+			}
+
+			policy.Statuses = append(policy.Statuses, int(status))
+		}
+	}
+
+	if methods, found, err := lookupList(ctx, obj, "methods", owner); err != nil {
+		return policy, err
+	} else if found {
+		policy.Methods = make([]string, 0, len(methods))
+
+		for _, item := range methods {
+			method, ok := item.(runtime.String)
+			if !ok || strings.TrimSpace(method.String()) == "" {
+				return policy, fmt.Errorf("%s.methods must contain HTTP method names", owner)
+			}
+
+			policy.Methods = append(policy.Methods, strings.ToUpper(strings.TrimSpace(method.String())))
+		}
+	}
+
+	if retryAfter, found, err := lookupBool(ctx, obj, "retryAfter", owner); err != nil {
+		return policy, err
+	} else if found {
+		policy.RetryAfter = retryAfter
+	}
+
+	return policy, nil
+}
+
+// doWithRetry sends a request until it succeeds, fails permanently or runs out of attempts.
+// It returns the last response together with the number of attempts made.
+func doWithRetry(ctx context.Context, client httpDoer, req *ferrethttp.Request, opts ExecutionOptions) (*ferrethttp.Response, int, error) {
+	policy := opts.Retry
+	retryable := policy.MaxAttempts > 1 && slices.Contains(policy.Methods, req.Method)
+
+	for attempt := 1; ; attempt++ {
+		resp, err := doAttempt(ctx, client, req, opts.Timeout)
+		if !retryable || attempt >= policy.MaxAttempts {
+			return resp, attempt, err
+		}
+
+		var delay time.Duration
+
+		switch {
+		case err != nil:
+			if ctx.Err() != nil || !isTransientError(err) {
+				return nil, attempt, err
+			}
+
+			delay = policy.backoff(attempt)
+		case slices.Contains(policy.Statuses, resp.StatusCode):
+			delay = policy.backoff(attempt)
+
+			if wait, found := policy.retryAfter(http.Header(resp.Headers), time.Now()); found {
+				// waiting longer than allowed would only delay the same failure
+				if wait > policy.MaxDelay {
+					return resp, attempt, nil
+				}
+
+				delay = wait
+			}
+		default:
+			return resp, attempt, nil
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, attempt, err
+		}
+	}
+}
+
+func doAttempt(ctx context.Context, client httpDoer, req *ferrethttp.Request, timeout time.Duration) (*ferrethttp.Response, error) {
+	if timeout <= 0 {
+		return client.Do(ctx, req)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return client.Do(attemptCtx, req)
+}
+
+// backoff returns the delay before the attempt that follows the given one.
+// Full jitter picks a random delay up to the computed one, which spreads out clients retrying together.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.Delay
+
+	if p.Backoff == BackoffExponential {
+		for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+			delay *= 2
+		}
+	}
+
+	delay = min(delay, p.MaxDelay)
+
+	if p.Jitter && delay > 0 {
+		delay = rand.N(delay + 1)
+	}
+
+	return delay
+}
+
+// retryAfter reads the Retry-After header as a number of seconds or an HTTP date.
+func (p RetryPolicy) retryAfter(headers http.Header, now time.Time) (time.Duration, bool) {
+	if !p.RetryAfter {
+		return 0, false
+	}
+
+	value := strings.TrimSpace(headers.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(date.Sub(now), 0), true
+}
+
+// isTransientError reports whether a request failed for a reason that may not repeat,
+// such as a dropped connection or an attempt timeout.
+func isTransientError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,314 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	ferrethttp "github.com/MontFerret/ferret/v2/pkg/net/http"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+func TestClientRetriesRetryableStatuses(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":1}`))
+		}
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+	cfg.Retry = testRetryPolicy(3)
+
+	out, err := NewClient(cfg).QueryOne(ctx, runtime.Query{
+		Expression: runtime.NewString("/users/1"),
+		Options: object(t, map[string]runtime.Value{
+			"response": runtime.NewString("full"),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+	if got := field(t, out, "status"); got != runtime.NewInt(http.StatusOK) {
+		t.Fatalf("expected status 200, got %s", got.String())
+	}
+	if got := field(t, out, "attempts"); got != runtime.NewInt(3) {
+		t.Fatalf("expected 3 attempts, got %s", got.String())
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 requests, got %d", calls.Load())
+	}
+}
+
+func TestClientStopsRetryingAfterMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+	cfg.Retry = testRetryPolicy(2)
+
+	_, err := NewClient(cfg).QueryOne(ctx, runtime.Query{Expression: runtime.NewString("/users")})
+	if err == nil {
+		t.Fatal("expected exhausted retries to raise the last status")
+	}
+	if !strings.Contains(err.Error(), "unexpected status 502 Bad Gateway") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 requests, got %d", calls.Load())
+	}
+}
+
+func TestClientRetryRespectsMethodsAndQueryOptions(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+	cfg.ErrorMode = ErrorModeResponse
+	cfg.Retry = testRetryPolicy(3)
+	client := NewClient(cfg)
+
+	// POST is not idempotent and is not retried by default
+	out, err := client.QueryOne(ctx, runtime.Query{
+		Expression: runtime.NewString("/users"),
+		Params: object(t, map[string]runtime.Value{
+			"body": object(t, map[string]runtime.Value{"name": runtime.NewString("Ada")}),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+	if got := field(t, out, "attempts"); got != runtime.NewInt(1) {
+		t.Fatalf("expected a single POST attempt, got %s", got.String())
+	}
+
+	out, err = client.QueryOne(ctx, runtime.Query{
+		Expression: runtime.NewString("/users"),
+		Params: object(t, map[string]runtime.Value{
+			"body": object(t, map[string]runtime.Value{"name": runtime.NewString("Ada")}),
+		}),
+		Options: object(t, map[string]runtime.Value{
+			"retry": object(t, map[string]runtime.Value{
+				"attempts": runtime.NewInt(2),
+				"methods":  runtime.NewArrayWith(runtime.NewString("post")),
+			}),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+	if got := field(t, out, "attempts"); got != runtime.NewInt(2) {
+		t.Fatalf("expected 2 POST attempts, got %s", got.String())
+	}
+
+	out, err = client.QueryOne(ctx, runtime.Query{
+		Expression: runtime.NewString("/users"),
+		Options: object(t, map[string]runtime.Value{
+			"retry": runtime.False,
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+	if got := field(t, out, "attempts"); got != runtime.NewInt(1) {
+		t.Fatalf("expected retries to be disabled, got %s", got.String())
+	}
+	if calls.Load() != 4 {
+		t.Fatalf("expected 4 requests, got %d", calls.Load())
+	}
+}
+
+func TestDoWithRetryRetriesTransientErrors(t *testing.T) {
+	t.Parallel()
+
+	client := &sequenceHTTPClient{
+		errs: []error{syscall.ECONNRESET, nil},
+		response: &ferrethttp.Response{
+			StatusCode: http.StatusOK,
+			Status:     "200 OK",
+		},
+	}
+	opts := DefaultExecutionOptions(DefaultConfig())
+	opts.Retry = testRetryPolicy(3)
+
+	resp, attempts, err := doWithRetry(context.Background(), client, &ferrethttp.Request{Method: http.MethodGet}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || attempts != 2 {
+		t.Fatalf("expected success on the second attempt, got status %d after %d attempts", resp.StatusCode, attempts)
+	}
+
+	client = &sequenceHTTPClient{errs: []error{errors.New("request blocked by policy")}}
+
+	_, attempts, err = doWithRetry(context.Background(), client, &ferrethttp.Request{Method: http.MethodGet}, opts)
+	if err == nil || attempts != 1 {
+		t.Fatalf("expected a permanent error without retries, got %v after %d attempts", err, attempts)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	t.Parallel()
+
+	policy := testRetryPolicy(5)
+	policy.Delay = 100 * time.Millisecond
+	policy.MaxDelay = 300 * time.Millisecond
+
+	for attempt, expected := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 300 * time.Millisecond,
+		4: 300 * time.Millisecond,
+	} {
+		if got := policy.backoff(attempt); got != expected {
+			t.Fatalf("expected backoff %s after attempt %d, got %s", expected, attempt, got)
+		}
+	}
+
+	policy.Backoff = BackoffConstant
+	if got := policy.backoff(3); got != 100*time.Millisecond {
+		t.Fatalf("expected constant backoff, got %s", got)
+	}
+
+	policy.Backoff = BackoffExponential
+	policy.Jitter = true
+	for attempt := 1; attempt < 5; attempt++ {
+		if got := policy.backoff(attempt); got < 0 || got > policy.MaxDelay {
+			t.Fatalf("expected jittered backoff within [0, %s], got %s", policy.MaxDelay, got)
+		}
+	}
+}
+
+func TestRetryPolicyRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	policy := DefaultRetryPolicy()
+
+	for _, tc := range []struct {
+		value    string
+		expected time.Duration
+		found    bool
+	}{
+		{value: "5", expected: 5 * time.Second, found: true},
+		{value: now.Add(90 * time.Second).Format(http.TimeFormat), expected: 90 * time.Second, found: true},
+		{value: now.Add(-time.Minute).Format(http.TimeFormat), expected: 0, found: true},
+		{value: "soon"},
+		{value: ""},
+	} {
+		headers := make(http.Header)
+		headers.Set("Retry-After", tc.value)
+
+		got, found := policy.retryAfter(headers, now)
+		if got != tc.expected || found != tc.found {
+			t.Fatalf("Retry-After %q: expected %s/%t, got %s/%t", tc.value, tc.expected, tc.found, got, found)
+		}
+	}
+
+	policy.RetryAfter = false
+	if _, found := policy.retryAfter(http.Header{"Retry-After": []string{"5"}}, now); found {
+		t.Fatal("expected Retry-After to be ignored")
+	}
+}
+
+func TestDecodeRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	policy, err := DecodeRetryPolicy(ctx, DefaultRetryPolicy(), object(t, map[string]runtime.Value{
+		"backoff":    runtime.NewString("constant"),
+		"jitter":     runtime.False,
+		"delay":      runtime.NewInt(50),
+		"maxDelay":   runtime.NewInt(1000),
+		"statuses":   runtime.NewArrayWith(runtime.NewInt(503)),
+		"retryAfter": runtime.False,
+	}), "retry")
+	if err != nil {
+		t.Fatalf("unexpected retry error: %v", err)
+	}
+	if policy.MaxAttempts != defaultRetryAttempts {
+		t.Fatalf("expected a retry block to enable %d attempts, got %d", defaultRetryAttempts, policy.MaxAttempts)
+	}
+	if policy.Backoff != BackoffConstant || policy.Jitter || policy.RetryAfter {
+		t.Fatalf("unexpected retry policy: %+v", policy)
+	}
+	if policy.Delay != 50*time.Millisecond || policy.MaxDelay != time.Second {
+		t.Fatalf("unexpected retry delays: %s/%s", policy.Delay, policy.MaxDelay)
+	}
+	if len(policy.Statuses) != 1 || policy.Statuses[0] != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected retry statuses: %v", policy.Statuses)
+	}
+
+	for _, invalid := range []runtime.Value{
+		runtime.NewString("always"),
+		object(t, map[string]runtime.Value{"attempts": runtime.NewInt(0)}),
+		object(t, map[string]runtime.Value{"backoff": runtime.NewString("linear")}),
+		object(t, map[string]runtime.Value{"statuses": runtime.NewArrayWith(runtime.NewInt(42))}),
+		object(t, map[string]runtime.Value{"delay": runtime.NewInt(500), "maxDelay": runtime.NewInt(100)}),
+	} {
+		if _, err := DecodeRetryPolicy(ctx, DefaultRetryPolicy(), invalid, "retry"); err == nil {
+			t.Fatalf("expected retry block %s to fail", invalid.String())
+		}
+	}
+}
+
+func testRetryPolicy(attempts int) RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = attempts
+	policy.Delay = time.Millisecond
+	policy.MaxDelay = 10 * time.Millisecond
+	policy.Jitter = false
+
+	return policy
+}
+
+type sequenceHTTPClient struct {
+	response *ferrethttp.Response
+	errs     []error
+	calls    int
+}
+
+func (c *sequenceHTTPClient) Do(_ context.Context, _ *ferrethttp.Request) (*ferrethttp.Response, error) {
+	defer func() { c.calls++ }()
+
+	if c.calls < len(c.errs) && c.errs[c.calls] != nil {
+		return nil, c.errs[c.calls]
+	}
+
+	return c.response, nil
+}
@@ -0,0 +1,348 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+// templateOperator describes how an RFC 6570 expression operator expands its variables.
+type templateOperator struct {
+	first         string
+	sep           string
+	ifEmpty       string
+	named         bool
+	allowReserved bool
+}
+
+// templateVarspec is a variable reference with its optional prefix or explode modifier.
+// The key is the name with its percent-encoded characters decoded, which is the WITH.path key it reads.
+type templateVarspec struct {
+	name    string
+	key     string
+	prefix  int
+	explode bool
+}
+
+var templateOperators = map[byte]templateOperator{
+	'+': {sep: ",", allowReserved: true},
+	'#': {first: "#", sep: ",", allowReserved: true},
+	'.': {first: ".", sep: "."},
+	'/': {first: "/", sep: "/"},
+	';': {first: ";", sep: ";", named: true},
+	'?': {first: "?", sep: "&", ifEmpty: "=", named: true},
+	'&': {first: "&", sep: "&", ifEmpty: "=", named: true},
+}
+
+const maxTemplatePrefix = 9999
+
+// expandURITemplate expands an RFC 6570 URI template with the values of WITH.path.
+// Expressions without template braces are returned unchanged.
+func expandURITemplate(ctx context.Context, template string, vars runtime.Value) (string, error) {
+	if !strings.Contains(template, "{") {
+		return template, nil
+	}
+
+	values := map[string]any{}
+
+	if !runtime.TypeNone.Is(vars) {
+		obj, err := requireMap(ctx, vars, "HTTP query WITH.path")
+		if err != nil {
+			return "", err
+		}
+
+		values, err = mapToNative(ctx, obj)
+		if err != nil {
+			return "", fmt.Errorf("HTTP query WITH.path: %w", err)
+		}
+	}
+
+	expanded, err := expandTemplate(template, values)
+	if err != nil {
+		return "", fmt.Errorf("invalid URI template %q: %w", template, err)
+	}
+
+	return expanded, nil
+}
+
+// expandTemplate implements level 4 template expansion. Object keys are expanded in sorted order.
+func expandTemplate(template string, vars map[string]any) (string, error) {
+	var out strings.Builder
+
+	for len(template) > 0 {
+		start := strings.IndexAny(template, "{}")
+		if start < 0 {
+			out.WriteString(encodeTemplateValue(template, true))
+			break
+		}
+
+		if template[start] == '}' {
+			return "", fmt.Errorf("unexpected '}' at %q", template[start:])
+		}
+
+		out.WriteString(encodeTemplateValue(template[:start], true))
+		template = template[start+1:]
+
+		end := strings.IndexByte(template, '}')
+		if end < 0 {
+			return "", fmt.Errorf("unclosed expression")
+		}
+
+		expanded, err := expandExpression(template[:end], vars)
+		if err != nil {
+			return "", err
+		}
+
+		out.WriteString(expanded)
+		template = template[end+1:]
+	}
+
+	return out.String(), nil
+}
+
+func expandExpression(expression string, vars map[string]any) (string, error) {
+	if expression == "" {
+		return "", fmt.Errorf("empty expression")
+	}
+
+	op := templateOperator{sep: ","}
+
+	if found, ok := templateOperators[expression[0]]; ok {
+		op = found
+		expression = expression[1:]
+	} else if strings.ContainsRune("=,!@|", rune(expression[0])) {
+		return "", fmt.Errorf("unsupported operator %q", expression[0])
+	}
+
+	parts := make([]string, 0)
+
+	for _, raw := range strings.Split(expression, ",") {
+		spec, err := parseVarspec(raw)
+		if err != nil {
+			return "", err
+		}
+
+		expanded, defined, err := expandVariable(op, spec, vars[spec.key])
+		if err != nil {
+			return "", fmt.Errorf("variable %q: %w", spec.key, err)
+		}
+
+		if defined {
+			parts = append(parts, expanded)
+		}
+	}
+
+	if len(parts) == 0 {
+		return "", nil
+	}
+
+	return op.first + strings.Join(parts, op.sep), nil
+}
+
+func parseVarspec(raw string) (templateVarspec, error) {
+	spec := templateVarspec{name: raw}
+
+	if name, ok := strings.CutSuffix(raw, "*"); ok {
+		spec.name = name
+		spec.explode = true
+	} else if name, prefix, ok := strings.Cut(raw, ":"); ok {
+		length, err := strconv.Atoi(prefix)
+		if err != nil || length < 1 || length > maxTemplatePrefix || prefix[0] == '0' {
+			return spec, fmt.Errorf("invalid prefix modifier in %q", raw)
+		}
+
+		spec.name = name
+		spec.prefix = length
+	}
+
+	if !validVarname(spec.name) {
+		return spec, fmt.Errorf("invalid variable name %q", spec.name)
+	}
+
+	key, err := url.PathUnescape(spec.name)
+	if err != nil {
+		return spec, fmt.Errorf("invalid variable name %q", spec.name)
+	}
+
+	spec.key = key
+
+	return spec, nil
+}
+
+func validVarname(name string) bool {
+	if name == "" || name[0] == '.' || name[len(name)-1] == '.' || strings.Contains(name, "..") {
+		return false
+	}
+
+	for i := 0; i < len(name); i++ {
+		switch c := name[i]; {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == '.':
+		case c == '%' && i+2 < len(name) && isHex(name[i+1]) && isHex(name[i+2]):
+			i += 2
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// expandVariable expands a single variable. Undefined values, empty arrays and empty objects are skipped.
+func expandVariable(op templateOperator, spec templateVarspec, value any) (string, bool, error) {
+	switch typed := value.(type) {
+	case nil:
+		return "", false, nil
+	case []any:
+		if len(typed) == 0 {
+			return "", false, nil
+		}
+
+		if spec.prefix > 0 {
+			return "", false, fmt.Errorf("prefix modifiers do not apply to arrays")
+		}
+
+		items := make([]string, 0, len(typed))
+
+		for _, item := range typed {
+			str, err := templateScalar(item)
+			if err != nil {
+				return "", false, err
+			}
+
+			switch {
+			case spec.explode && op.named:
+				items = append(items, namedValue(op, spec.name, str))
+			default:
+				items = append(items, encodeTemplateValue(str, op.allowReserved))
+			}
+		}
+
+		if spec.explode {
+			return strings.Join(items, op.sep), true, nil
+		}
+
+		return namedPrefix(op, spec.name) + strings.Join(items, ","), true, nil
+	case map[string]any:
+		if len(typed) == 0 {
+			return "", false, nil
+		}
+
+		if spec.prefix > 0 {
+			return "", false, fmt.Errorf("prefix modifiers do not apply to objects")
+		}
+
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		items := make([]string, 0, len(keys)*2)
+
+		for _, key := range keys {
+			str, err := templateScalar(typed[key])
+			if err != nil {
+				return "", false, fmt.Errorf("at key %q: %w", key, err)
+			}
+
+			if spec.explode {
+				items = append(items, namedValue(op, encodeTemplateValue(key, op.allowReserved), str))
+			} else {
+				items = append(items, encodeTemplateValue(key, op.allowReserved), encodeTemplateValue(str, op.allowReserved))
+			}
+		}
+
+		if spec.explode {
+			return strings.Join(items, op.sep), true, nil
+		}
+
+		return namedPrefix(op, spec.name) + strings.Join(items, ","), true, nil
+	}
+
+	str, err := templateScalar(value)
+	if err != nil {
+		return "", false, err
+	}
+
+	if spec.prefix > 0 && utf8.RuneCountInString(str) > spec.prefix {
+		runes := []rune(str)
+		str = string(runes[:spec.prefix])
+	}
+
+	if op.named {
+		return namedValue(op, spec.name, str), true, nil
+	}
+
+	return encodeTemplateValue(str, op.allowReserved), true, nil
+}
+
+func namedValue(op templateOperator, name, value string) string {
+	if value == "" {
+		return name + op.ifEmpty
+	}
+
+	return name + "=" + encodeTemplateValue(value, op.allowReserved)
+}
+
+func namedPrefix(op templateOperator, name string) string {
+	if !op.named {
+		return ""
+	}
+
+	return name + "="
+}
+
+func templateScalar(value any) (string, error) {
+	switch typed := value.(type) {
+	case string:
+		return typed, nil
+	case int64:
+		return strconv.FormatInt(typed, 10), nil
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(typed), nil
+	case nil:
+		return "", nil
+	default:
+		return "", fmt.Errorf("nested arrays and objects are not supported")
+	}
+}
+
+// encodeTemplateValue percent-encodes everything except unreserved characters.
+// With allowReserved, reserved characters and existing percent-encoded triplets are kept as well.
+func encodeTemplateValue(value string, allowReserved bool) string {
+	var out strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+
+		switch {
+		case isUnreserved(c):
+			out.WriteByte(c)
+		case allowReserved && strings.IndexByte(":/?#[]@!$&'()*+,;=", c) >= 0:
+			out.WriteByte(c)
+		case allowReserved && c == '%' && i+2 < len(value) && isHex(value[i+1]) && isHex(value[i+2]):
+			out.WriteString(value[i : i+3])
+			i += 2
+		default:
+			fmt.Fprintf(&out, "%%%02X", c)
+		}
+	}
+
+	return out.String()
+}
+
+func isUnreserved(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+func TestExpandTemplate(t *testing.T) {
+	t.Parallel()
+
+	vars := map[string]any{
+		"var":   "value",
+		"hello": "Hello World!",
+		"half":  "50%",
+		"empty": "",
+		"path":  "/foo/bar",
+		"base":  "http://example.com/home/",
+		"x":     int64(1024),
+		"y":     int64(768),
+		"list":  []any{"red", "green", "blue"},
+		"ID-1":  "a b",
+		"keys":  map[string]any{"semi": ";", "dot": ".", "comma": ","},
+	}
+
+	cases := map[string]string{
+		"{var}":            "value",
+		"{hello}":          "Hello%20World%21",
+		"{half}":           "50%25",
+		"O{empty}X":        "OX",
+		"O{undef}X":        "OX",
+		"{var:3}":          "val",
+		"{+hello}":         "Hello%20World!",
+		"{+base}index":     "http://example.com/home/index",
+		"{base}index":      "http%3A%2F%2Fexample.com%2Fhome%2Findex",
+		"{+path:6}/here":   "/foo/b/here",
+		"{#hello}":         "#Hello%20World!",
+		"X{.list*}":        "X.red.green.blue",
+		"{/list*,path:4}":  "/red/green/blue/%2Ffoo",
+		"{;x,y,empty}":     ";x=1024;y=768;empty",
+		"{?x,y,empty}":     "?x=1024&y=768&empty=",
+		"{?list}":          "?list=red,green,blue",
+		"{?list*}":         "?list=red&list=green&list=blue",
+		"{keys}":           "comma,%2C,dot,.,semi,%3B",
+		"{?keys*}":         "?comma=%2C&dot=.&semi=%3B",
+		"?fixed=yes{&x}":   "?fixed=yes&x=1024",
+		"/users/{var}{?y}": "/users/value?y=768",
+		"/items/{ID%2D1}":  "/items/a%20b",
+		"{?ID%2D1}":        "?ID%2D1=a%20b",
+	}
+
+	for template, want := range cases {
+		got, err := expandTemplate(template, vars)
+		if err != nil {
+			t.Fatalf("unexpected %s error: %v", template, err)
+		}
+		if got != want {
+			t.Fatalf("expand %s:\nwant %s\n got %s", template, want, got)
+		}
+	}
+
+	for _, invalid := range []string{"{", "}", "{}", "{=var}", "{var:0}", "{var:10000}", "{a b}", "{list:2}", "{.var.}"} {
+		if _, err := expandTemplate(invalid, vars); err == nil {
+			t.Fatalf("expected template %s to fail", invalid)
+		}
+	}
+}
+
+func TestClientExpandsURITemplates(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/orgs/a%2Fb%20c/repos" {
+			t.Fatalf("expected escaped path parameter, got %s", r.URL.EscapedPath())
+		}
+		if r.URL.RawQuery != "type=all&sort=name&page=2" {
+			t.Fatalf("expected template and query parameters, got %s", r.URL.RawQuery)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+	client := NewClient(cfg)
+
+	_, err := client.Query(ctx, runtime.Query{
+		Expression: runtime.NewString("/orgs/{org}/repos{?type,sort,direction}"),
+		Params: object(t, map[string]runtime.Value{
+			"path": object(t, map[string]runtime.Value{
+				"org":  runtime.NewString("a/b c"),
+				"type": runtime.NewString("all"),
+				"sort": runtime.NewString("name"),
+			}),
+			"query": object(t, map[string]runtime.Value{
+				"page": runtime.NewInt(2),
+			}),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+
+	_, err = client.Query(ctx, runtime.Query{
+		Expression: runtime.NewString("/orgs/{org"),
+	})
+	if err == nil {
+		t.Fatal("expected an invalid template to fail")
+	}
+}
+
+func TestClientSendsExpandedTemplateQueryAsIs(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery != "list=red,green,blue&page=2" {
+			t.Errorf("expected the expanded query to be sent as is, got %s", r.URL.RawQuery)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	ctx := networkContext(t)
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+
+	_, err := NewClient(cfg).Query(ctx, runtime.Query{
+		Expression: runtime.NewString("/items{?list}"),
+		Params: object(t, map[string]runtime.Value{
+			"path": object(t, map[string]runtime.Value{
+				"list": runtime.NewArrayWith(runtime.NewString("red"), runtime.NewString("green"), runtime.NewString("blue")),
+			}),
+			"query": object(t, map[string]runtime.Value{
+				"page": runtime.NewInt(2),
+			}),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+}
+
+func TestExpandURITemplateLeavesPlainPaths(t *testing.T) {
+	t.Parallel()
+
+	got, err := expandURITemplate(context.Background(), "/users?active=true", runtime.None)
+	if err != nil {
+		t.Fatalf("unexpected template error: %v", err)
+	}
+	if got != "/users?active=true" {
+		t.Fatalf("expected a plain path to be unchanged, got %s", got)
+	}
+}
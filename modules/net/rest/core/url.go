@@ -23,12 +23,19 @@ func resolveRequestURL(ctx context.Context, baseURL, expression string, query ru
 	}
 
 	resolved := base.ResolveReference(resource)
-	values := resolved.Query()
+	values := url.Values{}
 	if err := appendURLValues(ctx, values, "HTTP query WITH.query", query); err != nil {
 		return "", err
 	}
 
-	resolved.RawQuery = values.Encode()
+	// the query of the expression, like an expanded URI template, is sent as written and WITH.query is appended to it
+	if extra := values.Encode(); extra != "" {
+		if resolved.RawQuery != "" {
+			resolved.RawQuery += "&" + extra
+		} else {
+			resolved.RawQuery = extra
+		}
+	}
 
 	return resolved.String(), nil
 }
@@ -21,12 +21,25 @@ func TestResolveRequestURLMergesQueryValues(t *testing.T) {
 		t.Fatalf("unexpected URL error: %v", err)
 	}
 
-	want := "https://api.example.test/users?active=true&existing=1&limit=50&tag=admin&tag=active"
+	want := "https://api.example.test/users?existing=1&active=true&limit=50&tag=admin&tag=active"
 	if got != want {
 		t.Fatalf("unexpected URL:\nwant %s\n got %s", want, got)
 	}
 }
 
+func TestResolveRequestURLKeepsExpressionQuery(t *testing.T) {
+	t.Parallel()
+
+	got, err := resolveRequestURL(context.Background(), "https://api.example.test", "/items?list=red,green,blue&q=a%20b", runtime.None)
+	if err != nil {
+		t.Fatalf("unexpected URL error: %v", err)
+	}
+
+	if want := "https://api.example.test/items?list=red,green,blue&q=a%20b"; got != want {
+		t.Fatalf("unexpected URL:\nwant %s\n got %s", want, got)
+	}
+}
+
 func TestResolveRequestURLRejectsNestedQueryObjects(t *testing.T) {
 	t.Parallel()
 
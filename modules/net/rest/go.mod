@@ -1,10 +1,16 @@
 module github.com/MontFerret/contrib/modules/net/rest
 
-go 1.25.6
+go 1.26.1
 
 require (
-	github.com/MontFerret/contrib/pkg/common v0.1.1
+	github.com/MontFerret/contrib/modules/csv v1.0.0-rc.17
+	github.com/MontFerret/contrib/modules/security/oauth2 v1.0.0-rc.5
+	github.com/MontFerret/contrib/modules/xml v1.0.0-rc.15
+	github.com/MontFerret/contrib/modules/yaml v1.0.0-rc.15
+	github.com/MontFerret/contrib/pkg/common v0.2.0
 	github.com/MontFerret/ferret/v2 v2.0.0-alpha.46
+	github.com/santhosh-tekuri/jsonschema/v6 v6.0.2
+	github.com/vmihailenco/msgpack/v5 v5.4.1
 )
 
 require (
@@ -12,12 +18,13 @@ require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/gobwas/glob v0.2.3 // indirect
 	github.com/goccy/go-json v0.10.6 // indirect
+	github.com/goccy/go-yaml v1.19.2 // indirect
 	github.com/mattn/go-colorable v0.1.14 // indirect
 	github.com/mattn/go-isatty v0.0.22 // indirect
 	github.com/rs/zerolog v1.35.1 // indirect
-	github.com/vmihailenco/msgpack/v5 v5.4.1 // indirect
 	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
 	golang.org/x/exp v0.0.0-20260410095643-746e56fc9e2f // indirect
 	golang.org/x/sys v0.46.0 // indirect
+	golang.org/x/text v0.38.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -7,5 +7,9 @@ import (
 
 // RegisterLib registers the REST namespace functions in the provided namespace.
 func RegisterLib(ns runtime.Namespace) error {
-	return sdk.RegisterFunctions(ns, sdk.Func("CLIENT", Client))
+	return sdk.RegisterFunctions(ns,
+		sdk.Func("CLIENT", Client),
+		sdk.Func("FROM_OPENAPI", FromOpenAPI),
+		sdk.Func("PAGINATE", Paginate),
+	)
 }
@@ -20,6 +20,8 @@ func TestRegisterLib(t *testing.T) {
 
 	expected := []string{
 		"NET::REST::CLIENT",
+		"NET::REST::FROM_OPENAPI",
+		"NET::REST::PAGINATE",
 	}
 
 	if funcs.Size() != len(expected) {
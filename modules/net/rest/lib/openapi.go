@@ -0,0 +1,32 @@
+package lib
+
+import (
+	"context"
+
+	"github.com/MontFerret/contrib/modules/net/rest/core"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+// FromOpenAPI creates a client for the operations of an OpenAPI 3.x document.
+// Queries name an operationId, and requests and responses are validated against the document when enabled.
+//
+// @param spec {String|Object} OpenAPI document as JSON or YAML text, or as an object.
+// @param config {Object?} Client defaults, server selection and validation.
+// @return {RESTClient} Reusable queryable HTTP client.
+func FromOpenAPI(ctx context.Context, args ...runtime.Value) (runtime.Value, error) {
+	if err := runtime.ValidateArgs(args, 1, 2); err != nil {
+		return runtime.None, err
+	}
+
+	config := runtime.Value(runtime.None)
+	if len(args) > 1 {
+		config = args[1]
+	}
+
+	cfg, api, err := core.DecodeOpenAPIConfig(ctx, args[0], config)
+	if err != nil {
+		return runtime.None, err
+	}
+
+	return core.NewOpenAPIClient(cfg, api), nil
+}
@@ -0,0 +1,55 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MontFerret/contrib/modules/net/rest/core"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+	"github.com/MontFerret/ferret/v2/pkg/sdk"
+)
+
+// Paginate returns a lazy iterator over the items of a paginated endpoint.
+// Pages are fetched on demand, so a loop that stops early does not request the remaining pages.
+//
+// @param client {RESTClient} Client created with NET::REST::CLIENT.
+// @param path {String} Endpoint path or absolute URL.
+// @param request {Object?} Request parameters, like QUERY ... USING.
+// @param options {Object?} Execution options, like QUERY ... OPTIONS. Without a paginate option Link headers are followed.
+// @return {Iterator} Items of every page.
+func Paginate(ctx context.Context, args ...runtime.Value) (runtime.Value, error) {
+	if err := runtime.ValidateArgs(args, 2, 4); err != nil {
+		return runtime.None, err
+	}
+
+	client, ok := args[0].(*core.Client)
+	if !ok {
+		return runtime.None, core.OperationError("PAGINATE", fmt.Errorf("expected a NET::REST client"))
+	}
+
+	path, err := sdk.DecodeArg[string](ctx, args, 1, sdk.RequireType(runtime.TypeString))
+	if err != nil {
+		return runtime.None, err
+	}
+
+	query := runtime.Query{
+		Expression: runtime.NewString(path),
+		Params:     runtime.None,
+		Options:    runtime.None,
+	}
+
+	if len(args) > 2 {
+		query.Params = args[2]
+	}
+
+	if len(args) > 3 {
+		query.Options = args[3]
+	}
+
+	pages, err := client.Paginate(ctx, query)
+	if err != nil {
+		return runtime.None, err
+	}
+
+	return sdk.NewIterableValue(pages), nil
+}
@@ -0,0 +1,38 @@
+package core
+
+type (
+	// ClientHolder is implemented by script values that carry an OAuth client.
+	// It lets other modules accept SECURITY::OAUTH2 client values without depending on the script library.
+	ClientHolder interface {
+		OAuth2Client() *Client
+	}
+
+	// TokenHolder is implemented by script values that carry a token set.
+	TokenHolder interface {
+		OAuth2Token() *TokenSet
+	}
+)
+
+// ClientFrom returns a copy of the OAuth client carried by a value.
+func ClientFrom(value any) (*Client, bool) {
+	holder, ok := value.(ClientHolder)
+	if !ok {
+		return nil, false
+	}
+
+	client := holder.OAuth2Client()
+
+	return client, client != nil
+}
+
+// TokenFrom returns a copy of the token set carried by a value.
+func TokenFrom(value any) (*TokenSet, bool) {
+	holder, ok := value.(TokenHolder)
+	if !ok {
+		return nil, false
+	}
+
+	token := holder.OAuth2Token()
+
+	return token, token != nil
+}
@@ -29,6 +29,11 @@ func (v *clientValue) client() *core.Client {
 	return v.target.Clone()
 }
 
+// OAuth2Client returns a copy of the client, so the value satisfies core.ClientHolder.
+func (v *clientValue) OAuth2Client() *core.Client {
+	return v.client()
+}
+
 func (v *clientValue) String() string {
 	if v == nil || v.target == nil {
 		return runtime.None.String()
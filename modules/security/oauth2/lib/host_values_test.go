@@ -128,6 +128,47 @@ func TestProviderAndClientHostPropertiesAreSafe(t *testing.T) {
 	}
 }
 
+func TestHostValueAccessorsReturnCopies(t *testing.T) {
+	t.Parallel()
+
+	provider, err := core.NewProvider(core.ProviderConfig{
+		TokenEndpoint: "https://issuer.example/token",
+	})
+	if err != nil {
+		t.Fatalf("construct provider: %v", err)
+	}
+	client, err := core.NewClient(provider, core.ClientConfig{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+	})
+	if err != nil {
+		t.Fatalf("construct client: %v", err)
+	}
+
+	clientHost := newClientValue(client)
+	got, ok := core.ClientFrom(clientHost)
+	if !ok || got.ClientID != "client-id" || got.ClientSecret != "client-secret" {
+		t.Fatalf("ClientFrom() = %v, %v", got, ok)
+	}
+
+	got.ClientSecret = "changed"
+	if clientHost.target.ClientSecret != "client-secret" {
+		t.Fatal("ClientFrom returned the host value's client instead of a copy")
+	}
+
+	token, ok := core.TokenFrom(newTokenValue(&core.TokenSet{AccessToken: "access"}))
+	if !ok || token.AccessToken != "access" {
+		t.Fatalf("TokenFrom() = %v, %v", token, ok)
+	}
+
+	if _, ok := core.ClientFrom(runtime.NewString("client")); ok {
+		t.Fatal("ClientFrom accepted a string")
+	}
+	if _, ok := core.TokenFrom(clientHost); ok {
+		t.Fatal("TokenFrom accepted a client")
+	}
+}
+
 func TestTokenHostPropertiesAndExplicitAccessors(t *testing.T) {
 	t.Parallel()
 
@@ -36,6 +36,11 @@ func (v *tokenValue) token() *core.TokenSet {
 	return v.target.Clone()
 }
 
+// OAuth2Token returns a copy of the token set, so the value satisfies core.TokenHolder.
+func (v *tokenValue) OAuth2Token() *core.TokenSet {
+	return v.token()
+}
+
 func (v *tokenValue) now() time.Time {
 	if v == nil || v.clock == nil {
 		return time.Now()
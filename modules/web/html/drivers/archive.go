@@ -0,0 +1,26 @@
+package drivers
+
+// Archive formats.
+const (
+	// ArchiveFormatMHTML represents a multipart/related archive holding the page and its resources.
+	ArchiveFormatMHTML ArchiveFormat = "mhtml"
+
+	// ArchiveFormatHTML represents a single HTML document with its resources inlined.
+	ArchiveFormatHTML ArchiveFormat = "html"
+)
+
+type (
+	// ArchiveFormat represents the format of a page archive.
+	ArchiveFormat string
+
+	// ArchiveParams defines parameters for the archive function.
+	ArchiveParams struct {
+		Format ArchiveFormat `json:"format"`
+	}
+)
+
+func IsArchiveFormatValid(format string) bool {
+	value := ArchiveFormat(format)
+
+	return value == ArchiveFormatMHTML || value == ArchiveFormatHTML
+}
@@ -24,11 +24,13 @@ var (
 	_ drivers.PageFrameTarget    = (*memory.HTMLPage)(nil)
 	_ drivers.PageCookieReader   = (*memory.HTMLPage)(nil)
 	_ drivers.PageResponseTarget = (*memory.HTMLPage)(nil)
+	_ drivers.PageArchiveTarget  = (*memory.HTMLPage)(nil)
 	_ runtime.Queryable          = (*memory.HTMLPage)(nil)
 
 	_ drivers.HTMLDocument           = (*memory.HTMLDocument)(nil)
 	_ drivers.NodeInspector          = (*memory.HTMLDocument)(nil)
 	_ drivers.QueryTarget            = (*memory.HTMLDocument)(nil)
+	_ drivers.XPathTarget            = (*memory.HTMLDocument)(nil)
 	_ drivers.DocumentMetadataTarget = (*memory.HTMLDocument)(nil)
 	_ drivers.DocumentURLTarget      = (*memory.HTMLDocument)(nil)
 	_ runtime.Queryable              = (*memory.HTMLDocument)(nil)
@@ -36,12 +38,14 @@ var (
 	_ drivers.HTMLElement       = (*memory.HTMLElement)(nil)
 	_ drivers.NodeInspector     = (*memory.HTMLElement)(nil)
 	_ drivers.QueryTarget       = (*memory.HTMLElement)(nil)
+	_ drivers.XPathTarget       = (*memory.HTMLElement)(nil)
 	_ drivers.ContentTarget     = (*memory.HTMLElement)(nil)
 	_ drivers.AttributeTarget   = (*memory.HTMLElement)(nil)
 	_ drivers.StyleTarget       = (*memory.HTMLElement)(nil)
 	_ drivers.ValueTarget       = (*memory.HTMLElement)(nil)
 	_ drivers.RelationTarget    = (*memory.HTMLElement)(nil)
 	_ drivers.DOMPropertyTarget = (*memory.HTMLElement)(nil)
+	_ drivers.FormTarget        = (*memory.HTMLElement)(nil)
 	_ runtime.IndexRemovable    = (*memory.HTMLElement)(nil)
 	_ runtime.KeyRemovable      = (*memory.HTMLElement)(nil)
 	_ runtime.Queryable         = (*memory.HTMLElement)(nil)
@@ -53,6 +57,7 @@ var (
 	_ drivers.PageCookieTarget     = (*cdp.HTMLPage)(nil)
 	_ drivers.PageResponseTarget   = (*cdp.HTMLPage)(nil)
 	_ drivers.PageSnapshotTarget   = (*cdp.HTMLPage)(nil)
+	_ drivers.PageArchiveTarget    = (*cdp.HTMLPage)(nil)
 	_ drivers.PageNavigationTarget = (*cdp.HTMLPage)(nil)
 	_ runtime.Observable           = (*cdp.HTMLPage)(nil)
 	_ runtime.Dispatchable         = (*cdp.HTMLPage)(nil)
@@ -61,9 +66,11 @@ var (
 	_ drivers.HTMLDocument           = (*cdpdom.HTMLDocument)(nil)
 	_ drivers.NodeInspector          = (*cdpdom.HTMLDocument)(nil)
 	_ drivers.QueryTarget            = (*cdpdom.HTMLDocument)(nil)
+	_ drivers.XPathTarget            = (*cdpdom.HTMLDocument)(nil)
 	_ drivers.DocumentMetadataTarget = (*cdpdom.HTMLDocument)(nil)
 	_ drivers.DocumentURLTarget      = (*cdpdom.HTMLDocument)(nil)
 	_ drivers.DocumentViewportTarget = (*cdpdom.HTMLDocument)(nil)
+	_ drivers.DocumentGestureTarget  = (*cdpdom.HTMLDocument)(nil)
 	_ runtime.Dispatchable           = (*cdpdom.HTMLDocument)(nil)
 	_ runtime.Queryable              = (*cdpdom.HTMLDocument)(nil)
 	_ drivers.DocumentViewportTarget = (*capabilityDocument)(nil)
@@ -71,11 +78,14 @@ var (
 	_ drivers.HTMLElement       = (*cdpdom.HTMLElement)(nil)
 	_ drivers.NodeInspector     = (*cdpdom.HTMLElement)(nil)
 	_ drivers.QueryTarget       = (*cdpdom.HTMLElement)(nil)
+	_ drivers.XPathTarget       = (*cdpdom.HTMLElement)(nil)
 	_ drivers.ContentTarget     = (*cdpdom.HTMLElement)(nil)
 	_ drivers.ValueTarget       = (*cdpdom.HTMLElement)(nil)
 	_ drivers.RelationTarget    = (*cdpdom.HTMLElement)(nil)
 	_ drivers.DOMPropertyTarget = (*cdpdom.HTMLElement)(nil)
 	_ drivers.InteractionTarget = (*cdpdom.HTMLElement)(nil)
+	_ drivers.GeometryTarget    = (*cdpdom.HTMLElement)(nil)
+	_ drivers.FormTarget        = (*cdpdom.HTMLElement)(nil)
 	_ runtime.IndexRemovable    = (*cdpdom.HTMLElement)(nil)
 	_ runtime.KeyRemovable      = (*cdpdom.HTMLElement)(nil)
 	_ runtime.Dispatchable      = (*cdpdom.HTMLElement)(nil)
@@ -99,6 +109,7 @@ func TestBackendCapabilityMatrix(t *testing.T) {
 	capabilities := []capability{
 		{name: "NodeInspector", typ: reflect.TypeOf((*drivers.NodeInspector)(nil)).Elem()},
 		{name: "QueryTarget", typ: reflect.TypeOf((*drivers.QueryTarget)(nil)).Elem()},
+		{name: "XPathTarget", typ: reflect.TypeOf((*drivers.XPathTarget)(nil)).Elem()},
 		{name: "ContentTarget", typ: reflect.TypeOf((*drivers.ContentTarget)(nil)).Elem()},
 		{name: "AttributeTarget", typ: reflect.TypeOf((*drivers.AttributeTarget)(nil)).Elem()},
 		{name: "StyleTarget", typ: reflect.TypeOf((*drivers.StyleTarget)(nil)).Elem()},
@@ -106,16 +117,20 @@ func TestBackendCapabilityMatrix(t *testing.T) {
 		{name: "RelationTarget", typ: reflect.TypeOf((*drivers.RelationTarget)(nil)).Elem()},
 		{name: "DOMPropertyTarget", typ: reflect.TypeOf((*drivers.DOMPropertyTarget)(nil)).Elem()},
 		{name: "InteractionTarget", typ: reflect.TypeOf((*drivers.InteractionTarget)(nil)).Elem()},
+		{name: "GeometryTarget", typ: reflect.TypeOf((*drivers.GeometryTarget)(nil)).Elem()},
+		{name: "FormTarget", typ: reflect.TypeOf((*drivers.FormTarget)(nil)).Elem()},
 		{name: "WaitTarget", typ: reflect.TypeOf((*drivers.WaitTarget)(nil)).Elem()},
 		{name: "DocumentMetadataTarget", typ: reflect.TypeOf((*drivers.DocumentMetadataTarget)(nil)).Elem()},
 		{name: "DocumentURLTarget", typ: reflect.TypeOf((*drivers.DocumentURLTarget)(nil)).Elem()},
 		{name: "DocumentViewportTarget", typ: reflect.TypeOf((*drivers.DocumentViewportTarget)(nil)).Elem()},
+		{name: "DocumentGestureTarget", typ: reflect.TypeOf((*drivers.DocumentGestureTarget)(nil)).Elem()},
 		{name: "PageStateTarget", typ: reflect.TypeOf((*drivers.PageStateTarget)(nil)).Elem()},
 		{name: "PageFrameTarget", typ: reflect.TypeOf((*drivers.PageFrameTarget)(nil)).Elem()},
 		{name: "PageCookieReader", typ: reflect.TypeOf((*drivers.PageCookieReader)(nil)).Elem()},
 		{name: "PageCookieTarget", typ: reflect.TypeOf((*drivers.PageCookieTarget)(nil)).Elem()},
 		{name: "PageResponseTarget", typ: reflect.TypeOf((*drivers.PageResponseTarget)(nil)).Elem()},
 		{name: "PageSnapshotTarget", typ: reflect.TypeOf((*drivers.PageSnapshotTarget)(nil)).Elem()},
+		{name: "PageArchiveTarget", typ: reflect.TypeOf((*drivers.PageArchiveTarget)(nil)).Elem()},
 		{name: "PageNavigationTarget", typ: reflect.TypeOf((*drivers.PageNavigationTarget)(nil)).Elem()},
 		{name: "IndexRemovable", typ: reflect.TypeOf((*runtime.IndexRemovable)(nil)).Elem()},
 		{name: "KeyRemovable", typ: reflect.TypeOf((*runtime.KeyRemovable)(nil)).Elem()},
@@ -130,6 +145,7 @@ func TestBackendCapabilityMatrix(t *testing.T) {
 				"PageFrameTarget":    true,
 				"PageCookieReader":   true,
 				"PageResponseTarget": true,
+				"PageArchiveTarget":  true,
 			},
 		},
 		{
@@ -138,6 +154,7 @@ func TestBackendCapabilityMatrix(t *testing.T) {
 			supported: map[string]bool{
 				"NodeInspector":          true,
 				"QueryTarget":            true,
+				"XPathTarget":            true,
 				"DocumentMetadataTarget": true,
 				"DocumentURLTarget":      true,
 			},
@@ -148,12 +165,14 @@ func TestBackendCapabilityMatrix(t *testing.T) {
 			supported: map[string]bool{
 				"NodeInspector":     true,
 				"QueryTarget":       true,
+				"XPathTarget":       true,
 				"ContentTarget":     true,
 				"AttributeTarget":   true,
 				"StyleTarget":       true,
 				"ValueTarget":       true,
 				"RelationTarget":    true,
 				"DOMPropertyTarget": true,
+				"FormTarget":        true,
 				"IndexRemovable":    true,
 				"KeyRemovable":      true,
 			},
@@ -168,6 +187,7 @@ func TestBackendCapabilityMatrix(t *testing.T) {
 				"PageCookieTarget":     true,
 				"PageResponseTarget":   true,
 				"PageSnapshotTarget":   true,
+				"PageArchiveTarget":    true,
 				"PageNavigationTarget": true,
 			},
 		},
@@ -177,9 +197,11 @@ func TestBackendCapabilityMatrix(t *testing.T) {
 			supported: map[string]bool{
 				"NodeInspector":          true,
 				"QueryTarget":            true,
+				"XPathTarget":            true,
 				"DocumentMetadataTarget": true,
 				"DocumentURLTarget":      true,
 				"DocumentViewportTarget": true,
+				"DocumentGestureTarget":  true,
 			},
 		},
 		{
@@ -188,11 +210,14 @@ func TestBackendCapabilityMatrix(t *testing.T) {
 			supported: map[string]bool{
 				"NodeInspector":     true,
 				"QueryTarget":       true,
+				"XPathTarget":       true,
 				"ContentTarget":     true,
 				"ValueTarget":       true,
 				"RelationTarget":    true,
 				"DOMPropertyTarget": true,
 				"InteractionTarget": true,
+				"GeometryTarget":    true,
+				"FormTarget":        true,
 				"IndexRemovable":    true,
 				"KeyRemovable":      true,
 			},
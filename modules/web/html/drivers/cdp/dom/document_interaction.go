@@ -37,3 +37,27 @@ func (doc *HTMLDocument) Scroll(ctx context.Context, options drivers.ScrollOptio
 		return state.input.ScrollByXY(ctx, options)
 	})
 }
+
+func (doc *HTMLDocument) Drag(ctx context.Context, from, to drivers.GesturePoint, options drivers.GestureOptions) error {
+	return withDocumentError(ctx, doc, func(state *documentState) error {
+		return state.input.Drag(ctx, state.element.id, from, to, options)
+	})
+}
+
+func (doc *HTMLDocument) Tap(ctx context.Context, at drivers.GesturePoint) error {
+	return withDocumentError(ctx, doc, func(state *documentState) error {
+		return state.input.Tap(ctx, state.element.id, at)
+	})
+}
+
+func (doc *HTMLDocument) Swipe(ctx context.Context, from, to drivers.GesturePoint, options drivers.GestureOptions) error {
+	return withDocumentError(ctx, doc, func(state *documentState) error {
+		return state.input.Swipe(ctx, state.element.id, from, to, options)
+	})
+}
+
+func (doc *HTMLDocument) Pinch(ctx context.Context, at drivers.GesturePoint, options drivers.PinchOptions) error {
+	return withDocumentError(ctx, doc, func(state *documentState) error {
+		return state.input.Pinch(ctx, state.element.id, at, options)
+	})
+}
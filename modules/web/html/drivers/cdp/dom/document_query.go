@@ -84,6 +84,12 @@ func (doc *HTMLDocument) XPath(ctx context.Context, expression runtime.String) (
 	})
 }
 
+func (doc *HTMLDocument) XPathWithOptions(ctx context.Context, expression runtime.String, options drivers.XPathOptions) (runtime.Value, error) {
+	return withDocumentResult(ctx, doc, func(state *documentState) (runtime.Value, error) {
+		return state.element.XPathWithOptions(ctx, expression, options)
+	})
+}
+
 func (doc *HTMLDocument) Query(ctx context.Context, q runtime.Query) (runtime.List, error) {
 	return withDocumentResult(ctx, doc, func(state *documentState) (runtime.List, error) {
 		return state.element.Query(ctx, q)
@@ -0,0 +1,128 @@
+package dom
+
+import (
+	"context"
+	"time"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/contrib/modules/web/html/drivers/cdp/input"
+	"github.com/MontFerret/contrib/modules/web/html/drivers/cdp/templates"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+	"github.com/MontFerret/ferret/v2/pkg/sdk"
+)
+
+type formControl struct {
+	Kind    string `json:"kind"`
+	Value   string `json:"value"`
+	Checked bool   `json:"checked"`
+}
+
+// FillForm fills the controls contained in the element.
+// Text controls are typed into, checkboxes and radio buttons are clicked and selects and date-like controls are set directly.
+func (el *HTMLElement) FillForm(ctx context.Context, fields []drivers.FormField, options drivers.FormFillOptions) (drivers.FormFillResult, error) {
+	var result drivers.FormFillResult
+
+	for _, field := range fields {
+		controls, err := el.executor.EvalElements(ctx, templates.FindFormControls(el.id, field.Name))
+		if err != nil {
+			return result, err
+		}
+
+		length, err := controls.Length(ctx)
+		if err != nil {
+			return result, err
+		}
+
+		if length == 0 {
+			result.Unmatched = append(result.Unmatched, field.Name)
+
+			continue
+		}
+
+		err = controls.ForEach(ctx, func(ctx context.Context, value runtime.Value, _ runtime.Int) (runtime.Boolean, error) {
+			control, ok := value.(*HTMLElement)
+			if !ok {
+				return false, runtime.TypeErrorOf(value, drivers.HTMLElementType)
+			}
+
+			return true, control.fillFormControl(ctx, field.Value, options)
+		})
+
+		if err != nil {
+			return result, err
+		}
+
+		result.Filled = append(result.Filled, field.Name)
+	}
+
+	if options.Submit {
+		if err := el.executor.Eval(ctx, templates.SubmitForm(el.id)); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+func (el *HTMLElement) fillFormControl(ctx context.Context, value runtime.Value, options drivers.FormFillOptions) error {
+	out, err := el.executor.EvalValue(ctx, templates.GetFormControl(el.id))
+	if err != nil {
+		return err
+	}
+
+	var control formControl
+
+	if err := sdk.Decode(ctx, out, &control); err != nil {
+		return err
+	}
+
+	switch control.Kind {
+	case "checkbox", "radio":
+		checked, err := drivers.FormChecked(ctx, value, control.Value)
+		if err != nil {
+			return err
+		}
+
+		// radio buttons are unchecked by checking another button of the group
+		if checked == control.Checked || (control.Kind == "radio" && !checked) {
+			return nil
+		}
+
+		return el.executor.run(ctx, func() error { return el.input.Click(ctx, el.id, 1) })
+	case "select":
+		values, err := drivers.FormValueStrings(ctx, value)
+		if err != nil {
+			return err
+		}
+
+		list := runtime.NewArray(len(values))
+
+		for _, item := range values {
+			_ = list.Append(ctx, runtime.NewString(item))
+		}
+
+		return el.executor.run(ctx, func() error {
+			_, err := el.input.Select(ctx, el.id, list)
+
+			return err
+		})
+	case "value":
+		return el.executor.run(ctx, func() error { return el.input.SetValue(ctx, el.id, formText(value)) })
+	default:
+		return el.executor.run(ctx, func() error {
+			return el.input.Type(ctx, el.id, input.TypeParams{
+				Text:  formText(value).String(),
+				Clear: control.Value != "",
+				Delay: time.Duration(options.Delay) * time.Millisecond,
+			})
+		})
+	}
+}
+
+func formText(value runtime.Value) runtime.String {
+	if value == nil || value == runtime.None {
+		return runtime.EmptyString
+	}
+
+	return runtime.NewString(value.String())
+}
@@ -0,0 +1,73 @@
+package dom
+
+import (
+	"context"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers/cdp/input"
+	"github.com/MontFerret/contrib/modules/web/html/drivers/cdp/templates"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+func (el *HTMLElement) GetBoundingBox(ctx context.Context) (runtime.Value, error) {
+	box, err := el.boundingBox(ctx)
+	if err != nil || box == nil {
+		return runtime.None, err
+	}
+
+	return runtime.NewObjectWith(map[string]runtime.Value{
+		"x":      runtime.NewFloat(box.X),
+		"y":      runtime.NewFloat(box.Y),
+		"width":  runtime.NewFloat(box.Width),
+		"height": runtime.NewFloat(box.Height),
+	}), nil
+}
+
+// IsVisible reports whether the element has a non-empty box and is not hidden by its own or inherited styles.
+func (el *HTMLElement) IsVisible(ctx context.Context) (runtime.Boolean, error) {
+	box, err := el.boundingBox(ctx)
+	if err != nil {
+		return runtime.False, err
+	}
+
+	if box == nil || box.Width <= 0 || box.Height <= 0 {
+		return runtime.False, nil
+	}
+
+	out, err := el.executor.EvalValue(ctx, templates.IsStyleVisible(el.id))
+	if err != nil {
+		return runtime.False, err
+	}
+
+	return runtime.ToBoolean(out), nil
+}
+
+func (el *HTMLElement) IsInViewport(ctx context.Context) (runtime.Boolean, error) {
+	return runElementResult(
+		ctx,
+		el.executor,
+		func() runtime.Boolean { return runtime.False },
+		func() (runtime.Boolean, error) {
+			inViewport, err := el.input.IsInViewport(ctx, el.id)
+
+			return runtime.Boolean(inViewport), err
+		},
+	)
+}
+
+func (el *HTMLElement) IsEnabled(ctx context.Context) (runtime.Boolean, error) {
+	out, err := el.executor.EvalValue(ctx, templates.IsEnabled(el.id))
+	if err != nil {
+		return runtime.False, err
+	}
+
+	return runtime.ToBoolean(out), nil
+}
+
+func (el *HTMLElement) boundingBox(ctx context.Context) (*input.Box, error) {
+	return runElementResult(
+		ctx,
+		el.executor,
+		func() *input.Box { return nil },
+		func() (*input.Box, error) { return el.input.BoundingBox(ctx, el.id) },
+	)
+}
@@ -0,0 +1,188 @@
+package dom
+
+import (
+	"context"
+
+	"github.com/antchfx/xpath"
+	"github.com/goccy/go-json"
+	"golang.org/x/net/html"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/contrib/modules/web/html/drivers/cdp/templates"
+	"github.com/MontFerret/contrib/modules/web/html/drivers/internal/xpathutil"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+type (
+	// xpathSnapshotNode is a node of the document tree serialized by the XPath snapshot template.
+	xpathSnapshotNode struct {
+		Name       string               `json:"n"`
+		Namespace  string               `json:"ns"`
+		Value      string               `json:"v"`
+		Attributes [][2]string          `json:"a"`
+		Children   []*xpathSnapshotNode `json:"c"`
+		Type       int                  `json:"t"`
+		Context    bool                 `json:"x"`
+	}
+
+	// xpathMatch is a node matched in a document snapshot, addressed for the resolve template.
+	// Text and comment nodes are addressed by the path of their parent element and their child index.
+	xpathMatch struct {
+		Kind  string `json:"kind"`
+		Value string `json:"value,omitempty"`
+		Path  []int  `json:"path"`
+		Index int    `json:"index,omitempty"`
+	}
+)
+
+// XPathWithOptions evaluates an expression with namespace-prefix bindings.
+// Expressions limited to the XPath 1.0 function library run natively in the browser with a namespace resolver.
+// Expressions calling extended functions are evaluated over a snapshot of the document,
+// and the matched nodes are resolved back to live nodes, so both paths return nodes and attribute values alike.
+// Matches are resolved by their position in the snapshot: if the document changes between the snapshot
+// and the resolution, a match may resolve to a different node or be dropped.
+func (el *HTMLElement) XPathWithOptions(ctx context.Context, expression runtime.String, options drivers.XPathOptions) (runtime.Value, error) {
+	options, err := drivers.NormalizeXPathOptions(options)
+	if err != nil {
+		return runtime.None, err
+	}
+
+	if !xpathutil.RequiresExtendedFunctions(expression.String()) {
+		return el.executor.EvalResult(ctx, templates.XPathWithNamespaces(el.id, expression, options.Namespaces))
+	}
+
+	exp, err := xpathutil.Compile(expression.String(), options.Namespaces)
+	if err != nil {
+		return runtime.None, err
+	}
+
+	data, err := el.executor.EvalValue(ctx, templates.XPathSnapshot(el.id))
+	if err != nil {
+		return runtime.None, err
+	}
+
+	var snapshot *xpathSnapshotNode
+
+	if err := json.Unmarshal([]byte(data.String()), &snapshot); err != nil {
+		return runtime.None, runtime.Error(err, "decode document snapshot")
+	}
+
+	root, current := buildXPathSnapshot(snapshot)
+
+	out := exp.Evaluate(xpathutil.NewNavigatorAt(root, current, options.Namespaces))
+
+	res, ok := out.(*xpath.NodeIterator)
+	if !ok {
+		return runtime.Parse(out), nil
+	}
+
+	matches := make([]xpathMatch, 0, 10)
+
+	for res.MoveNext() {
+		node := res.Current()
+
+		switch node.NodeType() {
+		case xpath.AttributeNode:
+			matches = append(matches, xpathMatch{Kind: "value", Value: node.Value()})
+		case xpath.TextNode, xpath.CommentNode:
+			child := xpathutil.Node(node)
+
+			matches = append(matches, xpathMatch{Kind: "node", Path: xpathElementPath(child.Parent), Index: xpathChildIndex(child)})
+		case xpath.ElementNode:
+			matches = append(matches, xpathMatch{Kind: "element", Path: xpathElementPath(xpathutil.Node(node))})
+		case xpath.RootNode:
+			matches = append(matches, xpathMatch{Kind: "document"})
+		}
+	}
+
+	if len(matches) == 0 {
+		return runtime.NewArray(0), nil
+	}
+
+	return el.executor.EvalResult(ctx, templates.XPathResolve(el.id, matches))
+}
+
+// buildXPathSnapshot builds an HTML tree from a document snapshot.
+// It returns the document node and the node of the element the snapshot was taken from.
+func buildXPathSnapshot(snapshot *xpathSnapshotNode) (*html.Node, *html.Node) {
+	doc := &html.Node{Type: html.DocumentNode}
+	current := doc
+
+	var build func(parent *html.Node, item *xpathSnapshotNode)
+
+	build = func(parent *html.Node, item *xpathSnapshotNode) {
+		node := &html.Node{}
+
+		switch item.Type {
+		case 1:
+			node.Type = html.ElementNode
+			node.Data = item.Name
+			node.Namespace = item.Namespace
+			node.Attr = make([]html.Attribute, 0, len(item.Attributes))
+
+			for _, attr := range item.Attributes {
+				node.Attr = append(node.Attr, html.Attribute{Key: attr[0], Val: attr[1]})
+			}
+		case 3:
+			node.Type = html.TextNode
+			node.Data = item.Value
+		case 8:
+			node.Type = html.CommentNode
+			node.Data = item.Value
+		default:
+			return
+		}
+
+		parent.AppendChild(node)
+
+		if item.Context {
+			current = node
+		}
+
+		for _, child := range item.Children {
+			if child != nil {
+				build(node, child)
+			}
+		}
+	}
+
+	if snapshot != nil {
+		build(doc, snapshot)
+	}
+
+	return doc, current
+}
+
+// xpathElementPath returns the element child indexes leading from the document element to the node.
+func xpathElementPath(node *html.Node) []int {
+	path := make([]int, 0, 8)
+
+	for ; node.Parent != nil && node.Parent.Type == html.ElementNode; node = node.Parent {
+		idx := 0
+
+		for sibling := node.PrevSibling; sibling != nil; sibling = sibling.PrevSibling {
+			if sibling.Type == html.ElementNode {
+				idx++
+			}
+		}
+
+		path = append(path, idx)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path
+}
+
+// xpathChildIndex returns the index of the node among the child nodes of its parent in the snapshot.
+func xpathChildIndex(node *html.Node) int {
+	idx := 0
+
+	for sibling := node.PrevSibling; sibling != nil; sibling = sibling.PrevSibling {
+		idx++
+	}
+
+	return idx
+}
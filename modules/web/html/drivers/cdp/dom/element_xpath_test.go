@@ -0,0 +1,121 @@
+package dom
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/antchfx/xpath"
+	"github.com/goccy/go-json"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers/internal/xpathutil"
+)
+
+const xpathSnapshotFixture = `{
+	"t": 1, "n": "html", "a": [], "c": [
+		{"t": 1, "n": "body", "a": [], "c": [
+			{"t": 8, "v": "feed"},
+			{"t": 1, "n": "div", "a": [["xmlns:feed", "urn:vendor:feed"]], "x": true, "c": [
+				{"t": 1, "n": "feed:item", "a": [["id", "a"]], "c": [{"t": 3, "v": "First"}]},
+				{"t": 3, "v": " "},
+				{"t": 1, "n": "feed:item", "a": [["id", "b"]], "c": [{"t": 3, "v": "Second"}]}
+			]},
+			{"t": 1, "n": "svg", "ns": "svg", "a": [], "c": [{"t": 1, "n": "rect", "ns": "svg", "a": [], "c": []}]}
+		]}
+	]
+}`
+
+func TestXPathSnapshotEvaluation(t *testing.T) {
+	t.Parallel()
+
+	var snapshot *xpathSnapshotNode
+
+	if err := json.Unmarshal([]byte(xpathSnapshotFixture), &snapshot); err != nil {
+		t.Fatalf("decode snapshot: %v", err)
+	}
+
+	root, current := buildXPathSnapshot(snapshot)
+
+	if current.Data != "div" {
+		t.Fatalf("expected the flagged element to be the context node, got %q", current.Data)
+	}
+
+	namespaces := map[string]string{
+		"v":   "urn:vendor:feed",
+		"svg": xpathutil.SVGNamespace,
+	}
+
+	cases := []struct {
+		expression string
+		want       [][]int
+	}{
+		{expression: "v:item[ends-with(@id, 'b')]", want: [][]int{{0, 0, 1}}},
+		{expression: "//svg:rect[string-length(lower-case(name())) = 4]", want: [][]int{{0, 1, 0}}},
+		{expression: "//*[matches(local-name(), '^(html|body)$')]", want: [][]int{{}, {0}}},
+	}
+
+	for _, tc := range cases {
+		exp, err := xpathutil.Compile(tc.expression, namespaces)
+		if err != nil {
+			t.Fatalf("compile %q: %v", tc.expression, err)
+		}
+
+		res, ok := exp.Evaluate(xpathutil.NewNavigatorAt(root, current, namespaces)).(*xpath.NodeIterator)
+		if !ok {
+			t.Fatalf("expected %q to select nodes", tc.expression)
+		}
+
+		paths := make([][]int, 0, len(tc.want))
+
+		for res.MoveNext() {
+			paths = append(paths, xpathElementPath(xpathutil.Node(res.Current())))
+		}
+
+		if !reflect.DeepEqual(paths, tc.want) {
+			t.Fatalf("expected %q to match %v, got %v", tc.expression, tc.want, paths)
+		}
+	}
+}
+
+func TestXPathSnapshotNodeAddress(t *testing.T) {
+	t.Parallel()
+
+	var snapshot *xpathSnapshotNode
+
+	if err := json.Unmarshal([]byte(xpathSnapshotFixture), &snapshot); err != nil {
+		t.Fatalf("decode snapshot: %v", err)
+	}
+
+	root, current := buildXPathSnapshot(snapshot)
+
+	cases := []struct {
+		expression string
+		path       []int
+		index      int
+	}{
+		{expression: "//*[ends-with(@id, 'b')]/text()", path: []int{0, 0, 1}, index: 0},
+		{expression: "//comment()[starts-with(., 'fe')]", path: []int{0}, index: 0},
+		{expression: "//*[matches(local-name(), '^div$')]/text()", path: []int{0, 0}, index: 1},
+	}
+
+	for _, tc := range cases {
+		exp, err := xpathutil.Compile(tc.expression, nil)
+		if err != nil {
+			t.Fatalf("compile %q: %v", tc.expression, err)
+		}
+
+		res, ok := exp.Evaluate(xpathutil.NewNavigatorAt(root, current, nil)).(*xpath.NodeIterator)
+		if !ok || !res.MoveNext() {
+			t.Fatalf("expected %q to select a node", tc.expression)
+		}
+
+		node := xpathutil.Node(res.Current())
+
+		if path := xpathElementPath(node.Parent); !reflect.DeepEqual(path, tc.path) {
+			t.Fatalf("expected %q to match a child of %v, got %v", tc.expression, tc.path, path)
+		}
+
+		if idx := xpathChildIndex(node); idx != tc.index {
+			t.Fatalf("expected %q to match child %d, got %d", tc.expression, tc.index, idx)
+		}
+	}
+}
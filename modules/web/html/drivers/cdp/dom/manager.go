@@ -16,6 +16,7 @@ type Manager struct {
 	rootClient *cdp.Client
 	mouse      *input.Mouse
 	keyboard   *input.Keyboard
+	touch      *input.Touch
 	mainFrame  *AtomicFrameID
 	frames     *AtomicFrameCollection
 	owners     *AtomicFrameClientCollection
@@ -27,6 +28,7 @@ func New(
 	client *cdp.Client,
 	mouse *input.Mouse,
 	keyboard *input.Keyboard,
+	touch *input.Touch,
 ) (manager *Manager, err error) {
 
 	manager = new(Manager)
@@ -34,6 +36,7 @@ func New(
 	manager.rootClient = client
 	manager.mouse = mouse
 	manager.keyboard = keyboard
+	manager.touch = touch
 	manager.mainFrame = NewAtomicFrameID()
 	manager.frames = NewAtomicFrameCollection()
 	manager.owners = NewAtomicFrameClientCollection()
@@ -44,7 +44,7 @@ func (m *Manager) loadDocumentState(ctx context.Context, doc *HTMLDocument, fram
 		return nil, err
 	}
 
-	inputs := input.New(m.logger, client, exec, m.keyboard, m.mouse)
+	inputs := input.New(m.logger, client, exec, m.keyboard, m.mouse, m.touch)
 
 	ref, err := exec.EvalRef(ctx, templates.GetDocument())
 	if err != nil {
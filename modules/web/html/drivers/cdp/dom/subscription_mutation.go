@@ -0,0 +1,140 @@
+package dom
+
+import (
+	"context"
+
+	cdpruntime "github.com/mafredri/cdp/protocol/runtime"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers/cdp/templates"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+const (
+	domMutationEventName = "mutation"
+
+	domMutationOptionAttributes = "attributes"
+	domMutationOptionChildList  = "childList"
+	domMutationOptionSubtree    = "subtree"
+)
+
+type domMutationOptions struct {
+	Subtree    runtime.Boolean
+	ChildList  runtime.Boolean
+	Attributes runtime.Boolean
+}
+
+// defaultDOMMutationOptions observes nodes added to or removed from the whole subtree.
+func defaultDOMMutationOptions() domMutationOptions {
+	return domMutationOptions{
+		Subtree:   runtime.True,
+		ChildList: runtime.True,
+	}
+}
+
+func subscribeDOMMutations(
+	ctx context.Context,
+	api domBindingRuntime,
+	evaluator domEventEvaluator,
+	targetID cdpruntime.RemoteObjectID,
+	subscription runtime.Subscription,
+) (runtime.Stream, error) {
+	options, err := parseDOMMutationOptions(ctx, subscription.Options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	config := buildDOMMutationTemplateOptions(options)
+
+	return subscribeDOMEvents(
+		ctx,
+		api,
+		evaluator.ContextID(),
+		func(ctx context.Context, bindingName string) error {
+			return evaluator.Eval(ctx, templates.ObserveMutations(targetID, bindingName, config))
+		},
+		func(ctx context.Context, bindingName string) error {
+			return evaluator.Eval(ctx, templates.DisconnectMutations(targetID, bindingName))
+		},
+	)
+}
+
+func parseDOMMutationOptions(ctx context.Context, value runtime.Map) (domMutationOptions, error) {
+	options := defaultDOMMutationOptions()
+
+	if value == nil {
+		return options, nil
+	}
+
+	keys, err := value.Keys(ctx)
+
+	if err != nil {
+		return options, err
+	}
+
+	length, err := keys.Length(ctx)
+
+	if err != nil {
+		return options, err
+	}
+
+	for idx := runtime.NewInt(0); idx < length; idx++ {
+		keyValue, err := keys.At(ctx, idx)
+
+		if err != nil {
+			return options, err
+		}
+
+		key, err := runtime.CastString(keyValue)
+
+		if err != nil {
+			return options, runtime.Errorf(runtime.ErrInvalidArgument, "options key: %s", err)
+		}
+
+		var field *runtime.Boolean
+
+		switch key {
+		case domMutationOptionSubtree:
+			field = &options.Subtree
+		case domMutationOptionChildList:
+			field = &options.ChildList
+		case domMutationOptionAttributes:
+			field = &options.Attributes
+		default:
+			return options, runtime.Errorf(runtime.ErrInvalidArgument, "unknown mutation event option: %s", key)
+		}
+
+		optionValue, err := value.Get(ctx, key)
+
+		if err != nil {
+			return options, err
+		}
+
+		enabled, err := runtime.CastBoolean(optionValue)
+
+		if err != nil {
+			return options, runtime.Errorf(runtime.ErrInvalidArgument, "%s: %s", key, err)
+		}
+
+		*field = enabled
+	}
+
+	if !options.ChildList && !options.Attributes {
+		return options, runtime.Errorf(
+			runtime.ErrInvalidArgument,
+			"at least one of %s or %s must be enabled",
+			domMutationOptionChildList,
+			domMutationOptionAttributes,
+		)
+	}
+
+	return options, nil
+}
+
+func buildDOMMutationTemplateOptions(options domMutationOptions) runtime.Map {
+	return runtime.NewObjectWith(map[string]runtime.Value{
+		domMutationOptionSubtree:    options.Subtree,
+		domMutationOptionChildList:  options.ChildList,
+		domMutationOptionAttributes: options.Attributes,
+	})
+}
@@ -0,0 +1,76 @@
+package dom
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseDOMMutationOptions(t *testing.T) {
+	Convey("parseDOMMutationOptions", t, func() {
+		ctx := context.Background()
+
+		Convey("Should observe child nodes of the whole subtree by default", func() {
+			options, err := parseDOMMutationOptions(ctx, nil)
+
+			So(err, ShouldBeNil)
+			So(options.Subtree, ShouldEqual, runtime.True)
+			So(options.ChildList, ShouldEqual, runtime.True)
+			So(options.Attributes, ShouldEqual, runtime.False)
+		})
+
+		Convey("Should override defaults and preserve them in template config", func() {
+			options, err := parseDOMMutationOptions(ctx, runtime.NewObjectWith(map[string]runtime.Value{
+				domMutationOptionSubtree:    runtime.False,
+				domMutationOptionChildList:  runtime.False,
+				domMutationOptionAttributes: runtime.True,
+			}))
+
+			So(err, ShouldBeNil)
+
+			config := buildDOMMutationTemplateOptions(options)
+
+			subtree, err := config.Get(ctx, runtime.NewString(domMutationOptionSubtree))
+			So(err, ShouldBeNil)
+			So(subtree, ShouldEqual, runtime.False)
+
+			childList, err := config.Get(ctx, runtime.NewString(domMutationOptionChildList))
+			So(err, ShouldBeNil)
+			So(childList, ShouldEqual, runtime.False)
+
+			attributes, err := config.Get(ctx, runtime.NewString(domMutationOptionAttributes))
+			So(err, ShouldBeNil)
+			So(attributes, ShouldEqual, runtime.True)
+		})
+
+		Convey("Should reject unknown options", func() {
+			_, err := parseDOMMutationOptions(ctx, runtime.NewObjectWith(map[string]runtime.Value{
+				"characterData": runtime.True,
+			}))
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "unknown mutation event option")
+		})
+
+		Convey("Should validate option types", func() {
+			_, err := parseDOMMutationOptions(ctx, runtime.NewObjectWith(map[string]runtime.Value{
+				domMutationOptionSubtree: runtime.NewString("yes"),
+			}))
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, domMutationOptionSubtree)
+		})
+
+		Convey("Should require child list or attribute observation", func() {
+			_, err := parseDOMMutationOptions(ctx, runtime.NewObjectWith(map[string]runtime.Value{
+				domMutationOptionChildList: runtime.False,
+			}))
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "must be enabled")
+		})
+	})
+}
@@ -22,6 +22,10 @@ func subscribeDOMTargetEvents(
 	targetID cdpruntime.RemoteObjectID,
 	subscription runtime.Subscription,
 ) (runtime.Stream, error) {
+	if subscription.EventName == domMutationEventName {
+		return subscribeDOMMutations(ctx, api, evaluator, targetID, subscription)
+	}
+
 	options, err := parseDOMEventOptions(ctx, subscription.Options)
 
 	if err != nil {
@@ -12,6 +12,7 @@ import (
 	"github.com/MontFerret/contrib/modules/web/html/drivers"
 	cdpsession "github.com/MontFerret/contrib/modules/web/html/drivers/cdp/session"
 	"github.com/MontFerret/ferret/v2/pkg/logging"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
 )
 
 const DriverName = "cdp"
@@ -42,7 +43,9 @@ func (drv *Driver) Name() string {
 func (drv *Driver) Open(ctx context.Context, params drivers.Params) (drivers.HTMLPage, error) {
 	logger := logging.From(ctx)
 
-	sessions, err := drv.createSessionManager(ctx, params.KeepCookies)
+	params.Proxy = drv.pageProxy(ctx, params)
+
+	sessions, err := drv.createSessionManager(ctx, params.KeepCookies, params.Proxy)
 
 	if err != nil {
 		logger.Error().
@@ -53,13 +56,35 @@ func (drv *Driver) Open(ctx context.Context, params drivers.Params) (drivers.HTM
 		return nil, err
 	}
 
-	return LoadHTMLPage(ctx, sessions, drv.setDefaultParams(params))
+	params = drv.setDefaultParams(params)
+
+	var jar *drivers.CookieJar
+
+	if drv.options.CookieJar != "" {
+		jar, err = drivers.OpenCookieJar(ctx, drv.options.CookieJar)
+		if err != nil {
+			_ = sessions.Close()
+
+			return nil, err
+		}
+
+		params = jar.SetDefaultParams(params)
+	}
+
+	page, err := LoadHTMLPage(ctx, sessions, params)
+	if err != nil {
+		return nil, err
+	}
+
+	page.jar = jar
+
+	return page, nil
 }
 
 func (drv *Driver) Parse(ctx context.Context, params drivers.ParseParams) (drivers.HTMLPage, error) {
 	logger := logging.From(ctx)
 
-	sessions, err := drv.createSessionManager(ctx, true)
+	sessions, err := drv.createSessionManager(ctx, true, "")
 
 	if err != nil {
 		logger.Error().
@@ -84,18 +109,50 @@ func (drv *Driver) Close() error {
 	return nil
 }
 
-func (drv *Driver) createSessionManager(ctx context.Context, keepCookies bool) (*cdpsession.Manager, error) {
+// pageProxy returns the proxy of a page. The driver proxy is only a default: pages that keep cookies share
+// the default browser context and open without it, while an explicit page proxy with keepCookies is rejected later.
+func (drv *Driver) pageProxy(ctx context.Context, params drivers.Params) string {
+	if params.Proxy != "" || drv.options.Proxy == "" {
+		return params.Proxy
+	}
+
+	if drv.options.KeepCookies || params.KeepCookies {
+		logging.From(ctx).Warn().
+			Str("driver", drv.options.Name).
+			Msg("driver proxy is not applied to pages that keep cookies")
+
+		return ""
+	}
+
+	return drv.options.Proxy
+}
+
+func (drv *Driver) createSessionManager(ctx context.Context, keepCookies bool, proxy string) (*cdpsession.Manager, error) {
+	isolated := !drv.options.KeepCookies && !keepCookies
+	browserContextArgs := target.NewCreateBrowserContextArgs().SetDisposeOnDetach(true)
+
+	// proxies are set per browser context, so the page cannot share the default one
+	if proxy != "" {
+		if !isolated {
+			return nil, runtime.Error(runtime.ErrInvalidArgument, "proxy cannot be combined with keepCookies")
+		}
+
+		server, _, err := parseProxy(proxy)
+		if err != nil {
+			return nil, err
+		}
+
+		browserContextArgs.SetProxyServer(server)
+	}
+
 	browserConn, browserClient, err := drv.openBrowser(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "initialize driver")
 	}
 
 	createTargetArgs := target.NewCreateTargetArgs(BlankPageURL)
-	if !drv.options.KeepCookies && !keepCookies {
-		ctxReply, err := browserClient.Target.CreateBrowserContext(
-			ctx,
-			target.NewCreateBrowserContextArgs().SetDisposeOnDetach(true),
-		)
+	if isolated {
+		ctxReply, err := browserClient.Target.CreateBrowserContext(ctx, browserContextArgs)
 		if err != nil {
 			_ = browserConn.Close()
 
@@ -127,6 +184,10 @@ func (drv *Driver) setDefaultParams(params drivers.Params) drivers.Params {
 		params.Viewport = defaultViewport
 	}
 
+	if params.Stealth == nil && drv.options.Stealth != nil {
+		params.Stealth = drv.options.Stealth
+	}
+
 	return drivers.SetDefaultParams(drv.options.Options, params)
 }
 
@@ -55,6 +55,10 @@ func enableFeatures(ctx context.Context, client *cdp.Client, params drivers.Para
 		},
 
 		func() error {
+			if params.Stealth != nil {
+				return overrideStealthUserAgent(ctx, client, params)
+			}
+
 			ua := useragent.Resolve(params.UserAgent)
 
 			// do not use custom user agent
@@ -76,6 +80,14 @@ func enableFeatures(ctx context.Context, client *cdp.Client, params drivers.Para
 			return client.Page.SetBypassCSP(ctx, page.NewSetBypassCSPArgs(true))
 		},
 
+		func() error {
+			if params.Stealth == nil {
+				return nil
+			}
+
+			return registerStealthScript(ctx, client, params)
+		},
+
 		func() error {
 			if params.Viewport == nil {
 				return nil
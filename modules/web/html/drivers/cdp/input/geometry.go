@@ -0,0 +1,92 @@
+package input
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/mafredri/cdp/protocol/dom"
+	cdpruntime "github.com/mafredri/cdp/protocol/runtime"
+	"github.com/mafredri/cdp/rpcc"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers/cdp/utils"
+)
+
+// Box is the border box of an element in viewport coordinates.
+type Box struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+// BoundingBox returns the border box of an element, or nil when the element is not rendered.
+func (m *Manager) BoundingBox(ctx context.Context, objectID cdpruntime.RemoteObjectID) (*Box, error) {
+	reply, err := m.client.DOM.GetBoxModel(ctx, dom.NewGetBoxModelArgs().SetObjectID(objectID))
+	if err != nil {
+		if isNoLayoutError(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return boxFromQuad(fromProtocolQuad(reply.Model.Border)), nil
+}
+
+// IsInViewport reports whether a visible part of an element intersects the layout viewport.
+func (m *Manager) IsInViewport(ctx context.Context, objectID cdpruntime.RemoteObjectID) (bool, error) {
+	reply, err := m.client.DOM.GetContentQuads(ctx, dom.NewGetContentQuadsArgs().SetObjectID(objectID))
+	if err != nil {
+		if isNoLayoutError(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	if len(reply.Quads) == 0 {
+		return false, nil
+	}
+
+	metrics, err := m.client.Page.GetLayoutMetrics(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	width, height := utils.GetLayoutViewportWH(metrics)
+
+	for _, quad := range reply.Quads {
+		if computeQuadArea(intersectQuadWithViewport(fromProtocolQuad(quad), float64(width), float64(height))) > 1 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func boxFromQuad(quad []Quad) *Box {
+	left, top := math.Inf(1), math.Inf(1)
+	right, bottom := math.Inf(-1), math.Inf(-1)
+
+	for _, point := range quad {
+		left = math.Min(left, point.X)
+		top = math.Min(top, point.Y)
+		right = math.Max(right, point.X)
+		bottom = math.Max(bottom, point.Y)
+	}
+
+	return &Box{X: left, Y: top, Width: right - left, Height: bottom - top}
+}
+
+// isNoLayoutError reports whether the browser could not compute the geometry of a node without a layout object,
+// such as elements with display: none or text nodes.
+func isNoLayoutError(err error) bool {
+	var responseErr *rpcc.ResponseError
+	if !errors.As(err, &responseErr) || responseErr.Code != -32000 {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(responseErr.Message), "could not compute")
+}
@@ -30,6 +30,7 @@ type (
 		exec     *eval.Runtime
 		keyboard *Keyboard
 		mouse    *Mouse
+		touch    *Touch
 	}
 )
 
@@ -39,6 +40,7 @@ func New(
 	exec *eval.Runtime,
 	keyboard *Keyboard,
 	mouse *Mouse,
+	touch *Touch,
 ) *Manager {
 	logger = logutil.WithComponent(logger.With(), "input_manager").Logger()
 
@@ -48,6 +50,7 @@ func New(
 		exec,
 		keyboard,
 		mouse,
+		touch,
 	}
 }
 
@@ -58,3 +61,7 @@ func (m *Manager) Keyboard() *Keyboard {
 func (m *Manager) Mouse() *Mouse {
 	return m.mouse
 }
+
+func (m *Manager) Touch() *Touch {
+	return m.touch
+}
@@ -264,3 +264,21 @@ func (m *Manager) SelectBySelector(ctx context.Context, id cdpruntime.RemoteObje
 
 	return m.selectTarget(ctx, selectorTarget(id, selector), value)
 }
+
+// SetValue sets the value of a control directly and dispatches input and change events.
+// It is used for controls such as date and color pickers, whose typed input depends on the browser locale.
+func (m *Manager) SetValue(ctx context.Context, objectID cdpruntime.RemoteObjectID, value runtime.String) error {
+	m.logger.Trace().
+		Str("object_id", string(objectID)).
+		Msg("starting to set value")
+
+	if err := m.exec.Eval(ctx, templates.SetFormValue(objectID, value)); err != nil {
+		m.logger.Trace().Err(err).Msg("failed to set value")
+
+		return err
+	}
+
+	m.logger.Trace().Msg("set value")
+
+	return nil
+}
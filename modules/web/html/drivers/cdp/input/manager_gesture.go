@@ -0,0 +1,216 @@
+package input
+
+import (
+	"context"
+	"time"
+
+	"github.com/mafredri/cdp/protocol/input"
+	cdpruntime "github.com/mafredri/cdp/protocol/runtime"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+// dragInterceptTimeout is how long a drag waits for the browser to report an HTML5 drag operation
+// after the pointer reached the drop point.
+const dragInterceptTimeout = 250 * time.Millisecond
+
+// Drag presses the left mouse button at one point, moves the mouse to another one and releases it there.
+// HTML5 drag-and-drop operations started by the movement are intercepted and dropped with drag events.
+func (m *Manager) Drag(ctx context.Context, id cdpruntime.RemoteObjectID, from, to drivers.GesturePoint, options drivers.GestureOptions) error {
+	steps := gestureSteps(options.Steps)
+
+	m.logger.Trace().Int("steps", steps).Msg("starting to drag")
+
+	start, err := m.gesturePoint(ctx, id, from)
+	if err != nil {
+		return err
+	}
+
+	if err := m.client.Input.SetInterceptDrags(ctx, input.NewSetInterceptDragsArgs(true)); err != nil {
+		m.logger.Trace().Err(err).Msg("failed to enable drag interception")
+
+		return err
+	}
+
+	defer func() {
+		if err := m.client.Input.SetInterceptDrags(context.Background(), input.NewSetInterceptDragsArgs(false)); err != nil {
+			m.logger.Trace().Err(err).Msg("failed to disable drag interception")
+		}
+	}()
+
+	intercepted, err := m.client.Input.DragIntercepted(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer intercepted.Close()
+
+	dragged := make(chan input.DragData, 1)
+
+	go func() {
+		reply, err := intercepted.Recv()
+		if err == nil {
+			dragged <- reply.Data
+		}
+	}()
+
+	if err := m.mouse.Move(ctx, start.X, start.Y); err != nil {
+		return err
+	}
+
+	if err := m.mouse.Down(ctx, input.MouseButtonLeft); err != nil {
+		return err
+	}
+
+	end, err := m.gesturePoint(ctx, id, to)
+	if err != nil {
+		m.release()
+
+		return err
+	}
+
+	if err := m.mouse.MoveBySteps(ctx, end.X, end.Y, steps); err != nil {
+		m.release()
+
+		return err
+	}
+
+	select {
+	case data := <-dragged:
+		m.logger.Trace().Msg("dropping an intercepted drag operation")
+
+		for _, event := range []string{"dragEnter", "dragOver", "drop"} {
+			if err := m.client.Input.DispatchDragEvent(ctx, input.NewDispatchDragEventArgs(event, end.X, end.Y, data)); err != nil {
+				m.logger.Trace().Err(err).Str("event", event).Msg("failed to dispatch a drag event")
+				m.release()
+
+				return err
+			}
+		}
+	case <-time.After(dragInterceptTimeout):
+		m.logger.Trace().Msg("no drag operation was started. releasing the mouse")
+	case <-ctx.Done():
+		m.release()
+
+		return ctx.Err()
+	}
+
+	if err := m.mouse.Up(ctx, input.MouseButtonLeft); err != nil {
+		return err
+	}
+
+	m.logger.Trace().Msg("dragged")
+
+	return nil
+}
+
+// release lifts the left mouse button after a failed drag. It does not use the drag context,
+// which may be the cause of the failure.
+func (m *Manager) release() {
+	if err := m.mouse.Up(context.Background(), input.MouseButtonLeft); err != nil {
+		m.logger.Trace().Err(err).Msg("failed to release the mouse")
+	}
+}
+
+func (m *Manager) Tap(ctx context.Context, id cdpruntime.RemoteObjectID, at drivers.GesturePoint) error {
+	if err := m.touchSupported(); err != nil {
+		return err
+	}
+
+	point, err := m.gesturePoint(ctx, id, at)
+	if err != nil {
+		return err
+	}
+
+	m.logger.Trace().Float64("x", point.X).Float64("y", point.Y).Msg("tapping")
+
+	return m.touch.Tap(ctx, point.X, point.Y)
+}
+
+func (m *Manager) Swipe(ctx context.Context, id cdpruntime.RemoteObjectID, from, to drivers.GesturePoint, options drivers.GestureOptions) error {
+	if err := m.touchSupported(); err != nil {
+		return err
+	}
+
+	start, err := m.gesturePoint(ctx, id, from)
+	if err != nil {
+		return err
+	}
+
+	end, err := m.gesturePoint(ctx, id, to)
+	if err != nil {
+		return err
+	}
+
+	m.logger.Trace().
+		Float64("from_x", start.X).
+		Float64("from_y", start.Y).
+		Float64("to_x", end.X).
+		Float64("to_y", end.Y).
+		Msg("swiping")
+
+	return m.touch.Swipe(ctx, start.X, start.Y, end.X, end.Y, gestureSteps(options.Steps))
+}
+
+func (m *Manager) Pinch(ctx context.Context, id cdpruntime.RemoteObjectID, at drivers.GesturePoint, options drivers.PinchOptions) error {
+	if err := m.touchSupported(); err != nil {
+		return err
+	}
+
+	scale := float64(options.Scale)
+	if scale == 0 {
+		scale = drivers.DefaultPinchScale
+	}
+
+	if scale < 0 {
+		return runtime.Errorf(runtime.ErrInvalidArgument, "pinch scale must be greater than 0")
+	}
+
+	center, err := m.gesturePoint(ctx, id, at)
+	if err != nil {
+		return err
+	}
+
+	m.logger.Trace().Float64("scale", scale).Msg("pinching")
+
+	return m.touch.Pinch(ctx, center.X, center.Y, defaultPinchDistance, defaultPinchDistance*scale, gestureSteps(options.Steps))
+}
+
+func (m *Manager) touchSupported() error {
+	if m.touch.Enabled() {
+		return nil
+	}
+
+	return runtime.Error(runtime.ErrNotSupported, "touch gestures require a page opened with mobile viewport emulation")
+}
+
+// gesturePoint returns the viewport coordinates of a gesture point.
+// Selected elements are scrolled into view and addressed by their clickable center.
+func (m *Manager) gesturePoint(ctx context.Context, id cdpruntime.RemoteObjectID, point drivers.GesturePoint) (Quad, error) {
+	if point.Selector == nil {
+		return Quad{X: float64(point.X), Y: float64(point.Y)}, nil
+	}
+
+	objectID, err := m.resolveTargetID(ctx, selectorTarget(id, *point.Selector), interactionScrollOptions())
+	if err != nil {
+		return Quad{}, err
+	}
+
+	quad, err := getClickablePointByObjectID(ctx, m.client, objectID)
+	if err != nil {
+		m.logger.Trace().Err(err).Msg("failed calculating clickable element points")
+
+		return Quad{}, err
+	}
+
+	return quad, nil
+}
+
+func gestureSteps(steps runtime.Int) int {
+	if steps <= 0 {
+		return drivers.DefaultGestureSteps
+	}
+
+	return int(steps)
+}
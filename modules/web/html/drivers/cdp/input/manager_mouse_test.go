@@ -207,7 +207,7 @@ func newMouseMoveManager(pageAPI cdp.Page, inputAPI cdp.Input) (*Manager, *Mouse
 	}
 	mouse := NewMouse(client)
 
-	return New(zerolog.Nop(), client, nil, nil, mouse), mouse
+	return New(zerolog.Nop(), client, nil, nil, mouse, nil), mouse
 }
 
 func assertLastMouseMove(t *testing.T, inputAPI *mouseDispatchInput, expectedX, expectedY float64) {
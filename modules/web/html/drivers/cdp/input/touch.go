@@ -0,0 +1,119 @@
+package input
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mafredri/cdp"
+	"github.com/mafredri/cdp/protocol/emulation"
+	"github.com/mafredri/cdp/protocol/input"
+)
+
+const (
+	// defaultPinchDistance is the distance in pixels between the two fingers when a pinch starts.
+	defaultPinchDistance = 100.0
+
+	// maxTouchPoints is the number of touch points reported once touch emulation is enabled.
+	maxTouchPoints = 5
+)
+
+type Touch struct {
+	client   *cdp.Client
+	mu       sync.Mutex
+	enabled  bool
+	emulated bool
+}
+
+// NewTouch returns a touch screen. Touch events are only dispatched to pages with mobile emulation.
+// Touch emulation is enabled by the first gesture, so pages that are never touched keep their pointer behavior.
+func NewTouch(client *cdp.Client, enabled bool) *Touch {
+	return &Touch{client: client, enabled: enabled}
+}
+
+func (t *Touch) Enabled() bool {
+	return t != nil && t.enabled
+}
+
+func (t *Touch) Tap(ctx context.Context, x, y float64) error {
+	if err := t.dispatch(ctx, "touchStart", input.TouchPoint{X: x, Y: y}); err != nil {
+		return err
+	}
+
+	return t.dispatch(ctx, "touchEnd")
+}
+
+func (t *Touch) Swipe(ctx context.Context, fromX, fromY, toX, toY float64, steps int) error {
+	if err := t.dispatch(ctx, "touchStart", input.TouchPoint{X: fromX, Y: fromY}); err != nil {
+		return err
+	}
+
+	for i := 1; i <= steps; i++ {
+		progress := float64(i) / float64(steps)
+		point := input.TouchPoint{
+			X: fromX + (toX-fromX)*progress,
+			Y: fromY + (toY-fromY)*progress,
+		}
+
+		if err := t.dispatch(ctx, "touchMove", point); err != nil {
+			return err
+		}
+	}
+
+	return t.dispatch(ctx, "touchEnd")
+}
+
+// Pinch moves two fingers placed horizontally around the center from one distance to another.
+func (t *Touch) Pinch(ctx context.Context, x, y, fromDistance, toDistance float64, steps int) error {
+	fingers := func(distance float64) []input.TouchPoint {
+		return []input.TouchPoint{
+			{X: x - distance/2, Y: y},
+			{X: x + distance/2, Y: y},
+		}
+	}
+
+	if err := t.dispatch(ctx, "touchStart", fingers(fromDistance)...); err != nil {
+		return err
+	}
+
+	for i := 1; i <= steps; i++ {
+		distance := fromDistance + (toDistance-fromDistance)*(float64(i)/float64(steps))
+
+		if err := t.dispatch(ctx, "touchMove", fingers(distance)...); err != nil {
+			return err
+		}
+	}
+
+	return t.dispatch(ctx, "touchEnd")
+}
+
+func (t *Touch) dispatch(ctx context.Context, event string, points ...input.TouchPoint) error {
+	if err := t.emulate(ctx); err != nil {
+		return err
+	}
+
+	if points == nil {
+		// touchEnd must carry an empty list of touch points
+		points = []input.TouchPoint{}
+	}
+
+	return t.client.Input.DispatchTouchEvent(ctx, input.NewDispatchTouchEventArgs(event, points))
+}
+
+// emulate exposes touch support to the page scripts before the first touch event.
+func (t *Touch) emulate(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.emulated {
+		return nil
+	}
+
+	args := emulation.NewSetTouchEmulationEnabledArgs(true).SetMaxTouchPoints(maxTouchPoints)
+	if err := t.client.Emulation.SetTouchEmulationEnabled(ctx, args); err != nil {
+		return err
+	}
+
+	t.emulated = true
+
+	return nil
+}
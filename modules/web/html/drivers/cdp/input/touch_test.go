@@ -0,0 +1,128 @@
+package input
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mafredri/cdp"
+	cdpinput "github.com/mafredri/cdp/protocol/input"
+	"github.com/rs/zerolog"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+type touchDispatchInput struct {
+	cdp.Input
+	calls []*cdpinput.DispatchTouchEventArgs
+}
+
+func (i *touchDispatchInput) DispatchTouchEvent(_ context.Context, args *cdpinput.DispatchTouchEventArgs) error {
+	i.calls = append(i.calls, args)
+
+	return nil
+}
+
+func newTouchManager(enabled bool) (*Manager, *touchDispatchInput) {
+	inputAPI := &touchDispatchInput{}
+	client := &cdp.Client{Input: inputAPI}
+
+	return New(zerolog.Nop(), client, nil, nil, NewMouse(client), NewTouch(client, enabled)), inputAPI
+}
+
+func TestManagerTouchGesturesRequireMobileEmulation(t *testing.T) {
+	manager, inputAPI := newTouchManager(false)
+	at := drivers.NewGesturePointByXY(10, 20)
+
+	for name, gesture := range map[string]func() error{
+		"tap":   func() error { return manager.Tap(context.Background(), "", at) },
+		"swipe": func() error { return manager.Swipe(context.Background(), "", at, at, drivers.GestureOptions{}) },
+		"pinch": func() error { return manager.Pinch(context.Background(), "", at, drivers.PinchOptions{}) },
+	} {
+		if err := gesture(); !errors.Is(err, runtime.ErrNotSupported) {
+			t.Fatalf("%s: expected not supported error, got %v", name, err)
+		}
+	}
+
+	if len(inputAPI.calls) != 0 {
+		t.Fatalf("expected no touch events, got %d", len(inputAPI.calls))
+	}
+}
+
+func TestManagerTapDispatchesTouchStartAndEnd(t *testing.T) {
+	manager, inputAPI := newTouchManager(true)
+
+	if err := manager.Tap(context.Background(), "", drivers.NewGesturePointByXY(10, 20)); err != nil {
+		t.Fatalf("unexpected tap error: %v", err)
+	}
+
+	assertTouchEvents(t, inputAPI, "touchStart", "touchEnd")
+
+	start := inputAPI.calls[0].TouchPoints
+	if len(start) != 1 || start[0].X != 10 || start[0].Y != 20 {
+		t.Fatalf("expected a single touch point at (10, 20), got %+v", start)
+	}
+
+	if end := inputAPI.calls[1].TouchPoints; end == nil || len(end) != 0 {
+		t.Fatalf("expected touchEnd with an empty touch point list, got %+v", end)
+	}
+}
+
+func TestManagerSwipeInterpolatesTouchMoves(t *testing.T) {
+	manager, inputAPI := newTouchManager(true)
+	from := drivers.NewGesturePointByXY(200, 100)
+	to := drivers.NewGesturePointByXY(0, 100)
+
+	if err := manager.Swipe(context.Background(), "", from, to, drivers.GestureOptions{Steps: 2}); err != nil {
+		t.Fatalf("unexpected swipe error: %v", err)
+	}
+
+	assertTouchEvents(t, inputAPI, "touchStart", "touchMove", "touchMove", "touchEnd")
+
+	if x := inputAPI.calls[1].TouchPoints[0].X; x != 100 {
+		t.Fatalf("expected the first move halfway at x=100, got %v", x)
+	}
+
+	if x := inputAPI.calls[2].TouchPoints[0].X; x != 0 {
+		t.Fatalf("expected the last move at the end point, got %v", x)
+	}
+}
+
+func TestManagerPinchMovesTwoFingers(t *testing.T) {
+	manager, inputAPI := newTouchManager(true)
+
+	if err := manager.Pinch(context.Background(), "", drivers.NewGesturePointByXY(200, 300), drivers.PinchOptions{Scale: 0.5, Steps: 1}); err != nil {
+		t.Fatalf("unexpected pinch error: %v", err)
+	}
+
+	assertTouchEvents(t, inputAPI, "touchStart", "touchMove", "touchEnd")
+
+	start := inputAPI.calls[0].TouchPoints
+	if len(start) != 2 || start[0].X != 150 || start[1].X != 250 {
+		t.Fatalf("expected fingers 100px apart around the center, got %+v", start)
+	}
+
+	moved := inputAPI.calls[1].TouchPoints
+	if len(moved) != 2 || moved[0].X != 175 || moved[1].X != 225 {
+		t.Fatalf("expected fingers 50px apart after pinching in, got %+v", moved)
+	}
+
+	if err := manager.Pinch(context.Background(), "", drivers.NewGesturePointByXY(0, 0), drivers.PinchOptions{Scale: -1}); !errors.Is(err, runtime.ErrInvalidArgument) {
+		t.Fatalf("expected invalid scale error, got %v", err)
+	}
+}
+
+func assertTouchEvents(t *testing.T, inputAPI *touchDispatchInput, expected ...string) {
+	t.Helper()
+
+	if len(inputAPI.calls) != len(expected) {
+		t.Fatalf("expected %d touch events, got %d", len(expected), len(inputAPI.calls))
+	}
+
+	for i, event := range expected {
+		if got := inputAPI.calls[i].Type; got != event {
+			t.Fatalf("expected touch event %d to be %q, got %q", i, event, got)
+		}
+	}
+}
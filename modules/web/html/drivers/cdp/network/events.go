@@ -14,6 +14,7 @@ import (
 var (
 	responseReceivedEvent = events.New("response_received")
 	requestPausedEvent    = events.New("request_paused")
+	authRequiredEvent     = events.New("auth_required")
 )
 
 func createResponseReceivedStreamFactory(client *cdp.Client) events.SourceFactory {
@@ -31,3 +32,11 @@ func createRequestPausedStreamFactory(client *cdp.Client) events.SourceFactory {
 		return stream.(fetch.RequestPausedClient).Recv()
 	})
 }
+
+func createAuthRequiredStreamFactory(client *cdp.Client) events.SourceFactory {
+	return events.NewStreamSourceFactory(authRequiredEvent, func(ctx context.Context) (rpcc.Stream, error) {
+		return client.Fetch.AuthRequired(ctx)
+	}, func(stream rpcc.Stream) (any, error) {
+		return stream.(fetch.AuthRequiredClient).Recv()
+	})
+}
@@ -22,12 +22,13 @@ type (
 	}
 
 	Interceptor struct {
-		logger  zerolog.Logger
-		client  *cdp.Client
-		filters map[string]*InterceptorFilter
-		loop    *events.Loop
-		mu      sync.RWMutex
-		running bool
+		logger    zerolog.Logger
+		client    *cdp.Client
+		filters   map[string]*InterceptorFilter
+		loop      *events.Loop
+		proxyAuth *ProxyAuth
+		mu        sync.RWMutex
+		running   bool
 	}
 
 	InterceptorFilter struct {
@@ -89,8 +90,12 @@ func NewInterceptor(logger zerolog.Logger, client *cdp.Client) *Interceptor {
 	i.logger = logutil.WithComponent(logger.With(), "network_interceptor").Logger()
 	i.client = client
 	i.filters = make(map[string]*InterceptorFilter)
-	i.loop = events.NewLoop(createRequestPausedStreamFactory(client))
+	i.loop = events.NewLoop(
+		createRequestPausedStreamFactory(client),
+		createAuthRequiredStreamFactory(client),
+	)
 	i.loop.AddListener(requestPausedEvent, events.Always(i.filter))
+	i.loop.AddListener(authRequiredEvent, events.Always(i.authenticate))
 
 	return i
 }
@@ -124,6 +129,15 @@ func (i *Interceptor) RemoveFilter(name string) {
 	delete(i.filters, name)
 }
 
+// SetProxyAuth sets the credentials answered to proxy authentication challenges.
+// It must be called before the interceptor runs.
+func (i *Interceptor) SetProxyAuth(auth *ProxyAuth) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.proxyAuth = auth
+}
+
 func (i *Interceptor) AddListener(listener InterceptorListener) events.ListenerID {
 	i.mu.Lock()
 	defer i.mu.Unlock()
@@ -154,7 +168,13 @@ func (i *Interceptor) Run(ctx context.Context) error {
 		return nil
 	}
 
-	err := i.client.Fetch.Enable(ctx, fetch.NewEnableArgs())
+	args := fetch.NewEnableArgs()
+
+	if i.proxyAuth != nil {
+		args.SetHandleAuthRequests(true)
+	}
+
+	err := i.client.Fetch.Enable(ctx, args)
 	i.running = err == nil
 
 	if err != nil {
@@ -241,3 +261,36 @@ func (i *Interceptor) filter(ctx context.Context, message any) {
 
 	log.Trace().Msg("succeeded to block resource loading")
 }
+
+// authenticate answers proxy challenges with the configured credentials
+// and leaves server challenges to the browser defaults.
+func (i *Interceptor) authenticate(ctx context.Context, message any) {
+	i.mu.RLock()
+	auth := i.proxyAuth
+	i.mu.RUnlock()
+
+	msg, ok := message.(*fetch.AuthRequiredReply)
+
+	if !ok {
+		return
+	}
+
+	response := fetch.AuthChallengeResponse{Response: "Default"}
+
+	if auth != nil && msg.AuthChallenge.Source != nil && *msg.AuthChallenge.Source == "Proxy" {
+		response = fetch.AuthChallengeResponse{
+			Response: "ProvideCredentials",
+			Username: &auth.Username,
+			Password: &auth.Password,
+		}
+	}
+
+	err := i.client.Fetch.ContinueWithAuth(ctx, fetch.NewContinueWithAuthArgs(msg.RequestID, response))
+
+	if err != nil {
+		i.logger.Err(err).
+			Str("request_id", string(msg.RequestID)).
+			Str("origin", msg.AuthChallenge.Origin).
+			Msg("failed to answer authentication challenge")
+	}
+}
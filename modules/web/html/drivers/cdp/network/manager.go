@@ -61,11 +61,19 @@ func New(
 		}
 	}()
 
-	if options.Filter != nil && len(options.Filter.Patterns) > 0 {
+	hasFilter := options.Filter != nil && len(options.Filter.Patterns) > 0
+
+	if hasFilter || options.ProxyAuth != nil {
 		m.interceptor = NewInterceptor(logger, client)
 
-		if err = m.interceptor.AddFilter("resources", options.Filter); err != nil {
-			return nil, err
+		if hasFilter {
+			if err = m.interceptor.AddFilter("resources", options.Filter); err != nil {
+				return nil, err
+			}
+		}
+
+		if options.ProxyAuth != nil {
+			m.interceptor.SetProxyAuth(options.ProxyAuth)
 		}
 
 		if err = m.interceptor.Run(ctx); err != nil {
@@ -15,10 +15,17 @@ type (
 		Patterns []drivers.ResourceFilter
 	}
 
+	// ProxyAuth holds the credentials answered to proxy authentication challenges.
+	ProxyAuth struct {
+		Username string
+		Password string
+	}
+
 	Options struct {
-		Cookies Cookies
-		Headers *drivers.HTTPHeaders
-		Filter  *Filter
+		Cookies   Cookies
+		Headers   *drivers.HTTPHeaders
+		Filter    *Filter
+		ProxyAuth *ProxyAuth
 	}
 
 	WaitEventOptions struct {
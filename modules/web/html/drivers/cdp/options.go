@@ -13,6 +13,7 @@ type (
 	Options struct {
 		*drivers.Options
 		Connection  *ConnectionOptions
+		Stealth     *drivers.Stealth
 		Address     string
 		KeepCookies bool
 	}
@@ -103,6 +104,21 @@ func WithCookies(cookies []drivers.HTTPCookie) Option {
 	}
 }
 
+// WithCookieJar loads page cookies from a Netscape or JSON cookie jar file of the Ferret filesystem
+// and saves them back when the page is closed.
+func WithCookieJar(path string) Option {
+	return func(opts *Options) {
+		drivers.WithCookieJar(path)(opts.Options)
+	}
+}
+
+// WithStealth applies the fingerprint patches of the stealth profile to every page without its own profile.
+func WithStealth(profile drivers.Stealth) Option {
+	return func(opts *Options) {
+		opts.Stealth = &profile
+	}
+}
+
 func WithBufferSize(size int) Option {
 	return func(opts *Options) {
 		opts.Connection.BufferSize = size
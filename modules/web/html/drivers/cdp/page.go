@@ -29,6 +29,7 @@ type (
 		network    *cdpnet.Manager
 		dom        *dom.Manager
 		initScript *drivers.InitScript
+		jar        *drivers.CookieJar
 		mu         sync.Mutex
 		closed     runtime.Boolean
 	}
@@ -50,6 +51,10 @@ func LoadHTMLPage(
 		return nil, err
 	}
 
+	if params.Stealth, err = drivers.NormalizeStealth(params.Stealth); err != nil {
+		return nil, err
+	}
+
 	if sessions == nil {
 		return nil, runtime.Error(runtime.ErrMissedArgument, "sessions")
 	}
@@ -111,6 +116,12 @@ func LoadHTMLPage(
 		}
 	}
 
+	if params.Proxy != "" {
+		if _, netOpts.ProxyAuth, err = parseProxy(params.Proxy); err != nil {
+			return nil, err
+		}
+	}
+
 	netManager, err = cdpnet.New(
 		logger,
 		client,
@@ -124,12 +135,14 @@ func LoadHTMLPage(
 
 	mouse := input.NewMouse(client)
 	keyboard := input.NewKeyboard(client)
+	touch := input.NewTouch(client, params.Viewport != nil && params.Viewport.Mobile)
 
 	domManager, err = dom.New(
 		logger,
 		client,
 		mouse,
 		keyboard,
+		touch,
 	)
 
 	if err != nil {
@@ -23,6 +23,16 @@ func (p *HTMLPage) Close() error {
 		}
 	}
 
+	if p.jar != nil && p.network != nil {
+		if err := p.saveCookieJar(url); err != nil {
+			p.logger.Warn().
+				Str("url", url).
+				Str("cookie_jar", p.jar.Path()).
+				Err(err).
+				Msg("failed to save cookie jar")
+		}
+	}
+
 	if p.dom != nil {
 		if err := p.dom.Close(); err != nil {
 			p.logger.Warn().
@@ -61,3 +71,12 @@ func (p *HTMLPage) Close() error {
 
 	return nil
 }
+
+func (p *HTMLPage) saveCookieJar(url string) error {
+	cookies, err := p.network.GetCookies(context.Background(), url)
+	if err != nil {
+		return err
+	}
+
+	return p.jar.Sync(url, cookies)
+}
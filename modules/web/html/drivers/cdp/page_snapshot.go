@@ -1,12 +1,16 @@
 package cdp
 
 import (
+	"bytes"
 	"context"
 
 	"github.com/mafredri/cdp/protocol/page"
+	"github.com/pkg/errors"
+	"golang.org/x/net/html"
 
 	"github.com/MontFerret/contrib/modules/web/html/drivers"
 	"github.com/MontFerret/contrib/modules/web/html/drivers/cdp/utils"
+	"github.com/MontFerret/contrib/modules/web/html/drivers/internal/archive"
 	"github.com/MontFerret/ferret/v2/pkg/runtime"
 )
 
@@ -122,3 +126,39 @@ func (p *HTMLPage) CaptureScreenshot(ctx context.Context, params drivers.Screens
 
 	return runtime.NewBinary(reply.Data), nil
 }
+
+func (p *HTMLPage) Archive(ctx context.Context, params drivers.ArchiveParams) (runtime.Binary, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if params.Format != "" && !drivers.IsArchiveFormatValid(string(params.Format)) {
+		return runtime.NewBinary(nil), runtime.Errorf(runtime.ErrInvalidArgument, "archive format %q", params.Format)
+	}
+
+	reply, err := p.client.Page.CaptureSnapshot(ctx, page.NewCaptureSnapshotArgs().SetFormat("mhtml"))
+	if err != nil {
+		return runtime.NewBinary(nil), err
+	}
+
+	if params.Format != drivers.ArchiveFormatHTML {
+		return runtime.NewBinary([]byte(reply.Data)), nil
+	}
+
+	// the snapshot holds every resource of the page, so the HTML archive is built from it without network access
+	snapshot, err := archive.ReadMHTML([]byte(reply.Data))
+	if err != nil {
+		return runtime.NewBinary(nil), err
+	}
+
+	doc, err := html.Parse(bytes.NewReader(snapshot.Main.Body))
+	if err != nil {
+		return runtime.NewBinary(nil), errors.Wrap(err, "parse snapshot document")
+	}
+
+	out, err := archive.HTML(ctx, doc, snapshot.Main.URL, snapshot.Fetch)
+	if err != nil {
+		return runtime.NewBinary(nil), err
+	}
+
+	return runtime.NewBinary(out), nil
+}
@@ -0,0 +1,35 @@
+package cdp
+
+import (
+	"net/url"
+
+	"github.com/pkg/errors"
+
+	cdpnet "github.com/MontFerret/contrib/modules/web/html/drivers/cdp/network"
+)
+
+// parseProxy splits a proxy URL into the server address accepted by Chrome
+// and the credentials answered to its authentication challenges.
+func parseProxy(address string) (string, *cdpnet.ProxyAuth, error) {
+	proxyURL, err := url.Parse(address)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "parse proxy %q", address)
+	}
+
+	if proxyURL.Scheme == "" || proxyURL.Host == "" {
+		return "", nil, errors.Errorf("invalid proxy %q: expected scheme://host:port", address)
+	}
+
+	server := (&url.URL{Scheme: proxyURL.Scheme, Host: proxyURL.Host}).String()
+
+	if proxyURL.User == nil {
+		return server, nil, nil
+	}
+
+	password, _ := proxyURL.User.Password()
+
+	return server, &cdpnet.ProxyAuth{
+		Username: proxyURL.User.Username(),
+		Password: password,
+	}, nil
+}
@@ -0,0 +1,232 @@
+package cdp
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/mafredri/cdp"
+	"github.com/mafredri/cdp/protocol/emulation"
+	"github.com/mafredri/cdp/protocol/page"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/contrib/modules/web/html/drivers/cdp/templates"
+	"github.com/MontFerret/contrib/modules/web/html/internal/useragent"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+type stealthScriptConfig struct {
+	Languages     []string               `json:"languages"`
+	Skip          []drivers.StealthPatch `json:"skip"`
+	WebGLVendor   string                 `json:"webglVendor"`
+	WebGLRenderer string                 `json:"webglRenderer"`
+}
+
+var (
+	defaultStealthLanguages = []string{"en-US", "en"}
+	chromeVersionExp        = regexp.MustCompile(`(?:Headless)?Chrome/(\d+)\.[\d.]+`)
+)
+
+// registerStealthScript patches the fingerprint of every new document of the page.
+func registerStealthScript(ctx context.Context, client *cdp.Client, params drivers.Params) error {
+	config, err := json.Marshal(stealthScriptConfig{
+		Languages:     stealthLanguages(params),
+		Skip:          params.Stealth.Skip,
+		WebGLVendor:   params.Stealth.WebGLVendor,
+		WebGLRenderer: params.Stealth.WebGLRenderer,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Page.AddScriptToEvaluateOnNewDocument(
+		ctx,
+		page.NewAddScriptToEvaluateOnNewDocumentArgs(templates.Stealth(config)),
+	)
+	if err != nil {
+		return runtime.Error(err, "register stealth script")
+	}
+
+	return nil
+}
+
+// overrideStealthUserAgent sets a user agent, its client hints, the platform and the Accept-Language header
+// consistent with the stealth profile.
+// Without a configured user agent, the one reported by the browser is used.
+func overrideStealthUserAgent(ctx context.Context, client *cdp.Client, params drivers.Params) error {
+	profile := params.Stealth
+	ua := useragent.Resolve(params.UserAgent)
+
+	if ua == "" {
+		version, err := client.Browser.GetVersion(ctx)
+		if err != nil {
+			return err
+		}
+
+		ua = version.UserAgent
+	}
+
+	if profile.Enabled(drivers.StealthUserAgent) {
+		ua = strings.Replace(ua, "HeadlessChrome/", "Chrome/", 1)
+	}
+
+	args := emulation.NewSetUserAgentOverrideArgs(ua)
+
+	if profile.Enabled(drivers.StealthLanguages) {
+		args.SetAcceptLanguage(acceptLanguage(stealthLanguages(params)))
+	}
+
+	platform, hints := stealthPlatform(profile.Platform, ua)
+
+	if profile.Platform != "" || profile.Enabled(drivers.StealthUserAgent) {
+		args.SetPlatform(platform)
+	}
+
+	if profile.Enabled(drivers.StealthUserAgent) {
+		mobile := params.Viewport != nil && params.Viewport.Mobile
+
+		if metadata, ok := stealthUserAgentMetadata(ua, hints, mobile); ok {
+			args.SetUserAgentMetadata(metadata)
+		}
+	}
+
+	return client.Emulation.SetUserAgentOverride(ctx, args)
+}
+
+// stealthLanguages returns the configured languages or the ones of the Accept-Language header.
+func stealthLanguages(params drivers.Params) []string {
+	if len(params.Stealth.Languages) > 0 {
+		return params.Stealth.Languages
+	}
+
+	if params.Headers != nil {
+		if languages := parseAcceptLanguage(params.Headers.Data.Get("Accept-Language")); len(languages) > 0 {
+			return languages
+		}
+	}
+
+	return defaultStealthLanguages
+}
+
+// parseAcceptLanguage returns the language tags of an Accept-Language header in their order of preference.
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag    string
+		weight float64
+	}
+
+	values := make([]weighted, 0, 4)
+
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		weight := 1.0
+
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				weight = parsed
+			}
+		}
+
+		if weight <= 0 {
+			continue
+		}
+
+		// keep the header order for equal weights
+		idx := len(values)
+		for idx > 0 && values[idx-1].weight < weight {
+			idx--
+		}
+
+		values = append(values, weighted{})
+		copy(values[idx+1:], values[idx:])
+		values[idx] = weighted{tag: tag, weight: weight}
+	}
+
+	res := make([]string, 0, len(values))
+
+	for _, value := range values {
+		res = append(res, value.tag)
+	}
+
+	return res
+}
+
+// acceptLanguage formats languages as an Accept-Language header with decreasing weights.
+func acceptLanguage(languages []string) string {
+	var b strings.Builder
+
+	for i, lang := range languages {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		b.WriteString(lang)
+
+		if i > 0 {
+			weight := 1 - float64(i)*0.1
+			if weight < 0.1 {
+				weight = 0.1
+			}
+
+			b.WriteString(";q=")
+			b.WriteString(strconv.FormatFloat(weight, 'f', 1, 64))
+		}
+	}
+
+	return b.String()
+}
+
+// stealthPlatform returns the navigator.platform value and the client hints platform name for a user agent.
+func stealthPlatform(configured, ua string) (string, string) {
+	var platform, hints string
+
+	switch {
+	case strings.Contains(ua, "Android"):
+		platform, hints = "Linux armv8l", "Android"
+	case strings.Contains(ua, "iPhone"), strings.Contains(ua, "iPad"):
+		platform, hints = "iPhone", "iOS"
+	case strings.Contains(ua, "Windows"):
+		platform, hints = "Win32", "Windows"
+	case strings.Contains(ua, "Macintosh"), strings.Contains(ua, "Mac OS X"):
+		platform, hints = "MacIntel", "macOS"
+	case strings.Contains(ua, "CrOS"):
+		platform, hints = "Linux x86_64", "Chrome OS"
+	default:
+		platform, hints = "Linux x86_64", "Linux"
+	}
+
+	if configured != "" {
+		platform = configured
+	}
+
+	return platform, hints
+}
+
+// stealthUserAgentMetadata returns client hints matching a Chrome user agent.
+// Other browsers do not send client hints.
+func stealthUserAgentMetadata(ua, platform string, mobile bool) (emulation.UserAgentMetadata, bool) {
+	match := chromeVersionExp.FindStringSubmatch(ua)
+	if match == nil {
+		return emulation.UserAgentMetadata{}, false
+	}
+
+	major := match[1]
+
+	return emulation.UserAgentMetadata{
+		Brands: []emulation.UserAgentBrandVersion{
+			{Brand: "Chromium", Version: major},
+			{Brand: "Google Chrome", Version: major},
+			{Brand: "Not_A Brand", Version: "24"},
+		},
+		Platform: platform,
+		Mobile:   mobile,
+	}, true
+}
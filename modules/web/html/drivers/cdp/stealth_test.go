@@ -0,0 +1,121 @@
+package cdp
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mafredri/cdp"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	got := parseAcceptLanguage("fr;q=0.5, de-DE, *;q=0.1, de;q=0.9, en;q=0")
+
+	if want := []string{"de-DE", "de", "fr"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if got := acceptLanguage([]string{"de-DE", "de", "en"}); got != "de-DE,de;q=0.9,en;q=0.8" {
+		t.Fatalf("unexpected Accept-Language header: %q", got)
+	}
+}
+
+func TestStealthLanguages(t *testing.T) {
+	params := drivers.Params{
+		Stealth: &drivers.Stealth{},
+		Headers: drivers.NewHTTPHeadersWith(map[string][]string{"Accept-Language": {"nl-NL,nl;q=0.8"}}),
+	}
+
+	if got := stealthLanguages(params); !reflect.DeepEqual(got, []string{"nl-NL", "nl"}) {
+		t.Fatalf("expected languages of the Accept-Language header, got %v", got)
+	}
+
+	params.Stealth.Languages = []string{"pt-BR"}
+
+	if got := stealthLanguages(params); !reflect.DeepEqual(got, []string{"pt-BR"}) {
+		t.Fatalf("expected configured languages, got %v", got)
+	}
+
+	if got := stealthLanguages(drivers.Params{Stealth: &drivers.Stealth{}}); !reflect.DeepEqual(got, defaultStealthLanguages) {
+		t.Fatalf("expected default languages, got %v", got)
+	}
+}
+
+func TestSetDefaultParamsStealth(t *testing.T) {
+	drv := New(WithStealth(drivers.Stealth{Platform: "MacIntel"}))
+
+	if params := drv.setDefaultParams(drivers.Params{}); params.Stealth == nil || params.Stealth.Platform != "MacIntel" {
+		t.Fatalf("expected the driver profile, got %#v", params.Stealth)
+	}
+
+	params := drv.setDefaultParams(drivers.Params{Stealth: &drivers.Stealth{Disabled: true}})
+
+	profile, err := drivers.NormalizeStealth(params.Stealth)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if profile != nil {
+		t.Fatalf("expected a page to opt out of the driver profile, got %#v", profile)
+	}
+}
+
+func TestStealthUserAgentMetadata(t *testing.T) {
+	ua := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.6478.126 Safari/537.36"
+
+	platform, hints := stealthPlatform("", ua)
+	if platform != "Win32" || hints != "Windows" {
+		t.Fatalf("unexpected platform: %q / %q", platform, hints)
+	}
+
+	if platform, _ := stealthPlatform("MacIntel", ua); platform != "MacIntel" {
+		t.Fatalf("expected the configured platform, got %q", platform)
+	}
+
+	metadata, ok := stealthUserAgentMetadata(ua, hints, false)
+	if !ok {
+		t.Fatal("expected client hints for a Chrome user agent")
+	}
+
+	for _, brand := range metadata.Brands {
+		if strings.Contains(brand.Brand, "Headless") || (brand.Brand != "Not_A Brand" && brand.Version != "126") {
+			t.Fatalf("unexpected brand: %+v", brand)
+		}
+	}
+
+	if _, ok := stealthUserAgentMetadata("Mozilla/5.0 (X11; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0", "Linux", false); ok {
+		t.Fatal("expected no client hints for Firefox")
+	}
+}
+
+func TestRegisterStealthScript(t *testing.T) {
+	pageAPI := new(initScriptPageAPI)
+	client := &cdp.Client{Page: pageAPI}
+
+	profile, err := drivers.NormalizeStealth(&drivers.Stealth{
+		Languages: []string{"de-DE"},
+		Skip:      []drivers.StealthPatch{drivers.StealthPlugins},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := registerStealthScript(context.Background(), client, drivers.Params{Stealth: profile}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	if len(pageAPI.registered) != 1 {
+		t.Fatalf("expected a single new-document script, got %d", len(pageAPI.registered))
+	}
+
+	script := pageAPI.registered[0]
+
+	for _, expected := range []string{`"languages":["de-DE"]`, `"skip":["plugins"]`, `"webglVendor":"Intel Inc."`} {
+		if !strings.Contains(script, expected) {
+			t.Fatalf("expected the script config to contain %s", expected)
+		}
+	}
+}
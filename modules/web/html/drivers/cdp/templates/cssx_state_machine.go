@@ -52,6 +52,31 @@ const within = (node, selector) => {
 
 	return false;
 };
+const visible = (node) => {
+	if (node == null || node.nodeType !== 1 || !node.isConnected) {
+		return false;
+	}
+
+	const rect = node.getBoundingClientRect();
+
+	if (rect.width <= 0 || rect.height <= 0) {
+		return false;
+	}
+
+	if (typeof node.checkVisibility === "function") {
+		return node.checkVisibility({ opacityProperty: true, visibilityProperty: true });
+	}
+
+	for (let current = node; current != null; current = current.parentElement) {
+		if (window.getComputedStyle(current).opacity === "0") {
+			return false;
+		}
+	}
+
+	const visibility = window.getComputedStyle(node).visibility;
+
+	return visibility !== "hidden" && visibility !== "collapse";
+};
 const textOf = (value) => {
 	if (value == null) {
 		return "";
@@ -124,6 +149,144 @@ const toDate = (value) => {
 	const result = new Date(source);
 	return Number.isNaN(result.getTime()) ? null : result.toISOString();
 };
+const tableChildren = (node, names) => Array.from(node?.children ?? []).filter((child) => names.includes(child.localName));
+const tableSpan = (cell, name) => {
+	const match = /^\s*(\d+)/.exec(cell.getAttribute(name) ?? "");
+	const span = match != null ? Number(match[1]) : 1;
+
+	return Number.isSafeInteger(span) ? span : 1;
+};
+const tableGroup = (rows) => {
+	const grid = rows.map(() => []);
+
+	rows.forEach((row, r) => {
+		let col = 0;
+
+		for (const cell of tableChildren(row, ["td", "th"])) {
+			while (col < grid[r].length && grid[r][col] !== undefined) {
+				col++;
+			}
+
+			const colSpan = Math.min(Math.max(tableSpan(cell, "colspan"), 1), 1000);
+			let rowSpan = Math.min(tableSpan(cell, "rowspan"), 65534);
+			if (rowSpan === 0) {
+				rowSpan = rows.length - r;
+			}
+
+			const text = normalizeSpace(cell.textContent);
+			for (let dr = 0; dr < rowSpan && r + dr < rows.length; dr++) {
+				const target = grid[r + dr];
+				while (target.length < col + colSpan) {
+					target.push(undefined);
+				}
+				for (let dc = 0; dc < colSpan; dc++) {
+					target[col + dc] = text;
+				}
+			}
+
+			col += colSpan;
+		}
+	});
+
+	return grid.filter((row) => row.length > 0);
+};
+const tablePad = (row, width) => Array.from({ length: width }, (_, col) => row[col] ?? null);
+const tableKeys = (header, width) => {
+	const seen = new Map();
+
+	return Array.from({ length: width }, (_, col) => {
+		const parts = [];
+		for (const row of header) {
+			const text = row[col] ?? "";
+			if (text !== "" && parts[parts.length - 1] !== text) {
+				parts.push(text);
+			}
+		}
+
+		let key = parts.join(" ");
+		if (key === "") {
+			key = "column" + (col + 1);
+		}
+
+		const count = (seen.get(key) ?? 0) + 1;
+		seen.set(key, count);
+
+		return count > 1 ? key + "_" + count : key;
+	});
+};
+const table = (node, args) => {
+	if (!isNode(node) || node.localName !== "table") {
+		return null;
+	}
+
+	const head = [];
+	const body = [];
+	const foot = [];
+	let implicit = null;
+
+	for (const child of tableChildren(node, ["thead", "tbody", "tfoot", "tr"])) {
+		switch (child.localName) {
+			case "thead":
+				head.push(...tableChildren(child, ["tr"]));
+				break;
+			case "tfoot":
+				foot.push(...tableChildren(child, ["tr"]));
+				break;
+			case "tbody":
+				body.push(tableChildren(child, ["tr"]));
+				implicit = null;
+				break;
+			default:
+				if (implicit == null) {
+					implicit = [];
+					body.push(implicit);
+				}
+				implicit.push(child);
+		}
+	}
+
+	const headRows = tableGroup(head);
+	let rows = headRows.concat(...body.map(tableGroup), tableGroup(foot));
+	const first = [head, ...body, foot].find((group) => group.length > 0);
+	const cells = first != null ? tableChildren(first[0], ["td", "th"]) : [];
+	let header = 0;
+
+	if (args.length > 0) {
+		const index = Number(args[0]);
+		if (!Number.isInteger(index)) {
+			return null;
+		}
+
+		if (index >= 0) {
+			if (index >= rows.length) {
+				return [];
+			}
+
+			rows = rows.slice(index);
+			header = 1;
+		}
+	} else if (headRows.length > 0) {
+		header = headRows.length;
+	} else if (cells.length > 0 && cells.every((cell) => cell.localName === "th")) {
+		header = 1;
+	}
+
+	const width = rows.reduce((out, row) => Math.max(out, row.length), 0);
+
+	if (header === 0) {
+		return rows.map((row) => tablePad(row, width));
+	}
+
+	const keys = tableKeys(rows.slice(0, header), width);
+
+	return rows.slice(header).map((row) => {
+		const item = {};
+		tablePad(row, width).forEach((value, col) => {
+			item[keys[col]] = value;
+		});
+		return item;
+	});
+};
 const cardinality = (name, args, input) => {
 	const items = toSelection(input);
 
@@ -245,6 +408,10 @@ const traverse = (name, args, input) => {
 	return out;
 };
 const filter = (name, args, input) => {
+	if (name === ":visible" || name === ":hidden") {
+		return toNodes(input).filter((node) => visible(node) === (name === ":visible"));
+	}
+
 	const criterion = String(args[0]);
 	const out = [];
 
@@ -349,6 +516,8 @@ const mapItem = (name, args, input) => {
 			return toNumber(input);
 		case ":toDate":
 			return toDate(input);
+		case ":table":
+			return table(input, args);
 		default:
 			return null;
 	}
@@ -60,6 +60,10 @@ func TestCSSXCompilesAllSupportedSelectors(t *testing.T) {
 		{name: "regex", exp: `:regex("(\\d+)", 1, :text(section))`},
 		{name: "toNumber", exp: `:toNumber(:text(section))`},
 		{name: "toDate", exp: `:toDate("2006-01-02", :text(time))`},
+		{name: "table", exp: `:table(table)`},
+		{name: "table header row", exp: `:table(1, table)`},
+		{name: "visible", exp: `:visible(a)`},
+		{name: "hidden", exp: `:count(:hidden(input))`},
 	}
 
 	for _, tc := range cases {
@@ -216,6 +220,9 @@ func TestCSSXRejectsInvalidArgs(t *testing.T) {
 		`:closest(.card)`,
 		`:replace("\\s+", :text(section))`,
 		`:regex(1, :text(section))`,
+		`:table("thead", table)`,
+		`:table(0, 1, table)`,
+		`:visible("a", a)`,
 	}
 
 	for _, exp := range cases {
@@ -0,0 +1,119 @@
+package templates
+
+import (
+	cdpruntime "github.com/mafredri/cdp/protocol/runtime"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers/cdp/eval"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+const findFormControls = `(root, field) => {
+	const controls = "input, select, textarea";
+	const normalize = (text) => String(text == null ? "" : text).replace(/[\s*:]+$/, "").replace(/\s+/g, " ").trim().toLowerCase();
+	const within = (node) => node != null && node.matches(controls) && (node === root || root.contains(node));
+
+	const byName = Array.from(root.querySelectorAll(controls)).filter((node) => node.getAttribute("name") === field);
+
+	if (byName.length > 0) {
+		return byName;
+	}
+
+	const byId = Array.from(root.querySelectorAll(controls)).filter((node) => node.id === field);
+
+	if (byId.length > 0) {
+		return byId;
+	}
+
+	const label = normalize(field);
+	const byLabel = Array.from(root.querySelectorAll("label"))
+		.filter((node) => normalize(node.textContent) === label)
+		.map((node) => node.control)
+		.filter(within);
+
+	if (byLabel.length > 0) {
+		return byLabel;
+	}
+
+	try {
+		return Array.from(root.querySelectorAll(field)).filter((node) => node.matches(controls));
+	} catch (err) {
+		return [];
+	}
+}`
+
+// FindFormControls returns the form controls addressed by a field name, id, label text or CSS selector, in that order.
+func FindFormControls(id cdpruntime.RemoteObjectID, field runtime.String) *eval.Function {
+	return eval.F(findFormControls).WithArgRef(id).WithArgValue(field)
+}
+
+const getFormControl = `(el) => {
+	const tag = el.nodeName.toLowerCase();
+	const type = tag === "input" ? String(el.type).toLowerCase() : tag;
+	let kind = "text";
+
+	switch (type) {
+		case "checkbox":
+		case "radio":
+		case "select":
+			kind = type;
+			break;
+		case "date":
+		case "datetime-local":
+		case "month":
+		case "week":
+		case "time":
+		case "color":
+		case "range":
+		case "hidden":
+			kind = "value";
+			break;
+	}
+
+	return {
+		kind,
+		value: String(el.value == null ? "" : el.value),
+		checked: el.checked === true
+	};
+}`
+
+// GetFormControl describes how a form control is filled: its kind, current value and checked state.
+func GetFormControl(id cdpruntime.RemoteObjectID) *eval.Function {
+	return eval.F(getFormControl).WithArgRef(id)
+}
+
+const setFormValue = `(el, value) => {
+	const prototype = Object.getPrototypeOf(el);
+	const descriptor = Object.getOwnPropertyDescriptor(prototype, "value");
+
+	if (descriptor != null && typeof descriptor.set === "function") {
+		descriptor.set.call(el, value);
+	} else {
+		el.value = value;
+	}
+
+	el.dispatchEvent(new Event("input", { bubbles: true }));
+	el.dispatchEvent(new Event("change", { bubbles: true }));
+}`
+
+// SetFormValue sets the value of a control through the native setter so that framework bindings see the change.
+func SetFormValue(id cdpruntime.RemoteObjectID, value runtime.String) *eval.Function {
+	return eval.F(setFormValue).WithArgRef(id).WithArgValue(value)
+}
+
+const submitForm = `(root) => {
+	const form = root.nodeName.toLowerCase() === "form" ? root : (root.closest("form") || root.querySelector("form"));
+
+	if (form == null) {
+		throw new Error("form not found");
+	}
+
+	if (typeof form.requestSubmit === "function") {
+		form.requestSubmit();
+	} else {
+		form.submit();
+	}
+}`
+
+func SubmitForm(id cdpruntime.RemoteObjectID) *eval.Function {
+	return eval.F(submitForm).WithArgRef(id)
+}
@@ -0,0 +1,110 @@
+package templates
+
+import (
+	cdpruntime "github.com/mafredri/cdp/protocol/runtime"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers/cdp/eval"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+const observeMutations = `(rootTarget, bindingName, config) => {
+	const registryKey = '__ferretDomMutationObservers';
+	const registry = globalThis[registryKey] || (globalThis[registryKey] = new WeakMap());
+	const target = rootTarget.documentElement != null ? rootTarget.documentElement : rootTarget;
+
+	const serializeNode = (node) => {
+		if (node == null) {
+			return null;
+		}
+
+		const output = {
+			nodeType: node.nodeType,
+			nodeName: node.nodeName,
+			textContent: node.textContent
+		};
+
+		if (node.nodeType === 1) {
+			output.id = node.id;
+			output.className = typeof node.className === 'string' ? node.className : node.getAttribute('class');
+			output.outerHTML = node.outerHTML;
+		}
+
+		return output;
+	};
+
+	const serializeRecord = (record) => {
+		const output = {
+			type: record.type,
+			target: serializeNode(record.target),
+			addedNodes: Array.from(record.addedNodes, serializeNode),
+			removedNodes: Array.from(record.removedNodes, serializeNode)
+		};
+
+		if (record.type === 'attributes') {
+			output.attributeName = record.attributeName;
+			output.oldValue = record.oldValue;
+			output.value = record.target.getAttribute(record.attributeName);
+		}
+
+		return output;
+	};
+
+	const observer = new MutationObserver((records) => {
+		for (const record of records) {
+			globalThis[bindingName](JSON.stringify(serializeRecord(record)));
+		}
+	});
+
+	observer.observe(target, {
+		subtree: config.subtree,
+		childList: config.childList,
+		attributes: config.attributes,
+		attributeOldValue: config.attributes
+	});
+
+	let observers = registry.get(rootTarget);
+
+	if (observers == null) {
+		observers = {};
+		registry.set(rootTarget, observers);
+	}
+
+	observers[bindingName] = observer;
+}`
+
+const disconnectMutations = `(rootTarget, bindingName) => {
+	const registryKey = '__ferretDomMutationObservers';
+	const registry = globalThis[registryKey];
+
+	if (registry == null) {
+		return;
+	}
+
+	const observers = registry.get(rootTarget);
+
+	if (observers == null || observers[bindingName] == null) {
+		return;
+	}
+
+	observers[bindingName].disconnect();
+
+	delete observers[bindingName];
+
+	if (Object.keys(observers).length === 0) {
+		registry.delete(rootTarget);
+	}
+}`
+
+// ObserveMutations attaches a MutationObserver that reports every mutation record through the binding.
+func ObserveMutations(id cdpruntime.RemoteObjectID, bindingName string, config runtime.Map) *eval.Function {
+	return eval.F(observeMutations).
+		WithArgRef(id).
+		WithArg(bindingName).
+		WithArgValue(config)
+}
+
+func DisconnectMutations(id cdpruntime.RemoteObjectID, bindingName string) *eval.Function {
+	return eval.F(disconnectMutations).
+		WithArgRef(id).
+		WithArg(bindingName)
+}
@@ -0,0 +1,203 @@
+package templates
+
+const stealth = `(function (config) {
+	const skipped = new Set(config.skip || []);
+	const enabled = (patch) => !skipped.has(patch);
+
+	// patched functions must keep looking like native code
+	const nativeToString = Function.prototype.toString;
+	const nativeSources = new WeakMap();
+	const toString = new Proxy(nativeToString, {
+		apply(target, self, args) {
+			if (nativeSources.has(self)) {
+				return nativeSources.get(self);
+			}
+
+			return Reflect.apply(target, self, args);
+		},
+	});
+
+	nativeSources.set(toString, nativeToString.call(nativeToString));
+	Function.prototype.toString = toString;
+
+	const native = (fn, name) => {
+		nativeSources.set(fn, "function " + name + "() { [native code] }");
+
+		return fn;
+	};
+
+	const defineGetter = (target, prop, get) => {
+		Object.defineProperty(target, prop, {
+			get: native(get, "get " + prop),
+			configurable: true,
+			enumerable: true,
+		});
+	};
+
+	const defineValues = (target, values) => {
+		Object.keys(values).forEach((key) => {
+			Object.defineProperty(target, key, { value: values[key], enumerable: true });
+		});
+	};
+
+	if (enabled("webdriver")) {
+		defineGetter(Navigator.prototype, "webdriver", () => false);
+	}
+
+	if (enabled("languages") && config.languages.length > 0) {
+		const languages = Object.freeze(config.languages.slice());
+
+		defineGetter(Navigator.prototype, "languages", () => languages);
+		defineGetter(Navigator.prototype, "language", () => languages[0]);
+	}
+
+	if (enabled("plugins") && navigator.plugins.length === 0) {
+		const mimeTypes = [
+			{ type: "application/pdf", suffixes: "pdf", description: "Portable Document Format" },
+			{ type: "text/pdf", suffixes: "pdf", description: "Portable Document Format" },
+		].map((data) => {
+			const mimeType = Object.create(MimeType.prototype);
+
+			defineValues(mimeType, data);
+
+			return mimeType;
+		});
+
+		const namedList = (proto, items, key) => {
+			const list = Object.create(proto);
+
+			items.forEach((item, idx) => {
+				Object.defineProperty(list, idx, { value: item, enumerable: true });
+				Object.defineProperty(list, item[key], { value: item });
+			});
+
+			Object.defineProperty(list, "length", { value: items.length });
+			Object.defineProperty(list, "item", {
+				value: native((idx) => items[idx] || null, "item"),
+			});
+			Object.defineProperty(list, "namedItem", {
+				value: native((name) => items.find((item) => item[key] === name) || null, "namedItem"),
+			});
+
+			return list;
+		};
+
+		const plugins = [
+			"PDF Viewer",
+			"Chrome PDF Viewer",
+			"Chromium PDF Viewer",
+			"Microsoft Edge PDF Viewer",
+			"WebKit built-in PDF",
+		].map((name) => {
+			const plugin = namedList(Plugin.prototype, mimeTypes, "type");
+
+			defineValues(plugin, {
+				name: name,
+				filename: "internal-pdf-viewer",
+				description: "Portable Document Format",
+			});
+
+			return plugin;
+		});
+
+		mimeTypes.forEach((mimeType) => {
+			Object.defineProperty(mimeType, "enabledPlugin", { value: plugins[0], enumerable: true });
+		});
+
+		const pluginArray = namedList(PluginArray.prototype, plugins, "name");
+		const mimeTypeArray = namedList(MimeTypeArray.prototype, mimeTypes, "type");
+
+		Object.defineProperty(pluginArray, "refresh", { value: native(() => undefined, "refresh") });
+
+		defineGetter(Navigator.prototype, "plugins", () => pluginArray);
+		defineGetter(Navigator.prototype, "mimeTypes", () => mimeTypeArray);
+		defineGetter(Navigator.prototype, "pdfViewerEnabled", () => true);
+	}
+
+	if (enabled("webgl")) {
+		const UNMASKED_VENDOR_WEBGL = 0x9245;
+		const UNMASKED_RENDERER_WEBGL = 0x9246;
+
+		[window.WebGLRenderingContext, window.WebGL2RenderingContext].forEach((context) => {
+			if (context == null) {
+				return;
+			}
+
+			const getParameter = context.prototype.getParameter;
+
+			Object.defineProperty(context.prototype, "getParameter", {
+				value: native(function (parameter) {
+					if (parameter === UNMASKED_VENDOR_WEBGL) {
+						return config.webglVendor;
+					}
+
+					if (parameter === UNMASKED_RENDERER_WEBGL) {
+						return config.webglRenderer;
+					}
+
+					return getParameter.call(this, parameter);
+				}, "getParameter"),
+				configurable: true,
+				enumerable: true,
+				writable: true,
+			});
+		});
+	}
+
+	if (enabled("chromeRuntime")) {
+		if (window.chrome == null) {
+			Object.defineProperty(window, "chrome", {
+				value: {},
+				configurable: false,
+				enumerable: true,
+				writable: true,
+			});
+		}
+
+		if (window.chrome.runtime == null) {
+			const noExtension = (name) => native(function () {
+				throw new TypeError("Error in invocation of runtime." + name + "(): chrome.runtime." + name + "() called from a webpage must specify an Extension ID (string) for its first argument.");
+			}, name);
+
+			window.chrome.runtime = {
+				OnInstalledReason: { CHROME_UPDATE: "chrome_update", INSTALL: "install", SHARED_MODULE_UPDATE: "shared_module_update", UPDATE: "update" },
+				OnRestartRequiredReason: { APP_UPDATE: "app_update", OS_UPDATE: "os_update", PERIODIC: "periodic" },
+				PlatformArch: { ARM: "arm", ARM64: "arm64", MIPS: "mips", MIPS64: "mips64", X86_32: "x86-32", X86_64: "x86-64" },
+				PlatformNaclArch: { ARM: "arm", MIPS: "mips", MIPS64: "mips64", X86_32: "x86-32", X86_64: "x86-64" },
+				PlatformOs: { ANDROID: "android", CROS: "cros", LINUX: "linux", MAC: "mac", OPENBSD: "openbsd", WIN: "win" },
+				RequestUpdateCheckStatus: { NO_UPDATE: "no_update", THROTTLED: "throttled", UPDATE_AVAILABLE: "update_available" },
+				connect: noExtension("connect"),
+				sendMessage: noExtension("sendMessage"),
+			};
+		}
+	}
+
+	if (enabled("permissions") && navigator.permissions != null && window.Notification != null) {
+		const query = navigator.permissions.query;
+
+		Object.defineProperty(Permissions.prototype, "query", {
+			value: native(function (descriptor) {
+				if (descriptor != null && descriptor.name === "notifications") {
+					const status = Object.create(PermissionStatus.prototype);
+					const state = Notification.permission === "default" ? "prompt" : Notification.permission;
+
+					defineValues(status, { name: "notifications", state: state });
+					Object.defineProperty(status, "onchange", { value: null, writable: true });
+
+					return Promise.resolve(status);
+				}
+
+				return query.call(this, descriptor);
+			}, "query"),
+			configurable: true,
+			enumerable: true,
+			writable: true,
+		});
+	}
+})`
+
+// Stealth returns the source of a script that patches the browser fingerprint of new documents.
+// The config is a JSON object with the languages, skip, webglVendor and webglRenderer fields.
+func Stealth(config []byte) string {
+	return stealth + "(" + string(config) + ");"
+}
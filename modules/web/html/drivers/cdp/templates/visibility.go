@@ -0,0 +1,43 @@
+package templates
+
+import (
+	cdpruntime "github.com/mafredri/cdp/protocol/runtime"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers/cdp/eval"
+)
+
+const isStyleVisible = `(el) => {
+	if (!el.isConnected) {
+		return false;
+	}
+
+	if (typeof el.checkVisibility === "function") {
+		return el.checkVisibility({ opacityProperty: true, visibilityProperty: true });
+	}
+
+	for (let current = el; current != null; current = current.parentElement) {
+		const styles = window.getComputedStyle(current);
+
+		if (styles.display === "none" || styles.opacity === "0") {
+			return false;
+		}
+	}
+
+	const visibility = window.getComputedStyle(el).visibility;
+
+	return visibility !== "hidden" && visibility !== "collapse";
+}`
+
+// IsStyleVisible reports whether neither the element nor its ancestors are hidden by styles.
+// The geometry of the element is checked separately.
+func IsStyleVisible(id cdpruntime.RemoteObjectID) *eval.Function {
+	return eval.F(isStyleVisible).WithArgRef(id)
+}
+
+const isEnabled = `(el) => {
+	return !el.matches(":disabled");
+}`
+
+func IsEnabled(id cdpruntime.RemoteObjectID) *eval.Function {
+	return eval.F(isEnabled).WithArgRef(id)
+}
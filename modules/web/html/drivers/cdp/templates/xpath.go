@@ -9,14 +9,15 @@ import (
 	"github.com/MontFerret/ferret/v2/pkg/runtime"
 )
 
-const xpath = `(el, expression, resType) => {
+const xpath = `(el, expression, resType, namespaces) => {
 	const unwrap = (item) => {
 		return item.nodeType != 2 ? item : item.nodeValue;
 	};
+	const resolver = namespaces == null ? null : (prefix) => namespaces[prefix] || null;
 	const out = document.evaluate(
 		expression,
 		el,
-		null,
+		resolver,
 		resType == null ? XPathResult.ANY_TYPE : resType
 	);
 	let result;
@@ -93,6 +94,123 @@ func XPath(id cdpruntime.RemoteObjectID, expression runtime.String) *eval.Functi
 	return eval.F(xpath).WithArgRef(id).WithArgValue(expression)
 }
 
+// XPathWithNamespaces evaluates an expression with a namespace resolver for the given prefix bindings.
+func XPathWithNamespaces(id cdpruntime.RemoteObjectID, expression runtime.String, namespaces map[string]string) *eval.Function {
+	return eval.F(xpath).WithArgRef(id).WithArgValue(expression).WithArg(nil).WithArg(namespaces)
+}
+
+const xpathSnapshot = `(el) => {
+	const doc = el.nodeType === 9 ? el : el.ownerDocument;
+	const foreign = {
+		"http://www.w3.org/2000/svg": "svg",
+		"http://www.w3.org/1998/Math/MathML": "math",
+	};
+	const serialize = (node) => {
+		switch (node.nodeType) {
+			case 1: {
+				const out = {
+					t: 1,
+					n: node.prefix ? node.prefix + ":" + node.localName : node.localName,
+					a: [],
+					c: [],
+				};
+
+				if (foreign[node.namespaceURI] != null) {
+					out.ns = foreign[node.namespaceURI];
+				}
+
+				if (node === el) {
+					out.x = true;
+				}
+
+				for (const attr of node.attributes) {
+					out.a.push([attr.name, attr.value]);
+				}
+
+				for (const child of node.childNodes) {
+					const item = serialize(child);
+
+					if (item != null) {
+						out.c.push(item);
+					}
+				}
+
+				return out;
+			}
+			case 3:
+			case 4:
+				return { t: 3, v: node.nodeValue };
+			case 8:
+				return { t: 8, v: node.nodeValue };
+			default:
+				return null;
+		}
+	};
+
+	return JSON.stringify(doc.documentElement != null ? serialize(doc.documentElement) : null);
+}`
+
+// XPathSnapshot returns the element tree of the document owning the element as a JSON string.
+// Elements carry their qualified name, attributes and children, and the element itself is flagged.
+func XPathSnapshot(id cdpruntime.RemoteObjectID) *eval.Function {
+	return eval.F(xpathSnapshot).WithArgRef(id)
+}
+
+const xpathResolve = `(el, items) => {
+	const doc = el.nodeType === 9 ? el : el.ownerDocument;
+
+	return items
+		.map((item) => {
+			switch (item.kind) {
+				case "document":
+					return doc;
+				case "element": {
+					let node = doc.documentElement;
+
+					for (const idx of item.path) {
+						if (node == null) {
+							break;
+						}
+
+						node = node.children[idx];
+					}
+
+					return node;
+				}
+				case "node": {
+					let parent = doc.documentElement;
+
+					for (const idx of item.path) {
+						if (parent == null) {
+							break;
+						}
+
+						parent = parent.children[idx];
+					}
+
+					if (parent == null) {
+						return null;
+					}
+
+					// the snapshot keeps element, text, CDATA and comment nodes only
+					const children = Array.from(parent.childNodes).filter((child) => [1, 3, 4, 8].includes(child.nodeType));
+
+					return children[item.index];
+				}
+				default:
+					return item.value;
+			}
+		})
+		.filter((item) => item != null);
+}`
+
+// XPathResolve maps XPath matches of a document snapshot back to live nodes.
+// Elements are addressed by their element child indexes from the document element,
+// text and comment nodes by the path of their parent element and their index among its serialized child nodes.
+func XPathResolve(id cdpruntime.RemoteObjectID, items any) *eval.Function {
+	return eval.F(xpathResolve).WithArgRef(id).WithArg(items)
+}
+
 const xpathOne = `(el, expression) => {
 	const unwrap = (item) => {
 		return item.nodeType != 2 ? item : item.nodeValue;
@@ -0,0 +1,632 @@
+package drivers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	stdfs "io/fs"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	ferretfs "github.com/MontFerret/ferret/v2/pkg/fs"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+const (
+	// CookieJarFormatNetscape is the tab-separated cookies.txt format used by curl and wget.
+	CookieJarFormatNetscape = "netscape"
+	// CookieJarFormatJSON is a JSON array of cookie objects.
+	CookieJarFormatJSON = "json"
+
+	netscapeCookieHeader   = "# Netscape HTTP Cookie File"
+	netscapeHTTPOnlyPrefix = "#HttpOnly_"
+)
+
+// cookieJarLocks holds a lock per jar file, since every page opens its own jar.
+var cookieJarLocks sync.Map
+
+type (
+	// CookieJar is a cookie file in the Ferret filesystem.
+	// The filesystem is resolved when the jar is opened, so the jar can be saved
+	// after the query context is gone, e.g. when a page is closed.
+	CookieJar struct {
+		reader  ferretfs.Reader
+		writer  ferretfs.Writer
+		path    string
+		format  string
+		cookies []HTTPCookie
+		mu      sync.Mutex
+	}
+
+	cookieJSON struct {
+		Name     string          `json:"name"`
+		Value    string          `json:"value"`
+		Path     string          `json:"path"`
+		Domain   string          `json:"domain"`
+		SameSite string          `json:"sameSite"`
+		Expires  json.RawMessage `json:"expires"`
+		MaxAge   int             `json:"maxAge"`
+		Secure   bool            `json:"secure"`
+		HTTPOnly bool            `json:"httpOnly"`
+	}
+)
+
+// CookieJarFormatOf returns the format of a cookie jar file by its extension.
+// Files with the .json extension hold JSON, any other file is a Netscape cookies.txt file.
+func CookieJarFormatOf(filename string) string {
+	if strings.EqualFold(path.Ext(filename), ".json") {
+		return CookieJarFormatJSON
+	}
+
+	return CookieJarFormatNetscape
+}
+
+// EncodeCookies serializes cookies in the given format.
+// Cookies are ordered by domain, path and name, so that the output is stable.
+func EncodeCookies(cookies []HTTPCookie, format string) ([]byte, error) {
+	sorted := make([]HTTPCookie, len(cookies))
+	copy(sorted, cookies)
+	sortCookies(sorted)
+
+	switch strings.ToLower(format) {
+	case CookieJarFormatNetscape:
+		return encodeNetscapeCookies(sorted), nil
+	case CookieJarFormatJSON:
+		return json.MarshalIndent(sorted, "", "  ")
+	default:
+		return nil, runtime.Errorf(runtime.ErrInvalidArgument, "unknown cookie format %q, expected %q or %q", format, CookieJarFormatNetscape, CookieJarFormatJSON)
+	}
+}
+
+// DecodeCookies parses cookies serialized in the Netscape or JSON format.
+// JSON is detected by a leading array or object, anything else is parsed as a cookies.txt file.
+func DecodeCookies(data []byte) ([]HTTPCookie, error) {
+	trimmed := bytes.TrimSpace(data)
+
+	if len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{') {
+		return decodeJSONCookies(trimmed)
+	}
+
+	return decodeNetscapeCookies(trimmed)
+}
+
+// MatchCookies returns cookies that a browser would send to the given URL.
+// Expired cookies are skipped.
+func MatchCookies(cookies []HTTPCookie, target string) *HTTPCookies {
+	res := NewHTTPCookies()
+
+	u, err := url.Parse(target)
+	if err != nil || u.Hostname() == "" {
+		return res
+	}
+
+	now := time.Now()
+
+	for _, cookie := range cookies {
+		if !cookieMatchesURL(cookie, u, now) {
+			continue
+		}
+
+		// more specific paths come first, keep them
+		if existing, exists := res.Data[cookie.Name]; exists && len(existing.Path) >= len(cookie.Path) {
+			continue
+		}
+
+		res.SetCookie(cookie)
+	}
+
+	return res
+}
+
+// OpenCookieJar reads a cookie jar file from the Ferret filesystem bound to the context.
+// A missing file opens an empty jar that is created on the first save.
+func OpenCookieJar(ctx context.Context, filename string) (*CookieJar, error) {
+	if strings.TrimSpace(filename) == "" {
+		return nil, runtime.Error(runtime.ErrMissedArgument, "cookie jar path")
+	}
+
+	reader, err := ferretfs.ReaderFrom(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("resolve filesystem: %w", err)
+	}
+
+	jar := &CookieJar{
+		reader: reader,
+		path:   filename,
+		format: CookieJarFormatOf(filename),
+	}
+
+	// the jar stays readable in read-only filesystems, saving reports the error instead
+	if writer, err := ferretfs.WriterFrom(ctx); err == nil {
+		jar.writer = writer
+	}
+
+	data, err := reader.ReadFile(filename)
+	if err != nil {
+		if errors.Is(err, stdfs.ErrNotExist) {
+			return jar, nil
+		}
+
+		return nil, fmt.Errorf("read cookie jar %q: %w", filename, err)
+	}
+
+	cookies, err := DecodeCookies(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode cookie jar %q: %w", filename, err)
+	}
+
+	jar.cookies = cookies
+
+	return jar, nil
+}
+
+// Path returns the path of the jar file.
+func (j *CookieJar) Path() string {
+	return j.path
+}
+
+// Cookies returns all cookies stored in the jar.
+func (j *CookieJar) Cookies() []HTTPCookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	res := make([]HTTPCookie, len(j.cookies))
+	copy(res, j.cookies)
+
+	return res
+}
+
+// CookiesFor returns the jar cookies that match the given URL.
+func (j *CookieJar) CookiesFor(target string) *HTTPCookies {
+	return MatchCookies(j.Cookies(), target)
+}
+
+// SetDefaultParams adds the jar cookies matching the page URL to the params.
+// Cookies set explicitly take precedence over the jar ones.
+func (j *CookieJar) SetDefaultParams(params Params) Params {
+	cookies := j.CookiesFor(params.URL)
+
+	if len(cookies.Data) == 0 {
+		return params
+	}
+
+	if params.Cookies == nil {
+		params.Cookies = NewHTTPCookies()
+	}
+
+	for name, cookie := range cookies.Data {
+		if _, exists := params.Cookies.Data[name]; !exists {
+			params.Cookies.Data[name] = cookie
+		}
+	}
+
+	return params
+}
+
+// Save merges cookies received from the given URL into the jar and writes the jar file.
+// Cookies without a domain or path are scoped to the URL, and expired cookies are removed from the jar.
+func (j *CookieJar) Save(target string, cookies *HTTPCookies) error {
+	return j.save(target, cookies, false)
+}
+
+// Sync is like Save, but takes every cookie a browser holds for the given URL:
+// jar cookies sent to the URL that are missing from them were deleted by the page and are removed from the jar.
+func (j *CookieJar) Sync(target string, cookies *HTTPCookies) error {
+	return j.save(target, cookies, true)
+}
+
+// save applies the changes a page made to the cookies it was opened with to the current jar file.
+// Pages of the same jar file are saved one at a time, and cookies a page left unchanged do not overwrite
+// the ones other pages saved in the meantime.
+func (j *CookieJar) save(target string, cookies *HTTPCookies, complete bool) error {
+	lock := cookieJarLock(j.path)
+	lock.Lock()
+	defer lock.Unlock()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.writer == nil {
+		return fmt.Errorf("write cookie jar %q: %w", j.path, runtime.Error(runtime.ErrNotSupported, "filesystem is read-only"))
+	}
+
+	current, err := j.read()
+	if err != nil {
+		return err
+	}
+
+	u, _ := url.Parse(target)
+
+	var host string
+	if u != nil {
+		host = strings.ToLower(u.Hostname())
+	}
+
+	loaded := make(map[string]HTTPCookie, len(j.cookies))
+
+	for _, cookie := range j.cookies {
+		loaded[cookieKey(cookie)] = cookie
+	}
+
+	merged := make(map[string]HTTPCookie, len(current))
+	keys := make([]string, 0, len(current))
+	put := func(cookie HTTPCookie) {
+		key := cookieKey(cookie)
+
+		if _, exists := merged[key]; !exists {
+			keys = append(keys, key)
+		}
+
+		merged[key] = cookie
+	}
+
+	for _, cookie := range current {
+		put(cookie)
+	}
+
+	received := make(map[string]struct{})
+
+	if cookies != nil {
+		for _, cookie := range cookies.Data {
+			if cookie.Domain == "" {
+				cookie.Domain = host
+			}
+
+			if cookie.Path == "" {
+				cookie.Path = "/"
+			}
+
+			key := cookieKey(cookie)
+			received[key] = struct{}{}
+
+			if prev, exists := loaded[key]; exists && sameCookie(prev, cookie) {
+				continue
+			}
+
+			put(cookie)
+		}
+	}
+
+	if complete && u != nil {
+		now := time.Now()
+
+		for key, cookie := range loaded {
+			if _, exists := received[key]; exists || !cookieMatchesURL(cookie, u, now) {
+				continue
+			}
+
+			if stored, exists := merged[key]; exists && sameCookie(stored, cookie) {
+				delete(merged, key)
+			}
+		}
+	}
+
+	now := time.Now()
+	result := make([]HTTPCookie, 0, len(merged))
+
+	for _, key := range keys {
+		if cookie, exists := merged[key]; exists && !isCookieExpired(cookie, now) && cookie.Domain != "" {
+			result = append(result, cookie)
+		}
+	}
+
+	data, err := EncodeCookies(result, j.format)
+	if err != nil {
+		return err
+	}
+
+	if err := j.writer.WriteFile(j.path, data, 0o666); err != nil {
+		return fmt.Errorf("write cookie jar %q: %w", j.path, err)
+	}
+
+	j.cookies = result
+
+	return nil
+}
+
+// read returns the cookies currently stored in the jar file.
+func (j *CookieJar) read() ([]HTTPCookie, error) {
+	data, err := j.reader.ReadFile(j.path)
+	if err != nil {
+		if errors.Is(err, stdfs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("read cookie jar %q: %w", j.path, err)
+	}
+
+	cookies, err := DecodeCookies(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode cookie jar %q: %w", j.path, err)
+	}
+
+	return cookies, nil
+}
+
+// cookieJarLock returns the lock that serializes saves of a jar file across pages.
+func cookieJarLock(filename string) *sync.Mutex {
+	lock, _ := cookieJarLocks.LoadOrStore(filename, new(sync.Mutex))
+
+	return lock.(*sync.Mutex)
+}
+
+func encodeNetscapeCookies(cookies []HTTPCookie) []byte {
+	var b bytes.Buffer
+
+	b.WriteString(netscapeCookieHeader)
+	b.WriteString("\n\n")
+
+	for _, cookie := range cookies {
+		domain := cookie.Domain
+
+		if cookie.HTTPOnly {
+			domain = netscapeHTTPOnlyPrefix + domain
+		}
+
+		cookiePath := cookie.Path
+		if cookiePath == "" {
+			cookiePath = "/"
+		}
+
+		fields := []string{
+			domain,
+			netscapeBool(strings.HasPrefix(cookie.Domain, ".")),
+			cookiePath,
+			netscapeBool(cookie.Secure),
+			strconv.FormatInt(cookieExpiresUnix(cookie), 10),
+			cookie.Name,
+			cookie.Value,
+		}
+
+		b.WriteString(strings.Join(fields, "\t"))
+		b.WriteByte('\n')
+	}
+
+	return b.Bytes()
+}
+
+func decodeNetscapeCookies(data []byte) ([]HTTPCookie, error) {
+	cookies := make([]HTTPCookie, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		httpOnly := false
+
+		if strings.HasPrefix(text, netscapeHTTPOnlyPrefix) {
+			text = strings.TrimPrefix(text, netscapeHTTPOnlyPrefix)
+			httpOnly = true
+		} else if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, "\t")
+		if len(fields) != 7 {
+			return nil, runtime.Errorf(runtime.ErrInvalidArgument, "invalid cookie on line %d: expected 7 tab-separated fields, got %d", line, len(fields))
+		}
+
+		expires, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, runtime.Errorf(runtime.ErrInvalidArgument, "invalid cookie expiration on line %d: %q", line, fields[4])
+		}
+
+		cookie := HTTPCookie{
+			Domain:   fields[0],
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Name:     fields[5],
+			Value:    fields[6],
+			HTTPOnly: httpOnly,
+			SameSite: SameSiteDefaultMode,
+		}
+
+		// subdomain matching is expressed by the leading dot of the domain
+		if strings.EqualFold(fields[1], "TRUE") && !strings.HasPrefix(cookie.Domain, ".") {
+			cookie.Domain = "." + cookie.Domain
+		}
+
+		if expires > 0 {
+			cookie.Expires = time.Unix(expires, 0).UTC()
+		}
+
+		cookies = append(cookies, cookie)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return cookies, nil
+}
+
+func decodeJSONCookies(data []byte) ([]HTTPCookie, error) {
+	var values []cookieJSON
+
+	if data[0] == '{' {
+		values = make([]cookieJSON, 1)
+		err := json.Unmarshal(data, &values[0])
+
+		if err != nil {
+			return nil, runtime.Errorf(runtime.ErrInvalidArgument, "invalid cookie JSON: %s", err)
+		}
+	} else if err := json.Unmarshal(data, &values); err != nil {
+		return nil, runtime.Errorf(runtime.ErrInvalidArgument, "invalid cookie JSON: %s", err)
+	}
+
+	cookies := make([]HTTPCookie, 0, len(values))
+
+	for i, value := range values {
+		if value.Name == "" {
+			return nil, runtime.Errorf(runtime.ErrMissedArgument, "cookie name at index %d", i)
+		}
+
+		expires, err := parseCookieJSONExpires(value.Expires)
+		if err != nil {
+			return nil, runtime.Errorf(runtime.ErrInvalidArgument, "invalid expiration of cookie %q: %s", value.Name, err)
+		}
+
+		cookies = append(cookies, HTTPCookie{
+			Name:     value.Name,
+			Value:    value.Value,
+			Path:     value.Path,
+			Domain:   value.Domain,
+			Expires:  expires,
+			MaxAge:   value.MaxAge,
+			Secure:   value.Secure,
+			HTTPOnly: value.HTTPOnly,
+			SameSite: parseSameSite(value.SameSite),
+		})
+	}
+
+	return cookies, nil
+}
+
+// parseCookieJSONExpires accepts RFC 3339 timestamps and Unix timestamps in seconds.
+func parseCookieJSONExpires(raw json.RawMessage) (time.Time, error) {
+	value := strings.TrimSpace(string(raw))
+
+	if value == "" || value == "null" {
+		return time.Time{}, nil
+	}
+
+	if strings.HasPrefix(value, `"`) {
+		var str string
+
+		if err := json.Unmarshal(raw, &str); err != nil {
+			return time.Time{}, err
+		}
+
+		return time.Parse(time.RFC3339Nano, str)
+	}
+
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if seconds <= 0 {
+		return time.Time{}, nil
+	}
+
+	return time.Unix(int64(seconds), 0).UTC(), nil
+}
+
+func parseSameSite(value string) SameSite {
+	switch strings.ToLower(value) {
+	case "lax":
+		return SameSiteLaxMode
+	case "strict":
+		return SameSiteStrictMode
+	default:
+		return SameSiteDefaultMode
+	}
+}
+
+func netscapeBool(value bool) string {
+	if value {
+		return "TRUE"
+	}
+
+	return "FALSE"
+}
+
+// cookieExpiresUnix returns the expiration time in seconds, or 0 for session cookies.
+func cookieExpiresUnix(cookie HTTPCookie) int64 {
+	if cookie.Expires.IsZero() || cookie.Expires.Unix() <= 0 {
+		return 0
+	}
+
+	return cookie.Expires.Unix()
+}
+
+// cookieMatchesURL reports whether a browser would send the cookie to the URL.
+func cookieMatchesURL(cookie HTTPCookie, u *url.URL, now time.Time) bool {
+	if isCookieExpired(cookie, now) {
+		return false
+	}
+
+	if cookie.Secure && u.Scheme != "https" {
+		return false
+	}
+
+	return cookieDomainMatch(strings.ToLower(u.Hostname()), cookie.Domain) && cookiePathMatch(u.EscapedPath(), cookie.Path)
+}
+
+func sameCookie(a, b HTTPCookie) bool {
+	return a.Value == b.Value &&
+		cookieExpiresUnix(a) == cookieExpiresUnix(b) &&
+		a.MaxAge == b.MaxAge &&
+		a.Secure == b.Secure &&
+		a.HTTPOnly == b.HTTPOnly
+}
+
+func isCookieExpired(cookie HTTPCookie, now time.Time) bool {
+	if cookie.MaxAge < 0 {
+		return true
+	}
+
+	expires := cookieExpiresUnix(cookie)
+
+	return expires > 0 && expires <= now.Unix()
+}
+
+func cookieDomainMatch(host, domain string) bool {
+	if domain == "" {
+		return true
+	}
+
+	domain = strings.ToLower(domain)
+
+	if !strings.HasPrefix(domain, ".") {
+		return host == domain
+	}
+
+	domain = strings.TrimPrefix(domain, ".")
+
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+func cookiePathMatch(requestPath, cookiePath string) bool {
+	if cookiePath == "" || cookiePath == "/" {
+		return true
+	}
+
+	if requestPath == "" {
+		requestPath = "/"
+	}
+
+	if !strings.HasPrefix(requestPath, cookiePath) {
+		return false
+	}
+
+	return len(requestPath) == len(cookiePath) || strings.HasSuffix(cookiePath, "/") || requestPath[len(cookiePath)] == '/'
+}
+
+func cookieKey(cookie HTTPCookie) string {
+	return strings.ToLower(strings.TrimPrefix(cookie.Domain, ".")) + "\t" + cookie.Path + "\t" + cookie.Name
+}
+
+func sortCookies(cookies []HTTPCookie) {
+	sort.SliceStable(cookies, func(i, j int) bool {
+		if cookies[i].Domain != cookies[j].Domain {
+			return cookies[i].Domain < cookies[j].Domain
+		}
+
+		if cookies[i].Path != cookies[j].Path {
+			return cookies[i].Path < cookies[j].Path
+		}
+
+		return cookies[i].Name < cookies[j].Name
+	})
+}
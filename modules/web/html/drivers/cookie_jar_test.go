@@ -0,0 +1,227 @@
+package drivers_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	ferretfs "github.com/MontFerret/ferret/v2/pkg/fs"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+const netscapeJar = `# Netscape HTTP Cookie File
+# https://curl.se/docs/http-cookies.html
+
+.example.com	TRUE	/	FALSE	0	session	abc
+#HttpOnly_example.com	FALSE	/app	TRUE	4102444800	token	xyz
+`
+
+func TestCookieJarFormats(t *testing.T) {
+	Convey("Cookie jar formats", t, func() {
+		Convey("Should decode a Netscape cookies.txt file", func() {
+			cookies, err := drivers.DecodeCookies([]byte(netscapeJar))
+
+			So(err, ShouldBeNil)
+			So(cookies, ShouldHaveLength, 2)
+			So(cookies[0].Domain, ShouldEqual, ".example.com")
+			So(cookies[0].Expires.IsZero(), ShouldBeTrue)
+			So(cookies[0].HTTPOnly, ShouldBeFalse)
+			So(cookies[1].Domain, ShouldEqual, "example.com")
+			So(cookies[1].Path, ShouldEqual, "/app")
+			So(cookies[1].Secure, ShouldBeTrue)
+			So(cookies[1].HTTPOnly, ShouldBeTrue)
+			So(cookies[1].Expires.Unix(), ShouldEqual, 4102444800)
+			So(cookies[1].Value, ShouldEqual, "xyz")
+		})
+
+		Convey("Should round-trip cookies through both formats", func() {
+			cookies, err := drivers.DecodeCookies([]byte(netscapeJar))
+			So(err, ShouldBeNil)
+
+			for _, format := range []string{drivers.CookieJarFormatNetscape, drivers.CookieJarFormatJSON} {
+				out, err := drivers.EncodeCookies(cookies, format)
+				So(err, ShouldBeNil)
+
+				decoded, err := drivers.DecodeCookies(out)
+				So(err, ShouldBeNil)
+				So(decoded, ShouldHaveLength, 2)
+				So(decoded[0].Name, ShouldEqual, "session")
+				So(decoded[1].HTTPOnly, ShouldBeTrue)
+				So(decoded[1].Expires.Unix(), ShouldEqual, 4102444800)
+			}
+		})
+
+		Convey("Should write Netscape lines in curl layout", func() {
+			out, err := drivers.EncodeCookies([]drivers.HTTPCookie{{
+				Name:     "token",
+				Value:    "xyz",
+				Domain:   ".example.com",
+				HTTPOnly: true,
+			}}, drivers.CookieJarFormatNetscape)
+
+			So(err, ShouldBeNil)
+			So(string(out), ShouldContainSubstring, "#HttpOnly_.example.com\tTRUE\t/\tFALSE\t0\ttoken\txyz\n")
+		})
+
+		Convey("Should decode a single JSON cookie object with a Unix expiration", func() {
+			cookies, err := drivers.DecodeCookies([]byte(`{"name":"a","value":"1","domain":"example.com","expires":4102444800,"sameSite":"strict"}`))
+
+			So(err, ShouldBeNil)
+			So(cookies, ShouldHaveLength, 1)
+			So(cookies[0].SameSite, ShouldEqual, drivers.SameSiteStrictMode)
+			So(cookies[0].Expires.Unix(), ShouldEqual, 4102444800)
+		})
+
+		Convey("Should reject malformed input", func() {
+			_, err := drivers.DecodeCookies([]byte("example.com\tTRUE\t/\n"))
+			So(errors.Is(err, runtime.ErrInvalidArgument), ShouldBeTrue)
+
+			_, err = drivers.EncodeCookies(nil, "yaml")
+			So(errors.Is(err, runtime.ErrInvalidArgument), ShouldBeTrue)
+		})
+
+		Convey("Should match cookies by domain, path and scheme", func() {
+			cookies, err := drivers.DecodeCookies([]byte(netscapeJar))
+			So(err, ShouldBeNil)
+
+			cookies = append(cookies, drivers.HTTPCookie{
+				Name:    "expired",
+				Domain:  "example.com",
+				Expires: time.Now().Add(-time.Hour),
+			})
+
+			So(drivers.MatchCookies(cookies, "https://example.com/app/page").Data, ShouldContainKey, "token")
+			So(drivers.MatchCookies(cookies, "https://example.com/app/page").Data, ShouldNotContainKey, "expired")
+			So(drivers.MatchCookies(cookies, "http://example.com/app").Data, ShouldNotContainKey, "token")
+			So(drivers.MatchCookies(cookies, "https://example.com/application").Data, ShouldNotContainKey, "token")
+			So(drivers.MatchCookies(cookies, "https://www.example.com/").Data, ShouldContainKey, "session")
+			So(drivers.MatchCookies(cookies, "https://www.example.com/app").Data, ShouldNotContainKey, "token")
+		})
+	})
+}
+
+func TestCookieJar(t *testing.T) {
+	Convey("CookieJar", t, func() {
+		root := t.TempDir()
+		filesystem, err := ferretfs.New(ferretfs.WithRoot(root))
+		So(err, ShouldBeNil)
+
+		ctx := ferretfs.WithFileSystem(context.Background(), filesystem)
+
+		Convey("Should open a missing file as an empty jar", func() {
+			jar, err := drivers.OpenCookieJar(ctx, "cookies.txt")
+
+			So(err, ShouldBeNil)
+			So(jar.Cookies(), ShouldBeEmpty)
+		})
+
+		Convey("Should add jar cookies to page params without overriding explicit ones", func() {
+			So(os.WriteFile(filepath.Join(root, "cookies.txt"), []byte(netscapeJar), 0o666), ShouldBeNil)
+
+			jar, err := drivers.OpenCookieJar(ctx, "cookies.txt")
+			So(err, ShouldBeNil)
+
+			explicit := drivers.NewHTTPCookies()
+			explicit.SetCookie(drivers.HTTPCookie{Name: "session", Value: "explicit"})
+
+			params := jar.SetDefaultParams(drivers.Params{URL: "https://example.com/app", Cookies: explicit})
+
+			So(params.Cookies.Data["session"].Value, ShouldEqual, "explicit")
+			So(params.Cookies.Data["token"].Value, ShouldEqual, "xyz")
+		})
+
+		Convey("Should merge page cookies into the jar file on save", func() {
+			So(os.WriteFile(filepath.Join(root, "cookies.txt"), []byte(netscapeJar), 0o666), ShouldBeNil)
+
+			jar, err := drivers.OpenCookieJar(ctx, "cookies.txt")
+			So(err, ShouldBeNil)
+
+			cookies := drivers.NewHTTPCookies()
+			cookies.SetCookie(drivers.HTTPCookie{Name: "session", Value: "updated", Domain: ".example.com", Path: "/"})
+			cookies.SetCookie(drivers.HTTPCookie{Name: "fresh", Value: "1"})
+
+			So(jar.Save("https://example.com/", cookies), ShouldBeNil)
+
+			data, err := os.ReadFile(filepath.Join(root, "cookies.txt"))
+			So(err, ShouldBeNil)
+
+			content := string(data)
+			So(content, ShouldStartWith, "# Netscape HTTP Cookie File")
+			So(content, ShouldContainSubstring, ".example.com\tTRUE\t/\tFALSE\t0\tsession\tupdated\n")
+			So(content, ShouldContainSubstring, "example.com\tFALSE\t/\tFALSE\t0\tfresh\t1\n")
+			So(content, ShouldContainSubstring, "#HttpOnly_example.com\tFALSE\t/app\tTRUE\t4102444800\ttoken\txyz\n")
+			So(strings.Count(content, "session"), ShouldEqual, 1)
+		})
+
+		Convey("Should merge saves of jars opened before each other's saves", func() {
+			So(os.WriteFile(filepath.Join(root, "cookies.txt"), []byte(netscapeJar), 0o666), ShouldBeNil)
+
+			first, err := drivers.OpenCookieJar(ctx, "cookies.txt")
+			So(err, ShouldBeNil)
+
+			second, err := drivers.OpenCookieJar(ctx, "cookies.txt")
+			So(err, ShouldBeNil)
+
+			cookies := drivers.NewHTTPCookies()
+			cookies.SetCookie(drivers.HTTPCookie{Name: "a", Value: "1"})
+			So(first.Save("https://example.com/", cookies), ShouldBeNil)
+
+			// the second page still holds the session cookie it was opened with
+			cookies = drivers.NewHTTPCookies()
+			cookies.SetCookie(drivers.HTTPCookie{Name: "b", Value: "2"})
+			cookies.SetCookie(drivers.HTTPCookie{Name: "session", Value: "abc", Domain: ".example.com", Path: "/"})
+			So(second.Save("https://example.com/", cookies), ShouldBeNil)
+
+			reopened, err := drivers.OpenCookieJar(ctx, "cookies.txt")
+			So(err, ShouldBeNil)
+
+			saved := reopened.CookiesFor("https://example.com/app")
+			So(saved.Data, ShouldContainKey, "a")
+			So(saved.Data, ShouldContainKey, "b")
+			So(saved.Data, ShouldContainKey, "token")
+		})
+
+		Convey("Should remove deleted and expired cookies", func() {
+			So(os.WriteFile(filepath.Join(root, "cookies.txt"), []byte(netscapeJar), 0o666), ShouldBeNil)
+
+			jar, err := drivers.OpenCookieJar(ctx, "cookies.txt")
+			So(err, ShouldBeNil)
+
+			// the browser no longer holds the token cookie sent to /app
+			cookies := drivers.NewHTTPCookies()
+			cookies.SetCookie(drivers.HTTPCookie{Name: "session", Value: "abc", Domain: ".example.com", Path: "/"})
+			So(jar.Sync("https://example.com/app", cookies), ShouldBeNil)
+			So(jar.CookiesFor("https://example.com/app").Data, ShouldNotContainKey, "token")
+
+			cookies = drivers.NewHTTPCookies()
+			cookies.SetCookie(drivers.HTTPCookie{Name: "session", Domain: ".example.com", Path: "/", MaxAge: -1})
+			So(jar.Save("https://example.com/", cookies), ShouldBeNil)
+			So(jar.Cookies(), ShouldBeEmpty)
+		})
+
+		Convey("Should persist .json jars as JSON", func() {
+			jar, err := drivers.OpenCookieJar(ctx, "cookies.json")
+			So(err, ShouldBeNil)
+
+			cookies := drivers.NewHTTPCookies()
+			cookies.SetCookie(drivers.HTTPCookie{Name: "a", Value: "1"})
+
+			So(jar.Save("https://example.com/", cookies), ShouldBeNil)
+
+			data, err := os.ReadFile(filepath.Join(root, "cookies.json"))
+			So(err, ShouldBeNil)
+			So(string(data), ShouldStartWith, "[")
+
+			reopened, err := drivers.OpenCookieJar(ctx, "cookies.json")
+			So(err, ShouldBeNil)
+			So(reopened.CookiesFor("https://example.com/").Data, ShouldContainKey, "a")
+		})
+	})
+}
@@ -12,7 +12,7 @@ import (
 	"github.com/MontFerret/ferret/v2/pkg/runtime"
 )
 
-const cssxContractMarkup = `<html><body><section class="card" data-role="hero"><h1>Hero</h1><a href="/hero">Read</a></section><section class="card" data-role="related"><h2>Other</h2><span class="price">$1,234.50</span></section></body></html>`
+const cssxContractMarkup = `<html><body><section class="card" data-role="hero"><h1>Hero</h1><a href="/hero">Read</a></section><section class="card" data-role="related"><h2>Other</h2><span class="price">$1,234.50</span></section><table class="specs"><thead><tr><th>Name</th><th>Value</th></tr></thead><tbody><tr><td>Weight</td><td>1 kg</td></tr></tbody></table></body></html>`
 
 func TestCSSXContractAcrossBackends(t *testing.T) {
 	t.Parallel()
@@ -78,6 +78,18 @@ func TestCSSXContractAcrossBackends(t *testing.T) {
 				assertListValues(t, ctx, list, []runtime.Value{runtime.NewFloat(1234.5)})
 			},
 		},
+		{
+			name: "specs table",
+			exp:  `:table(.specs)`,
+			assert: func(t *testing.T, list runtime.List) {
+				assertListValues(t, ctx, list, []runtime.Value{
+					runtime.NewArrayWith(runtime.NewObjectWith(map[string]runtime.Value{
+						"Name":  runtime.NewString("Weight"),
+						"Value": runtime.NewString("1 kg"),
+					})),
+				})
+			},
+		},
 	}
 
 	for _, tc := range cases {
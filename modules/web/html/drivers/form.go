@@ -0,0 +1,142 @@
+package drivers
+
+import (
+	"context"
+	"sort"
+
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+type (
+	// FormField is a value addressed to form controls by name, id, label text or CSS selector.
+	FormField struct {
+		Value runtime.Value
+		Name  runtime.String
+	}
+
+	// FormFillOptions defines how a form is filled.
+	FormFillOptions struct {
+		// Submit submits the form after all fields are filled.
+		Submit runtime.Boolean `json:"submit"`
+		// Delay is the typing delay in milliseconds for text controls.
+		Delay runtime.Int `json:"delay"`
+	}
+
+	// FormFillResult reports which fields were matched to form controls.
+	FormFillResult struct {
+		Filled    []runtime.String
+		Unmatched []runtime.String
+	}
+)
+
+// NewFormFields converts a map of field names to values into form fields ordered by name.
+// Use NewOrderedFormFields when the fill order matters.
+func NewFormFields(ctx context.Context, values runtime.Map) ([]FormField, error) {
+	fields := make([]FormField, 0)
+
+	err := values.ForEach(ctx, func(_ context.Context, value, key runtime.Value) (runtime.Boolean, error) {
+		fields = append(fields, FormField{Name: runtime.NewString(key.String()), Value: value})
+
+		return true, nil
+	})
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Name < fields[j].Name
+	})
+
+	return fields, err
+}
+
+// NewOrderedFormFields converts a list of {name, value} objects into form fields that keep the list order,
+// so controls that depend on each other, like chained selects, are filled in sequence.
+func NewOrderedFormFields(ctx context.Context, values runtime.List) ([]FormField, error) {
+	fields := make([]FormField, 0)
+
+	err := values.ForEach(ctx, func(ctx context.Context, item runtime.Value, idx runtime.Int) (runtime.Boolean, error) {
+		entry, err := runtime.CastMap(item)
+		if err != nil {
+			return false, runtime.Errorf(runtime.ErrInvalidArgument, "field %d: %s", idx, err)
+		}
+
+		name, err := entry.Get(ctx, runtime.NewString("name"))
+		if err != nil {
+			return false, err
+		}
+
+		if name == runtime.None || name.String() == "" {
+			return false, runtime.Errorf(runtime.ErrInvalidArgument, "field %d: name is required", idx)
+		}
+
+		value, err := entry.Get(ctx, runtime.NewString("value"))
+		if err != nil {
+			return false, err
+		}
+
+		fields = append(fields, FormField{Name: runtime.NewString(name.String()), Value: value})
+
+		return true, nil
+	})
+
+	return fields, err
+}
+
+// FormValueStrings returns the string values of a field value.
+// Lists yield one value per item, NONE yields no values and any other value yields its string form.
+func FormValueStrings(ctx context.Context, value runtime.Value) ([]string, error) {
+	if value == nil || value == runtime.None {
+		return nil, nil
+	}
+
+	list, ok := value.(runtime.List)
+	if !ok {
+		return []string{value.String()}, nil
+	}
+
+	out := make([]string, 0)
+
+	err := list.ForEach(ctx, func(_ context.Context, item runtime.Value, _ runtime.Int) (runtime.Boolean, error) {
+		out = append(out, item.String())
+
+		return true, nil
+	})
+
+	return out, err
+}
+
+// FormChecked reports whether a checkbox or radio button with the given value should be checked.
+// Boolean field values set the state directly, other values check the controls whose value they contain.
+func FormChecked(ctx context.Context, field runtime.Value, controlValue string) (bool, error) {
+	if flag, ok := field.(runtime.Boolean); ok {
+		return bool(flag), nil
+	}
+
+	values, err := FormValueStrings(ctx, field)
+	if err != nil {
+		return false, err
+	}
+
+	for _, value := range values {
+		if value == controlValue {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r FormFillResult) ToValue() runtime.Value {
+	toArray := func(items []runtime.String) *runtime.Array {
+		arr := runtime.NewArray(len(items))
+
+		for _, item := range items {
+			_ = arr.Append(context.Background(), item)
+		}
+
+		return arr
+	}
+
+	return runtime.NewObjectWith(map[string]runtime.Value{
+		"filled":    toArray(r.Filled),
+		"unmatched": toArray(r.Unmatched),
+	})
+}
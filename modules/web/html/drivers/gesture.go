@@ -0,0 +1,39 @@
+package drivers
+
+import "github.com/MontFerret/ferret/v2/pkg/runtime"
+
+const (
+	DefaultGestureSteps = 10
+	DefaultPinchScale   = 2
+)
+
+type (
+	// GesturePoint addresses a gesture either by an element selector or by viewport coordinates.
+	// Selected elements are scrolled into view and addressed by their center point.
+	GesturePoint struct {
+		Selector *QuerySelector
+		X        runtime.Float
+		Y        runtime.Float
+	}
+
+	// GestureOptions defines how pointer movement of a drag or swipe is interpolated.
+	GestureOptions struct {
+		Steps runtime.Int `json:"steps"`
+	}
+
+	// PinchOptions defines a two-finger pinch. A scale below 1 pinches in and a scale above 1 spreads out.
+	PinchOptions struct {
+		Scale runtime.Float `json:"scale"`
+		Steps runtime.Int   `json:"steps"`
+	}
+)
+
+// NewGesturePointBySelector returns a gesture point addressing the center of a selected element.
+func NewGesturePointBySelector(selector QuerySelector) GesturePoint {
+	return GesturePoint{Selector: &selector}
+}
+
+// NewGesturePointByXY returns a gesture point addressing viewport coordinates.
+func NewGesturePointByXY(x, y runtime.Float) GesturePoint {
+	return GesturePoint{X: x, Y: y}
+}
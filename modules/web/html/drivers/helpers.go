@@ -54,6 +54,17 @@ func ToQueryTarget(value runtime.Value) (QueryTarget, error) {
 	}
 }
 
+func ToXPathTarget(value runtime.Value) (XPathTarget, error) {
+	switch v := value.(type) {
+	case HTMLPage:
+		return asCapability[XPathTarget](v.GetMainFrame(), "xpath", nil)
+	case HTMLDocument, HTMLElement:
+		return asCapability[XPathTarget](v, "xpath", nil)
+	default:
+		return nil, runtime.TypeErrorOf(value, HTMLPageType, HTMLDocumentType, HTMLElementType)
+	}
+}
+
 func ToContentTarget(value runtime.Value) (ContentTarget, error) {
 	return toHTMLCapability(value, "content", func(value any) (ContentTarget, bool) {
 		provider, ok := value.(contentTargetProvider)
@@ -131,10 +142,22 @@ func ToWaitTarget(value runtime.Value) (WaitTarget, error) {
 	})
 }
 
+func ToFormTarget(value runtime.Value) (FormTarget, error) {
+	return toHTMLCapability[FormTarget](value, "form", nil)
+}
+
+func ToGeometryTarget(value runtime.Value) (GeometryTarget, error) {
+	return toHTMLCapability[GeometryTarget](value, "geometry", nil)
+}
+
 func ToDocumentViewportTarget(value runtime.Value) (DocumentViewportTarget, error) {
 	return toDocumentCapability[DocumentViewportTarget](value, "document viewport")
 }
 
+func ToDocumentGestureTarget(value runtime.Value) (DocumentGestureTarget, error) {
+	return toDocumentCapability[DocumentGestureTarget](value, "document gesture")
+}
+
 func ToDocumentURLTarget(value runtime.Value) (DocumentURLTarget, error) {
 	return toDocumentCapability[DocumentURLTarget](value, "document URL")
 }
@@ -155,6 +178,10 @@ func ToPageSnapshotTarget(value runtime.Value) (PageSnapshotTarget, error) {
 	return toPageCapability[PageSnapshotTarget](value, "page snapshot")
 }
 
+func ToPageArchiveTarget(value runtime.Value) (PageArchiveTarget, error) {
+	return toPageCapability[PageArchiveTarget](value, "page archive")
+}
+
 func ToPageNavigationTarget(value runtime.Value) (PageNavigationTarget, error) {
 	return toPageCapability[PageNavigationTarget](value, "page navigation")
 }
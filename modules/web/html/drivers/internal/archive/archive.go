@@ -0,0 +1,429 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"mime"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// MaxFrameDepth limits how deep nested frames are archived.
+const MaxFrameDepth = 5
+
+type (
+	// Resource is a fetched document, stylesheet, image or frame.
+	Resource struct {
+		URL         string
+		ContentType string
+		Body        []byte
+	}
+
+	// Fetcher loads the resource of an absolute URL.
+	Fetcher func(ctx context.Context, url string) (*Resource, error)
+
+	archiver struct {
+		fetch     Fetcher
+		seen      map[string]bool
+		resources []*Resource
+		inline    bool
+	}
+)
+
+// HTML renders the document with its stylesheets, images and frames inlined.
+// Stylesheets become style elements, images and CSS assets become data URIs and frames are archived
+// recursively into srcdoc (iframe) or data URI (frame) sources.
+// Resources that cannot be fetched keep an absolute reference to their origin.
+func HTML(ctx context.Context, doc *html.Node, baseURL string, fetch Fetcher) ([]byte, error) {
+	a := &archiver{fetch: fetch, inline: true}
+
+	return a.document(ctx, doc, baseURL, 0)
+}
+
+// MHTML renders the document as a multipart/related archive holding the page and every fetched
+// stylesheet, image and frame, addressed by their absolute URLs.
+func MHTML(ctx context.Context, doc *html.Node, baseURL string, fetch Fetcher) ([]byte, error) {
+	a := &archiver{fetch: fetch, seen: make(map[string]bool)}
+
+	content, err := a.document(ctx, doc, baseURL, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+
+	main := &Resource{URL: baseURL, ContentType: "text/html", Body: content}
+
+	if err := WriteMHTML(&out, main, a.resources); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+func (a *archiver) document(ctx context.Context, doc *html.Node, baseURL string, depth int) ([]byte, error) {
+	base, found := documentBase(doc, baseURL)
+
+	if err := a.node(ctx, doc, base, depth); err != nil {
+		return nil, err
+	}
+
+	// links left in an inlined document keep resolving against its origin
+	if a.inline && !found && base.IsAbs() {
+		insertBase(doc, base.String())
+	}
+
+	var out bytes.Buffer
+
+	if err := html.Render(&out, doc); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+func (a *archiver) node(ctx context.Context, node *html.Node, base *url.URL, depth int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if node.Type == html.ElementNode {
+		if err := a.element(ctx, node, base, depth); err != nil {
+			return err
+		}
+	}
+
+	for child := node.FirstChild; child != nil; {
+		// the element may be replaced while it is archived
+		next := child.NextSibling
+
+		if err := a.node(ctx, child, base, depth); err != nil {
+			return err
+		}
+
+		child = next
+	}
+
+	return nil
+}
+
+func (a *archiver) element(ctx context.Context, node *html.Node, base *url.URL, depth int) error {
+	if style, ok := attr(node, "style"); ok {
+		css, err := a.css(ctx, style, base, depth)
+		if err != nil {
+			return err
+		}
+
+		setAttr(node, "style", css)
+	}
+
+	switch node.DataAtom {
+	case atom.Link:
+		rel, _ := attr(node, "rel")
+
+		if hasToken(rel, "stylesheet") {
+			return a.stylesheet(ctx, node, base, depth)
+		}
+	case atom.Style:
+		if node.FirstChild != nil && node.FirstChild.Type == html.TextNode {
+			css, err := a.css(ctx, node.FirstChild.Data, base, depth)
+			if err != nil {
+				return err
+			}
+
+			node.FirstChild.Data = css
+		}
+	case atom.Img:
+		return a.image(ctx, node, base)
+	case atom.Iframe, atom.Frame:
+		return a.frame(ctx, node, base, depth)
+	}
+
+	return nil
+}
+
+func (a *archiver) stylesheet(ctx context.Context, node *html.Node, base *url.URL, depth int) error {
+	href, ok := attr(node, "href")
+	if !ok {
+		return nil
+	}
+
+	target := resolve(base, href)
+	res, err := a.load(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	if res == nil {
+		setAttr(node, "href", target)
+
+		return nil
+	}
+
+	cssBase, _ := url.Parse(res.URL)
+	css, err := a.css(ctx, string(res.Body), cssBase, depth)
+	if err != nil {
+		return err
+	}
+
+	if !a.inline {
+		setAttr(node, "href", target)
+		a.add(&Resource{URL: target, ContentType: "text/css", Body: []byte(css)})
+
+		return nil
+	}
+
+	style := &html.Node{Type: html.ElementNode, DataAtom: atom.Style, Data: "style"}
+
+	if media, ok := attr(node, "media"); ok {
+		setAttr(style, "media", media)
+	}
+
+	style.AppendChild(&html.Node{Type: html.TextNode, Data: css})
+	node.Parent.InsertBefore(style, node)
+	node.Parent.RemoveChild(node)
+
+	return nil
+}
+
+func (a *archiver) image(ctx context.Context, node *html.Node, base *url.URL) error {
+	// the archived src is the only candidate that stays available offline
+	removeAttr(node, "srcset")
+	removeAttr(node, "sizes")
+
+	src, ok := attr(node, "src")
+	if !ok {
+		return nil
+	}
+
+	ref, err := a.asset(ctx, resolve(base, src))
+	if err != nil {
+		return err
+	}
+
+	setAttr(node, "src", ref)
+
+	return nil
+}
+
+func (a *archiver) frame(ctx context.Context, node *html.Node, base *url.URL, depth int) error {
+	src, ok := attr(node, "src")
+	if !ok || depth >= MaxFrameDepth {
+		return nil
+	}
+
+	target := resolve(base, src)
+	res, err := a.load(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	if res == nil {
+		setAttr(node, "src", target)
+
+		return nil
+	}
+
+	doc, err := html.Parse(bytes.NewReader(res.Body))
+	if err != nil {
+		setAttr(node, "src", target)
+
+		return nil
+	}
+
+	content, err := a.document(ctx, doc, res.URL, depth+1)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !a.inline:
+		setAttr(node, "src", target)
+		a.add(&Resource{URL: target, ContentType: "text/html", Body: content})
+	case node.DataAtom == atom.Iframe:
+		removeAttr(node, "src")
+		setAttr(node, "srcdoc", string(content))
+	default:
+		setAttr(node, "src", dataURI("text/html", content))
+	}
+
+	return nil
+}
+
+// asset returns the reference an image or a CSS asset is archived under.
+func (a *archiver) asset(ctx context.Context, target string) (string, error) {
+	res, err := a.load(ctx, target)
+	if err != nil || res == nil {
+		return target, err
+	}
+
+	if !a.inline {
+		a.add(&Resource{URL: target, ContentType: res.ContentType, Body: res.Body})
+
+		return target, nil
+	}
+
+	return dataURI(res.ContentType, res.Body), nil
+}
+
+// load fetches a resource. Failed fetches are reported as a nil resource and only context errors are returned.
+// The URL of the returned resource is the one its relative references resolve against.
+func (a *archiver) load(ctx context.Context, target string) (*Resource, error) {
+	if !archivable(target) {
+		return nil, nil
+	}
+
+	res, err := a.fetch(ctx, target)
+	if err != nil || res == nil {
+		return nil, ctx.Err()
+	}
+
+	if res.URL == "" {
+		res.URL = target
+	}
+
+	return res, nil
+}
+
+func (a *archiver) add(res *Resource) {
+	if a.seen[res.URL] {
+		return
+	}
+
+	a.seen[res.URL] = true
+	a.resources = append(a.resources, res)
+}
+
+func documentBase(doc *html.Node, docURL string) (*url.URL, bool) {
+	base, err := url.Parse(docURL)
+	if err != nil {
+		base = &url.URL{}
+	}
+
+	node := findElement(doc, atom.Base, func(node *html.Node) bool {
+		_, ok := attr(node, "href")
+
+		return ok
+	})
+
+	if node == nil {
+		return base, false
+	}
+
+	href, _ := attr(node, "href")
+
+	if resolved, err := base.Parse(strings.TrimSpace(href)); err == nil {
+		return resolved, true
+	}
+
+	return base, true
+}
+
+func insertBase(doc *html.Node, href string) {
+	head := findElement(doc, atom.Head, nil)
+	if head == nil {
+		return
+	}
+
+	base := &html.Node{Type: html.ElementNode, DataAtom: atom.Base, Data: "base"}
+	setAttr(base, "href", href)
+	head.InsertBefore(base, head.FirstChild)
+}
+
+func findElement(node *html.Node, name atom.Atom, match func(*html.Node) bool) *html.Node {
+	if node.Type == html.ElementNode && node.DataAtom == name && (match == nil || match(node)) {
+		return node
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if found := findElement(child, name, match); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+func resolve(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+
+	resolved, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+
+	return resolved.String()
+}
+
+func archivable(target string) bool {
+	u, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "", "data", "javascript", "about", "blob", "mailto":
+		return false
+	default:
+		return true
+	}
+}
+
+func dataURI(contentType string, body []byte) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "" {
+		mediaType = "application/octet-stream"
+	}
+
+	if charset, ok := params["charset"]; ok {
+		mediaType += ";charset=" + charset
+	}
+
+	return "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(body)
+}
+
+func hasToken(value, token string) bool {
+	for _, field := range strings.Fields(value) {
+		if strings.EqualFold(field, token) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func attr(node *html.Node, name string) (string, bool) {
+	for _, a := range node.Attr {
+		if a.Namespace == "" && a.Key == name {
+			return a.Val, true
+		}
+	}
+
+	return "", false
+}
+
+func setAttr(node *html.Node, name, value string) {
+	for i, a := range node.Attr {
+		if a.Namespace == "" && a.Key == name {
+			node.Attr[i].Val = value
+
+			return
+		}
+	}
+
+	node.Attr = append(node.Attr, html.Attribute{Key: name, Val: value})
+}
+
+func removeAttr(node *html.Node, name string) {
+	for i, a := range node.Attr {
+		if a.Namespace == "" && a.Key == name {
+			node.Attr = append(node.Attr[:i], node.Attr[i+1:]...)
+
+			return
+		}
+	}
+}
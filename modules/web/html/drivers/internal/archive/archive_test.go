@@ -0,0 +1,178 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+const archivePage = `<html><head><link rel="stylesheet" href="/css/site.css" media="screen"></head>
+<body style="background: url('img/bg.png')">
+<img src="img/logo.png" srcset="img/logo@2x.png 2x" alt="logo">
+<img src="/missing.png">
+<iframe src="/frame.html"></iframe>
+<a href="/pricing">Pricing</a>
+</body></html>`
+
+var archiveResources = map[string]*Resource{
+	"https://example.com/css/site.css":  {ContentType: "text/css", Body: []byte(`@import "fonts.css"; h1 { background: url(../img/h1.png) }`)},
+	"https://example.com/css/fonts.css": {ContentType: "text/css", Body: []byte(`@font-face { src: url("font.woff") }`)},
+	"https://example.com/css/font.woff": {ContentType: "font/woff", Body: []byte("woff")},
+	"https://example.com/img/h1.png":    {ContentType: "image/png", Body: []byte("h1")},
+	"https://example.com/img/bg.png":    {ContentType: "image/png", Body: []byte("bg")},
+	"https://example.com/img/logo.png":  {ContentType: "image/png", Body: []byte("logo")},
+	"https://example.com/frame.html":    {ContentType: "text/html", Body: []byte(`<html><body><img src="inner.png"></body></html>`)},
+	"https://example.com/inner.png":     {ContentType: "image/png", Body: []byte("inner")},
+}
+
+func fetchArchiveResource(_ context.Context, url string) (*Resource, error) {
+	res, ok := archiveResources[url]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+
+	return &Resource{URL: url, ContentType: res.ContentType, Body: res.Body}, nil
+}
+
+func mustParse(t *testing.T, markup string) *html.Node {
+	t.Helper()
+
+	doc, err := html.Parse(strings.NewReader(markup))
+	if err != nil {
+		t.Fatalf("parse html: %v", err)
+	}
+
+	return doc
+}
+
+func TestHTMLInlinesResources(t *testing.T) {
+	t.Parallel()
+
+	out, err := HTML(context.Background(), mustParse(t, archivePage), "https://example.com/index.html", fetchArchiveResource)
+	if err != nil {
+		t.Fatalf("archive html: %v", err)
+	}
+
+	content := string(out)
+
+	for _, want := range []string{
+		`<base href="https://example.com/index.html"/>`,
+		`<style media="screen">@import "data:text/css;base64,`,
+		`url(data:image/png;base64,aDE=)`,
+		`url(&#39;data:image/png;base64,Ymc=&#39;)`,
+		`<img src="data:image/png;base64,bG9nbw==" alt="logo"/>`,
+		`<img src="https://example.com/missing.png"/>`,
+		`srcdoc="&lt;html&gt;`,
+		`data:image/png;base64,aW5uZXI=`,
+		`<a href="/pricing">`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("expected archive to contain %q, got %s", want, content)
+		}
+	}
+
+	for _, unexpected := range []string{"<link", "srcset", `src="/frame.html"`} {
+		if strings.Contains(content, unexpected) {
+			t.Fatalf("expected archive not to contain %q, got %s", unexpected, content)
+		}
+	}
+}
+
+func TestMHTMLRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	out, err := MHTML(context.Background(), mustParse(t, archivePage), "https://example.com/index.html", fetchArchiveResource)
+	if err != nil {
+		t.Fatalf("archive mhtml: %v", err)
+	}
+
+	snapshot, err := ReadMHTML(out)
+	if err != nil {
+		t.Fatalf("read mhtml: %v", err)
+	}
+
+	if snapshot.Main.URL != "https://example.com/index.html" || !strings.Contains(string(snapshot.Main.Body), `href="https://example.com/css/site.css"`) {
+		t.Fatalf("unexpected main part: %s %s", snapshot.Main.URL, snapshot.Main.Body)
+	}
+
+	for url, want := range map[string]string{
+		"https://example.com/img/logo.png":  "logo",
+		"https://example.com/css/font.woff": "woff",
+		"https://example.com/inner.png":     "inner",
+	} {
+		res, err := snapshot.Fetch(context.Background(), url)
+		if err != nil {
+			t.Fatalf("fetch %s: %v", url, err)
+		}
+
+		if string(res.Body) != want {
+			t.Fatalf("%s: expected %q, got %q", url, want, res.Body)
+		}
+	}
+
+	css, err := snapshot.Fetch(context.Background(), "https://example.com/css/site.css")
+	if err != nil || !strings.Contains(string(css.Body), `url(https://example.com/img/h1.png)`) {
+		t.Fatalf("expected absolute stylesheet references, got %v %s", err, css.Body)
+	}
+
+	if _, err := snapshot.Fetch(context.Background(), "https://example.com/missing.png"); err == nil {
+		t.Fatal("expected missing resource to stay out of the archive")
+	}
+}
+
+func TestReadMHTMLContentIDs(t *testing.T) {
+	t.Parallel()
+
+	data := strings.ReplaceAll(`From: <Saved by Blink>
+Snapshot-Content-Location: https://example.com/
+MIME-Version: 1.0
+Content-Type: multipart/related;
+	type="text/html";
+	boundary="----MultipartBoundary--abc----"
+
+------MultipartBoundary--abc----
+Content-Type: text/html
+Content-ID: <frame-main@mhtml.blink>
+Content-Transfer-Encoding: quoted-printable
+Content-Location: https://example.com/
+
+<html><body><iframe src=3D"cid:frame-child@mhtml.blink"></iframe></body></html>
+------MultipartBoundary--abc----
+Content-Type: text/html
+Content-ID: <frame-child@mhtml.blink>
+Content-Transfer-Encoding: quoted-printable
+Content-Location: https://example.com/child.html
+
+<html><body><img src=3D"pixel.png"></body></html>
+------MultipartBoundary--abc----
+Content-Type: image/png
+Content-Transfer-Encoding: base64
+Content-Location: https://example.com/pixel.png
+
+cGl4ZWw=
+------MultipartBoundary--abc------
+`, "\n", "\r\n")
+
+	snapshot, err := ReadMHTML([]byte(data))
+	if err != nil {
+		t.Fatalf("read mhtml: %v", err)
+	}
+
+	doc := mustParse(t, string(snapshot.Main.Body))
+
+	out, err := HTML(context.Background(), doc, snapshot.Main.URL, snapshot.Fetch)
+	if err != nil {
+		t.Fatalf("archive html: %v", err)
+	}
+
+	if !strings.Contains(string(out), `srcdoc="&lt;html&gt;`) || !strings.Contains(string(out), "data:image/png;base64,cGl4ZWw=") {
+		t.Fatalf("expected frame resolved by content ID, got %s", out)
+	}
+
+	if _, err := ReadMHTML([]byte("<html></html>")); !errors.Is(err, ErrInvalidMHTML) {
+		t.Fatalf("expected invalid archive error, got %v", err)
+	}
+}
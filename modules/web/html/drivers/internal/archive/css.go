@@ -0,0 +1,86 @@
+package archive
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// maxImportDepth limits how deep nested @import rules are archived.
+const maxImportDepth = 5
+
+// cssReference matches url() references and @import rules, whose target may also be a plain string.
+var cssReference = regexp.MustCompile(`(@import\s+)?(?:url\(\s*(?:"([^"]*)"|'([^']*)'|([^\s'"()]*))\s*\)|"([^"]*)"|'([^']*)')`)
+
+// css archives the stylesheets imported and the assets referenced by a stylesheet.
+func (a *archiver) css(ctx context.Context, source string, base *url.URL, depth int) (string, error) {
+	var failure error
+
+	out := cssReference.ReplaceAllStringFunc(source, func(match string) string {
+		if failure != nil {
+			return match
+		}
+
+		groups := cssReference.FindStringSubmatch(match)
+		imported := groups[1] != ""
+		ref := strings.Join(groups[2:], "")
+
+		// plain strings are only references inside @import rules
+		if !imported && groups[5]+groups[6] != "" {
+			return match
+		}
+
+		if ref == "" || strings.HasPrefix(ref, "#") || strings.HasPrefix(strings.ToLower(ref), "data:") {
+			return match
+		}
+
+		var value string
+		var err error
+
+		if imported {
+			value, err = a.cssImport(ctx, resolve(base, ref), depth)
+		} else {
+			value, err = a.asset(ctx, resolve(base, ref))
+		}
+
+		if err != nil {
+			failure = err
+
+			return match
+		}
+
+		return strings.Replace(match, ref, value, 1)
+	})
+
+	if failure != nil {
+		return "", failure
+	}
+
+	return out, nil
+}
+
+func (a *archiver) cssImport(ctx context.Context, target string, depth int) (string, error) {
+	if depth >= maxImportDepth {
+		return target, nil
+	}
+
+	res, err := a.load(ctx, target)
+	if err != nil || res == nil {
+		return target, err
+	}
+
+	base, _ := url.Parse(target)
+	css, err := a.css(ctx, string(res.Body), base, depth+1)
+	if err != nil {
+		return "", err
+	}
+
+	if !a.inline {
+		a.add(&Resource{URL: target, ContentType: "text/css", Body: []byte(css)})
+
+		return target, nil
+	}
+
+	return dataURI("text/css", []byte(css)), nil
+}
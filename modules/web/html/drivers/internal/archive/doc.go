@@ -0,0 +1,2 @@
+// Package archive turns HTML documents into self-contained HTML or MHTML archives.
+package archive
@@ -0,0 +1,186 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+)
+
+// ErrInvalidMHTML is returned for archives that are not multipart/related documents.
+var ErrInvalidMHTML = errors.New("invalid MHTML archive")
+
+// Snapshot is a parsed MHTML archive.
+type Snapshot struct {
+	Main      *Resource
+	resources map[string]*Resource
+}
+
+// WriteMHTML writes the main document and its resources as a multipart/related archive.
+// Text parts are quoted-printable encoded and binary parts base64 encoded.
+func WriteMHTML(w io.Writer, main *Resource, resources []*Resource) error {
+	var body bytes.Buffer
+
+	parts := multipart.NewWriter(&body)
+
+	for _, res := range append([]*Resource{main}, resources...) {
+		if err := writePart(parts, res); err != nil {
+			return err
+		}
+	}
+
+	if err := parts.Close(); err != nil {
+		return err
+	}
+
+	header := fmt.Sprintf(
+		"From: <Saved by Ferret>\r\nSnapshot-Content-Location: %s\r\nMIME-Version: 1.0\r\nContent-Type: %s\r\n\r\n",
+		main.URL,
+		mime.FormatMediaType("multipart/related", map[string]string{"type": "text/html", "boundary": parts.Boundary()}),
+	)
+
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+
+	_, err := body.WriteTo(w)
+
+	return err
+}
+
+func writePart(parts *multipart.Writer, res *Resource) error {
+	contentType := res.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	text := strings.HasPrefix(contentType, "text/")
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Location", res.URL)
+
+	if text {
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+	} else {
+		header.Set("Content-Transfer-Encoding", "base64")
+	}
+
+	part, err := parts.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	if text {
+		encoder := quotedprintable.NewWriter(part)
+
+		if _, err := encoder.Write(res.Body); err != nil {
+			return err
+		}
+
+		return encoder.Close()
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(res.Body)
+
+	for len(encoded) > 76 {
+		if _, err := io.WriteString(part, encoded[:76]+"\r\n"); err != nil {
+			return err
+		}
+
+		encoded = encoded[76:]
+	}
+
+	_, err = io.WriteString(part, encoded)
+
+	return err
+}
+
+// ReadMHTML parses an MHTML archive. The main document is the part located at the snapshot location,
+// or the first part of the archive.
+func ReadMHTML(data []byte) (*Snapshot, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMHTML, err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return nil, fmt.Errorf("%w: expected a multipart content type", ErrInvalidMHTML)
+	}
+
+	location := msg.Header.Get("Snapshot-Content-Location")
+	snapshot := &Snapshot{resources: make(map[string]*Resource)}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidMHTML, err)
+		}
+
+		res, err := readPart(part)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidMHTML, err)
+		}
+
+		if snapshot.Main == nil || (location != "" && res.URL == location && snapshot.Main.URL != location) {
+			snapshot.Main = res
+		}
+
+		if res.URL != "" {
+			snapshot.resources[res.URL] = res
+		}
+
+		if id := strings.Trim(part.Header.Get("Content-ID"), "<>"); id != "" {
+			snapshot.resources["cid:"+id] = res
+		}
+	}
+
+	if snapshot.Main == nil {
+		return nil, fmt.Errorf("%w: no parts", ErrInvalidMHTML)
+	}
+
+	return snapshot, nil
+}
+
+func readPart(part *multipart.Part) (*Resource, error) {
+	// quoted-printable parts are decoded by the multipart reader
+	var body io.Reader = part
+
+	if strings.EqualFold(part.Header.Get("Content-Transfer-Encoding"), "base64") {
+		body = base64.NewDecoder(base64.StdEncoding, part)
+	}
+
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Resource{
+		URL:         part.Header.Get("Content-Location"),
+		ContentType: part.Header.Get("Content-Type"),
+		Body:        content,
+	}, nil
+}
+
+// Fetch serves the resources of the archive by their location or content ID and can be used as a Fetcher.
+func (s *Snapshot) Fetch(_ context.Context, url string) (*Resource, error) {
+	res, ok := s.resources[url]
+	if !ok {
+		return nil, fmt.Errorf("%s is not archived", url)
+	}
+
+	return res, nil
+}
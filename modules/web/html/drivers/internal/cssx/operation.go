@@ -43,6 +43,8 @@ var operationLookup = map[string]Operation{
 	string(ExpressionNot):      {ExpressionNot, FamilyFilter},
 	string(ExpressionWithAttr): {ExpressionWithAttr, FamilyFilter},
 	string(ExpressionWithText): {ExpressionWithText, FamilyFilter},
+	string(ExpressionVisible):  {ExpressionVisible, FamilyFilter},
+	string(ExpressionHidden):   {ExpressionHidden, FamilyFilter},
 
 	string(ExpressionParent):   {ExpressionParent, FamilyTraversal},
 	string(ExpressionClosest):  {ExpressionClosest, FamilyTraversal},
@@ -67,6 +69,7 @@ var operationLookup = map[string]Operation{
 	string(ExpressionRegex):     {ExpressionRegex, FamilyMap},
 	string(ExpressionToNumber):  {ExpressionToNumber, FamilyMap},
 	string(ExpressionToDate):    {ExpressionToDate, FamilyMap},
+	string(ExpressionTable):     {ExpressionTable, FamilyMap},
 
 	string(ExpressionExists):  {ExpressionExists, FamilyReducer},
 	string(ExpressionEmpty):   {ExpressionEmpty, FamilyReducer},
@@ -15,6 +15,9 @@ func TestOperationFamilies(t *testing.T) {
 		ExpressionSiblings: FamilyTraversal,
 		ExpressionDistinct: FamilySelection,
 		ExpressionOne:      FamilyReducer,
+		ExpressionTable:    FamilyMap,
+		ExpressionVisible:  FamilyFilter,
+		ExpressionHidden:   FamilyFilter,
 	}
 
 	for expression, expected := range cases {
@@ -22,6 +22,8 @@ const (
 	ExpressionNot      Expression = ":not"
 	ExpressionWithAttr Expression = ":withAttr"
 	ExpressionWithText Expression = ":withText"
+	ExpressionVisible  Expression = ":visible"
+	ExpressionHidden   Expression = ":hidden"
 
 	ExpressionParent   Expression = ":parent"
 	ExpressionClosest  Expression = ":closest"
@@ -46,6 +48,7 @@ const (
 	ExpressionRegex     Expression = ":regex"
 	ExpressionToNumber  Expression = ":toNumber"
 	ExpressionToDate    Expression = ":toDate"
+	ExpressionTable     Expression = ":table"
 
 	ExpressionExists  Expression = ":exists"
 	ExpressionEmpty   Expression = ":empty"
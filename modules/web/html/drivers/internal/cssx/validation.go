@@ -27,7 +27,9 @@ func ValidateCallArgs(exp Expression, step cssx.Op) error {
 		ExpressionAbsURL,
 		ExpressionParseURL,
 		ExpressionDedupeByText,
-		ExpressionToNumber:
+		ExpressionToNumber,
+		ExpressionVisible,
+		ExpressionHidden:
 		if err := validateLiteralCount(step, 0); err != nil {
 			return err
 		}
@@ -151,6 +153,18 @@ func ValidateCallArgs(exp Expression, step cssx.Op) error {
 			}
 		}
 
+		return validateArityRange(step, 0, 1)
+	case ExpressionTable:
+		if err := validateLiteralCountRange(step, 0, 1); err != nil {
+			return err
+		}
+
+		if len(step.Args) > 0 {
+			if err := validateLiteralKind(step, 0, cssx.CallArgNumber); err != nil {
+				return err
+			}
+		}
+
 		return validateArityRange(step, 0, 1)
 	default:
 		return fmt.Errorf("unsupported expression %q", exp)
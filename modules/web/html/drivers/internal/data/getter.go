@@ -131,6 +131,23 @@ func GetInElement(ctx context.Context, key runtime.Value, el drivers.HTMLElement
 		}
 
 		return valueOrNone(target.GetStyles(ctx))
+	case "boundingBox", "isVisible", "isInViewport", "isEnabled":
+		target, ok := el.(drivers.GeometryTarget)
+		if !ok {
+			// elements without layout keep resolving these keys as DOM properties
+			return getElementProperty(ctx, key, el)
+		}
+
+		switch key.String() {
+		case "boundingBox":
+			return valueOrNone(target.GetBoundingBox(ctx))
+		case "isVisible":
+			return valueOrNone(target.IsVisible(ctx))
+		case "isInViewport":
+			return valueOrNone(target.IsInViewport(ctx))
+		default:
+			return valueOrNone(target.IsEnabled(ctx))
+		}
 	case "previousElementSibling":
 		target, err := drivers.ToRelationTarget(el)
 		if err != nil {
@@ -153,23 +170,28 @@ func GetInElement(ctx context.Context, key runtime.Value, el drivers.HTMLElement
 
 		return valueOrNone(target.GetParentElement(ctx))
 	default:
-		value, err := GetInNode(ctx, key, el)
-		if err != nil || value != runtime.None {
-			return value, err
-		}
+		return getElementProperty(ctx, key, el)
+	}
+}
 
-		keyVal, ok := key.(runtime.String)
-		if !ok {
-			return runtime.None, nil
-		}
+// getElementProperty resolves keys without a dedicated capability as node keys, then as DOM properties.
+func getElementProperty(ctx context.Context, key runtime.Value, el drivers.HTMLElement) (runtime.Value, error) {
+	value, err := GetInNode(ctx, key, el)
+	if err != nil || value != runtime.None {
+		return value, err
+	}
 
-		target, ok := el.(drivers.DOMPropertyTarget)
-		if !ok {
-			return runtime.None, nil
-		}
+	keyVal, ok := key.(runtime.String)
+	if !ok {
+		return runtime.None, nil
+	}
 
-		return valueOrNone(target.GetDOMProperty(ctx, keyVal))
+	target, ok := el.(drivers.DOMPropertyTarget)
+	if !ok {
+		return runtime.None, nil
 	}
+
+	return valueOrNone(target.GetDOMProperty(ctx, keyVal))
 }
 
 func GetInNode(ctx context.Context, key runtime.Value, node drivers.HTMLNode) (runtime.Value, error) {
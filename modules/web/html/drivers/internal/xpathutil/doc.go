@@ -0,0 +1,2 @@
+// Package xpathutil evaluates XPath expressions over parsed HTML trees with namespace-prefix bindings.
+package xpathutil
@@ -0,0 +1,163 @@
+package xpathutil
+
+import (
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xpath"
+	"golang.org/x/net/html"
+)
+
+// coreFunctions lists the functions of the XPath 1.0 core library and the node type tests,
+// which are available in every XPath engine.
+var coreFunctions = map[string]struct{}{
+	"last":                   {},
+	"position":               {},
+	"count":                  {},
+	"id":                     {},
+	"local-name":             {},
+	"namespace-uri":          {},
+	"name":                   {},
+	"string":                 {},
+	"concat":                 {},
+	"starts-with":            {},
+	"contains":               {},
+	"substring-before":       {},
+	"substring-after":        {},
+	"substring":              {},
+	"string-length":          {},
+	"normalize-space":        {},
+	"translate":              {},
+	"boolean":                {},
+	"not":                    {},
+	"true":                   {},
+	"false":                  {},
+	"lang":                   {},
+	"number":                 {},
+	"sum":                    {},
+	"floor":                  {},
+	"ceiling":                {},
+	"round":                  {},
+	"node":                   {},
+	"text":                   {},
+	"comment":                {},
+	"processing-instruction": {},
+}
+
+// operators are the operator names that can be directly followed by a parenthesized expression.
+var operators = map[string]struct{}{
+	"and": {},
+	"or":  {},
+	"div": {},
+	"mod": {},
+}
+
+// Compile compiles an XPath expression with the given namespace-prefix bindings.
+// Without bindings, prefixed name tests match elements by their literal prefix;
+// with bindings, every prefix used by the expression must be bound.
+func Compile(expression string, namespaces map[string]string) (*xpath.Expr, error) {
+	if len(namespaces) == 0 {
+		return xpath.Compile(expression)
+	}
+
+	return xpath.CompileWithNS(expression, namespaces)
+}
+
+// Node returns the HTML node a navigator is positioned at.
+// Attributes are returned as a detached element named after the attribute and holding its value as text.
+func Node(nav xpath.NodeNavigator) *html.Node {
+	var inner *htmlquery.NodeNavigator
+
+	switch v := nav.(type) {
+	case *Navigator:
+		inner = v.NodeNavigator
+	case *htmlquery.NodeNavigator:
+		inner = v
+	default:
+		return nil
+	}
+
+	if inner.NodeType() != xpath.AttributeNode {
+		return inner.Current()
+	}
+
+	text := &html.Node{
+		Type: html.TextNode,
+		Data: inner.Value(),
+	}
+
+	return &html.Node{
+		Type:       html.ElementNode,
+		Data:       inner.LocalName(),
+		FirstChild: text,
+		LastChild:  text,
+	}
+}
+
+// RequiresExtendedFunctions reports whether an expression calls functions outside the XPath 1.0 core library,
+// like matches(), lower-case() or string-join().
+func RequiresExtendedFunctions(expression string) bool {
+	for i := 0; i < len(expression); {
+		c := expression[i]
+
+		switch {
+		case c == '"' || c == '\'':
+			end := i + 1
+
+			for end < len(expression) && expression[end] != c {
+				end++
+			}
+
+			i = end + 1
+		case c == '$':
+			// variable references are never function calls
+			i = skipName(expression, i+1)
+		case isNameStart(c):
+			end := skipName(expression, i)
+			name := expression[i:end]
+
+			next := end
+			for next < len(expression) && isSpace(expression[next]) {
+				next++
+			}
+
+			if next < len(expression) && expression[next] == '(' {
+				_, core := coreFunctions[name]
+				_, operator := operators[name]
+
+				if !core && !operator {
+					return true
+				}
+			}
+
+			i = end
+		default:
+			i++
+		}
+	}
+
+	return false
+}
+
+func skipName(expression string, i int) int {
+	for i < len(expression) && isNameChar(expression[i]) {
+		// axis separators are not part of a name
+		if expression[i] == ':' && i+1 < len(expression) && expression[i+1] == ':' {
+			break
+		}
+
+		i++
+	}
+
+	return i
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || c == '-' || c == '.' || c == ':' || (c >= '0' && c <= '9')
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,27 @@
+package xpathutil
+
+import "testing"
+
+func TestRequiresExtendedFunctions(t *testing.T) {
+	cases := []struct {
+		expression string
+		want       bool
+	}{
+		{expression: "//div[@class='item']/text()", want: false},
+		{expression: "count(//a) and not(//b)", want: false},
+		{expression: "//a[contains(@href, 'x')] | //b[(1 + 2) div (3)]", want: false},
+		{expression: "//feed:item/feed:title", want: false},
+		{expression: "ancestor::div[starts-with(@id, 'x')]", want: false},
+		{expression: "//p[. = 'lower-case(x)']", want: false},
+		{expression: "//a[matches(@href, '^https')]", want: true},
+		{expression: "lower-case(//title)", want: true},
+		{expression: "string-join(//li/text(), ', ')", want: true},
+		{expression: "//a[ends-with (@href, '.pdf')]", want: true},
+	}
+
+	for _, tc := range cases {
+		if got := RequiresExtendedFunctions(tc.expression); got != tc.want {
+			t.Errorf("RequiresExtendedFunctions(%q) = %t, want %t", tc.expression, got, tc.want)
+		}
+	}
+}
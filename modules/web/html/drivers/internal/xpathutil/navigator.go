@@ -0,0 +1,204 @@
+package xpathutil
+
+import (
+	"strings"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xpath"
+	"golang.org/x/net/html"
+)
+
+const (
+	XHTMLNamespace  = "http://www.w3.org/1999/xhtml"
+	SVGNamespace    = "http://www.w3.org/2000/svg"
+	MathMLNamespace = "http://www.w3.org/1998/Math/MathML"
+	XLinkNamespace  = "http://www.w3.org/1999/xlink"
+	XMLNamespace    = "http://www.w3.org/XML/1998/namespace"
+	XMLNSNamespace  = "http://www.w3.org/2000/xmlns/"
+)
+
+// Navigator is an HTML node navigator that exposes namespace prefixes and URIs.
+// Prefixed names like "feed:item" are split into a prefix and a local name,
+// and prefixes are resolved through the xmlns declarations in scope, falling back to the given bindings,
+// so that documents parsed as HTML can still be queried by namespace.
+type Navigator struct {
+	*htmlquery.NodeNavigator
+	namespaces map[string]string
+}
+
+// NewNavigator returns a navigator rooted at the given node.
+func NewNavigator(root *html.Node, namespaces map[string]string) *Navigator {
+	return &Navigator{
+		NodeNavigator: htmlquery.CreateXPathNavigator(root),
+		namespaces:    namespaces,
+	}
+}
+
+// NewNavigatorAt returns a navigator rooted at the given node and positioned at one of its descendants.
+// Absolute location paths are then resolved against the root rather than the context node.
+func NewNavigatorAt(root, current *html.Node, namespaces map[string]string) *Navigator {
+	nav := NewNavigator(root, namespaces)
+
+	if current == nil || current == root {
+		return nav
+	}
+
+	ancestors := make([]*html.Node, 0, 8)
+
+	for node := current; node != nil && node != root; node = node.Parent {
+		ancestors = append(ancestors, node)
+	}
+
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		if !nav.MoveToChild() {
+			return NewNavigator(root, namespaces)
+		}
+
+		for nav.Current() != ancestors[i] {
+			if !nav.MoveToNext() {
+				return NewNavigator(root, namespaces)
+			}
+		}
+	}
+
+	return nav
+}
+
+func (n *Navigator) LocalName() string {
+	_, local := n.name()
+
+	return local
+}
+
+func (n *Navigator) Prefix() string {
+	prefix, _ := n.name()
+
+	return prefix
+}
+
+// NamespaceURL returns the namespace URI of the current element or attribute.
+func (n *Navigator) NamespaceURL() string {
+	node := n.Current()
+	prefix, _ := n.name()
+
+	switch n.NodeType() {
+	case xpath.ElementNode:
+		if prefix != "" {
+			return n.lookupPrefix(node, prefix)
+		}
+
+		switch node.Namespace {
+		case "":
+			if ns, ok := lookupDeclaration(node, "xmlns"); ok {
+				return ns
+			}
+
+			return XHTMLNamespace
+		case "svg":
+			return SVGNamespace
+		case "math":
+			return MathMLNamespace
+		default:
+			return node.Namespace
+		}
+	case xpath.AttributeNode:
+		if prefix == "" {
+			return ""
+		}
+
+		return n.lookupPrefix(node, prefix)
+	default:
+		return ""
+	}
+}
+
+func (n *Navigator) Copy() xpath.NodeNavigator {
+	return &Navigator{
+		NodeNavigator: n.NodeNavigator.Copy().(*htmlquery.NodeNavigator),
+		namespaces:    n.namespaces,
+	}
+}
+
+func (n *Navigator) MoveTo(other xpath.NodeNavigator) bool {
+	nav, ok := other.(*Navigator)
+	if !ok {
+		return false
+	}
+
+	return n.NodeNavigator.MoveTo(nav.NodeNavigator)
+}
+
+func (n *Navigator) name() (string, string) {
+	switch n.NodeType() {
+	case xpath.ElementNode:
+		return splitName(n.Current().Data)
+	case xpath.AttributeNode:
+		if attr := n.attribute(); attr != nil && attr.Namespace != "" {
+			return attr.Namespace, attr.Key
+		}
+
+		return splitName(n.NodeNavigator.LocalName())
+	default:
+		return "", n.NodeNavigator.LocalName()
+	}
+}
+
+// attribute returns the attribute the navigator is positioned at.
+func (n *Navigator) attribute() *html.Attribute {
+	node := n.Current()
+	key := n.NodeNavigator.LocalName()
+	val := n.Value()
+
+	for i := range node.Attr {
+		if node.Attr[i].Key == key && node.Attr[i].Val == val {
+			return &node.Attr[i]
+		}
+	}
+
+	return nil
+}
+
+func (n *Navigator) lookupPrefix(node *html.Node, prefix string) string {
+	switch prefix {
+	case "xml":
+		return XMLNamespace
+	case "xmlns":
+		return XMLNSNamespace
+	}
+
+	if ns, ok := lookupDeclaration(node, "xmlns:"+prefix); ok {
+		return ns
+	}
+
+	// foreign attributes are parsed with a fixed set of prefixes
+	if prefix == "xlink" {
+		return XLinkNamespace
+	}
+
+	return n.namespaces[prefix]
+}
+
+// lookupDeclaration returns the value of the closest namespace declaration attribute in scope.
+func lookupDeclaration(node *html.Node, name string) (string, bool) {
+	for ; node != nil; node = node.Parent {
+		if node.Type != html.ElementNode {
+			continue
+		}
+
+		for _, attr := range node.Attr {
+			if attr.Namespace == "" && attr.Key == name {
+				return attr.Val, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func splitName(name string) (string, string) {
+	if prefix, local, ok := strings.Cut(name, ":"); ok && prefix != "" && local != "" {
+		return prefix, local
+	}
+
+	return "", name
+}
@@ -0,0 +1,485 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+type (
+	// CacheMode controls how the driver consults its response cache.
+	CacheMode int
+
+	// CacheStore persists cached HTTP responses.
+	// Get must return a nil entry without an error when nothing is stored under the key.
+	CacheStore interface {
+		Get(ctx context.Context, key string) (*CacheEntry, error)
+		Set(ctx context.Context, key string, entry *CacheEntry) error
+		Delete(ctx context.Context, key string) error
+	}
+
+	// CacheEntry is a stored HTTP response together with the timing data
+	// required to calculate its age.
+	CacheEntry struct {
+		RequestTime  time.Time         `json:"requestTime"`
+		ResponseTime time.Time         `json:"responseTime"`
+		Header       http.Header       `json:"header"`
+		Vary         map[string]string `json:"vary,omitempty"`
+		URL          string            `json:"url"`
+		FinalURL     string            `json:"finalUrl,omitempty"`
+		Status       string            `json:"status"`
+		Body         []byte            `json:"body"`
+		StatusCode   int               `json:"statusCode"`
+	}
+
+	httpCache struct {
+		store CacheStore
+		now   func() time.Time
+		mode  CacheMode
+	}
+
+	fetchFunc func(req *http.Request) (*http.Response, error)
+)
+
+const (
+	// CacheModeDefault serves fresh responses from the cache, revalidates stale ones
+	// and stores new cacheable responses.
+	CacheModeDefault CacheMode = iota
+	// CacheModeReplay serves stored responses regardless of their freshness and never
+	// touches the network.
+	CacheModeReplay
+)
+
+// ErrCacheMiss is returned when a response must come from the cache but none is stored.
+var ErrCacheMiss = errors.New("no cached response")
+
+func newHTTPCache(options *Options) *httpCache {
+	store := options.Cache
+
+	if store == nil {
+		if options.CacheMode != CacheModeReplay {
+			return nil
+		}
+
+		// replay without a store can only miss, but it must never go to the network
+		store = NewMemoryCache(0)
+	}
+
+	return &httpCache{
+		store: store,
+		mode:  options.CacheMode,
+		now:   time.Now,
+	}
+}
+
+func (c *httpCache) do(ctx context.Context, req *http.Request, fetch fetchFunc) (*http.Response, error) {
+	key := cacheKey(req)
+	reqCC := parseCacheControl(req.Header)
+
+	if c.mode == CacheModeReplay {
+		entry, err := c.lookup(ctx, key, req)
+		if err != nil {
+			return nil, err
+		}
+
+		if entry == nil {
+			return nil, runtime.Errorf(ErrCacheMiss, "replay %s", req.URL)
+		}
+
+		return c.serve(entry, req), nil
+	}
+
+	if req.Method != http.MethodGet || reqCC.has("no-store") {
+		return fetch(req)
+	}
+
+	entry, err := c.lookup(ctx, key, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry == nil {
+		if reqCC.has("only-if-cached") {
+			return nil, runtime.Errorf(ErrCacheMiss, "only-if-cached %s", req.URL)
+		}
+
+		return c.fetch(ctx, key, req, fetch)
+	}
+
+	if !reqCC.has("no-cache") && entry.fresh(c.now(), reqCC) {
+		return c.serve(entry, req), nil
+	}
+
+	if reqCC.has("only-if-cached") {
+		return nil, runtime.Errorf(ErrCacheMiss, "only-if-cached %s: stored response is stale", req.URL)
+	}
+
+	if !entry.hasValidators() {
+		return c.fetch(ctx, key, req, fetch)
+	}
+
+	return c.revalidate(ctx, key, req, entry, fetch)
+}
+
+func (c *httpCache) lookup(ctx context.Context, key string, req *http.Request) (*CacheEntry, error) {
+	entry, err := c.store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry == nil || !entry.matches(req) {
+		return nil, nil
+	}
+
+	return entry, nil
+}
+
+func (c *httpCache) fetch(ctx context.Context, key string, req *http.Request, fetch fetchFunc) (*http.Response, error) {
+	requestTime := c.now()
+
+	resp, err := fetch(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.save(ctx, key, req, resp, requestTime)
+}
+
+func (c *httpCache) revalidate(
+	ctx context.Context,
+	key string,
+	req *http.Request,
+	entry *CacheEntry,
+	fetch fetchFunc,
+) (*http.Response, error) {
+	conditional := req.Clone(ctx)
+
+	if etag := entry.Header.Get("ETag"); etag != "" {
+		conditional.Header.Set("If-None-Match", etag)
+	}
+
+	if modified := entry.Header.Get("Last-Modified"); modified != "" {
+		conditional.Header.Set("If-Modified-Since", modified)
+	}
+
+	requestTime := c.now()
+
+	resp, err := fetch(conditional)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusNotModified {
+		return c.save(ctx, key, req, resp, requestTime)
+	}
+
+	resp.Body.Close()
+
+	entry.refresh(resp.Header, requestTime, c.now())
+
+	if err := c.store.Set(ctx, key, entry); err != nil {
+		return nil, err
+	}
+
+	return c.serve(entry, req), nil
+}
+
+func (c *httpCache) save(
+	ctx context.Context,
+	key string,
+	req *http.Request,
+	resp *http.Response,
+	requestTime time.Time,
+) (*http.Response, error) {
+	responseTime := c.now()
+
+	if !isStorable(req, resp) {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &CacheEntry{
+		URL:          req.URL.String(),
+		FinalURL:     finalURL(req, resp),
+		Status:       resp.Status,
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header.Clone(),
+		Vary:         varyValues(req, resp.Header),
+		Body:         body,
+		RequestTime:  requestTime,
+		ResponseTime: responseTime,
+	}
+
+	if err := c.store.Set(ctx, key, entry); err != nil {
+		return nil, err
+	}
+
+	return entry.response(req), nil
+}
+
+func (c *httpCache) serve(entry *CacheEntry, req *http.Request) *http.Response {
+	resp := entry.response(req)
+	resp.Header.Set("Age", strconv.FormatInt(int64(entry.age(c.now())/time.Second), 10))
+
+	return resp
+}
+
+// response rebuilds the stored response. A response that was reached through redirects carries a request
+// for its final URL, as a fetched response does.
+func (e *CacheEntry) response(req *http.Request) *http.Response {
+	if e.FinalURL != "" {
+		if final, err := url.Parse(e.FinalURL); err == nil {
+			req = req.Clone(req.Context())
+			req.URL = final
+			req.Host = final.Host
+		}
+	}
+
+	return &http.Response{
+		Status:        e.Status,
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// finalURL returns the URL a response was received from when redirects led away from the request URL.
+func finalURL(req *http.Request, resp *http.Response) string {
+	if resp.Request == nil || resp.Request.URL == nil {
+		return ""
+	}
+
+	if final := resp.Request.URL.String(); final != req.URL.String() {
+		return final
+	}
+
+	return ""
+}
+
+func (e *CacheEntry) clone() *CacheEntry {
+	cp := *e
+	cp.Header = e.Header.Clone()
+	cp.Body = append([]byte(nil), e.Body...)
+
+	if e.Vary != nil {
+		cp.Vary = make(map[string]string, len(e.Vary))
+
+		for k, v := range e.Vary {
+			cp.Vary[k] = v
+		}
+	}
+
+	return &cp
+}
+
+// matches reports whether the request selects the stored variant.
+func (e *CacheEntry) matches(req *http.Request) bool {
+	for name, value := range e.Vary {
+		if strings.Join(req.Header.Values(name), ", ") != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (e *CacheEntry) hasValidators() bool {
+	return e.Header.Get("ETag") != "" || e.Header.Get("Last-Modified") != ""
+}
+
+// refresh updates the stored headers and timing with a 304 response.
+func (e *CacheEntry) refresh(header http.Header, requestTime, responseTime time.Time) {
+	for name, values := range header {
+		if name == "Content-Length" {
+			continue
+		}
+
+		e.Header[name] = append([]string(nil), values...)
+	}
+
+	e.RequestTime = requestTime
+	e.ResponseTime = responseTime
+}
+
+func (e *CacheEntry) fresh(now time.Time, reqCC cacheControl) bool {
+	respCC := parseCacheControl(e.Header)
+
+	if respCC.has("no-cache") {
+		return false
+	}
+
+	lifetime := e.lifetime(respCC)
+	age := e.age(now)
+
+	if maxAge, ok := reqCC.seconds("max-age"); ok && age > maxAge {
+		return false
+	}
+
+	if minFresh, ok := reqCC.seconds("min-fresh"); ok {
+		age += minFresh
+	}
+
+	if lifetime > age {
+		return true
+	}
+
+	if respCC.has("must-revalidate") || !reqCC.has("max-stale") {
+		return false
+	}
+
+	maxStale, ok := reqCC.seconds("max-stale")
+
+	// max-stale without a value accepts a response of any staleness
+	return !ok || age-lifetime <= maxStale
+}
+
+func (e *CacheEntry) lifetime(respCC cacheControl) time.Duration {
+	if maxAge, ok := respCC.seconds("max-age"); ok {
+		return maxAge
+	}
+
+	if expires := e.Header.Get("Expires"); expires != "" {
+		t, err := http.ParseTime(expires)
+		if err != nil {
+			// invalid dates represent a time in the past
+			return 0
+		}
+
+		return t.Sub(e.date())
+	}
+
+	if !isHeuristicallyCacheable(e.StatusCode) {
+		return 0
+	}
+
+	modified, err := http.ParseTime(e.Header.Get("Last-Modified"))
+	if err != nil {
+		return 0
+	}
+
+	// the 10% heuristic suggested by RFC 9111 section 4.2.2
+	return e.date().Sub(modified) / 10
+}
+
+func (e *CacheEntry) age(now time.Time) time.Duration {
+	apparent := max(e.ResponseTime.Sub(e.date()), 0)
+
+	var ageValue time.Duration
+
+	if seconds, err := strconv.ParseInt(e.Header.Get("Age"), 10, 64); err == nil && seconds > 0 {
+		ageValue = time.Duration(seconds) * time.Second
+	}
+
+	corrected := ageValue + e.ResponseTime.Sub(e.RequestTime)
+
+	return max(apparent, corrected) + now.Sub(e.ResponseTime)
+}
+
+func (e *CacheEntry) date() time.Time {
+	if t, err := http.ParseTime(e.Header.Get("Date")); err == nil {
+		return t
+	}
+
+	return e.ResponseTime
+}
+
+func cacheKey(req *http.Request) string {
+	return req.Method + " " + req.URL.String()
+}
+
+func isStorable(req *http.Request, resp *http.Response) bool {
+	if req.Method != http.MethodGet {
+		return false
+	}
+
+	if resp.StatusCode == http.StatusPartialContent || resp.StatusCode == http.StatusNotModified {
+		return false
+	}
+
+	respCC := parseCacheControl(resp.Header)
+
+	if respCC.has("no-store") {
+		return false
+	}
+
+	for _, name := range headerTokens(resp.Header, "Vary") {
+		if name == "*" {
+			return false
+		}
+	}
+
+	if isHeuristicallyCacheable(resp.StatusCode) {
+		return true
+	}
+
+	return respCC.has("max-age") || respCC.has("public") || resp.Header.Get("Expires") != ""
+}
+
+func isHeuristicallyCacheable(code int) bool {
+	switch code {
+	case http.StatusOK,
+		http.StatusNonAuthoritativeInfo,
+		http.StatusNoContent,
+		http.StatusMultipleChoices,
+		http.StatusMovedPermanently,
+		http.StatusPermanentRedirect,
+		http.StatusNotFound,
+		http.StatusMethodNotAllowed,
+		http.StatusGone,
+		http.StatusRequestURITooLong,
+		http.StatusNotImplemented:
+		return true
+	default:
+		return false
+	}
+}
+
+func varyValues(req *http.Request, header http.Header) map[string]string {
+	names := headerTokens(header, "Vary")
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	res := make(map[string]string, len(names))
+
+	for _, name := range names {
+		name = http.CanonicalHeaderKey(name)
+		res[name] = strings.Join(req.Header.Values(name), ", ")
+	}
+
+	return res
+}
+
+func headerTokens(header http.Header, name string) []string {
+	var res []string
+
+	for _, value := range header.Values(name) {
+		for _, token := range strings.Split(value, ",") {
+			if token = strings.TrimSpace(token); token != "" {
+				res = append(res, token)
+			}
+		}
+	}
+
+	return res
+}
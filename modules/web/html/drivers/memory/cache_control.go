@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cacheControl holds parsed Cache-Control directives keyed by their lower-cased names.
+type cacheControl map[string]string
+
+func parseCacheControl(header http.Header) cacheControl {
+	res := make(cacheControl)
+
+	for _, directive := range headerTokens(header, "Cache-Control") {
+		name, value, _ := strings.Cut(directive, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+
+		res[name] = value
+	}
+
+	// Pragma is only consulted when Cache-Control is absent
+	if len(res) == 0 {
+		for _, token := range headerTokens(header, "Pragma") {
+			if strings.EqualFold(token, "no-cache") {
+				res["no-cache"] = ""
+			}
+		}
+	}
+
+	return res
+}
+
+func (cc cacheControl) has(name string) bool {
+	_, ok := cc[name]
+
+	return ok
+}
+
+// seconds returns the delta-seconds value of a directive.
+// Directives without a value are reported as absent, malformed values as zero.
+func (cc cacheControl) seconds(name string) (time.Duration, bool) {
+	value, ok := cc[name]
+
+	if !ok || value == "" {
+		return 0, false
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, true
+	}
+
+	return time.Duration(n) * time.Second, true
+}
@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	stdfs "io/fs"
+	"path"
+	"sync"
+
+	"github.com/goccy/go-json"
+
+	ferretfs "github.com/MontFerret/ferret/v2/pkg/fs"
+)
+
+var DefaultCacheCapacity = 1000
+
+type (
+	// MemoryCache is an in-memory CacheStore that evicts the least recently used entries.
+	MemoryCache struct {
+		entries  map[string]*list.Element
+		order    *list.List
+		mu       sync.Mutex
+		capacity int
+	}
+
+	memoryCacheItem struct {
+		entry *CacheEntry
+		key   string
+	}
+
+	// FileCache is a CacheStore that keeps one JSON file per entry in a directory
+	// of the Ferret filesystem bound to the query context.
+	FileCache struct {
+		dir string
+	}
+)
+
+// NewMemoryCache creates an LRU cache holding up to capacity entries.
+// Non-positive capacity falls back to DefaultCacheCapacity.
+func NewMemoryCache(capacity int) *MemoryCache {
+	if capacity <= 0 {
+		capacity = DefaultCacheCapacity
+	}
+
+	return &MemoryCache{
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		capacity: capacity,
+	}
+}
+
+func (c *MemoryCache) Get(_ context.Context, key string) (*CacheEntry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, nil
+	}
+
+	c.order.MoveToFront(el)
+
+	return el.Value.(*memoryCacheItem).entry.clone(), nil
+}
+
+func (c *MemoryCache) Set(_ context.Context, key string, entry *CacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*memoryCacheItem).entry = entry.clone()
+		c.order.MoveToFront(el)
+
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&memoryCacheItem{key: key, entry: entry.clone()})
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryCacheItem).key)
+	}
+
+	return nil
+}
+
+func (c *MemoryCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.order.Remove(el)
+		delete(c.entries, key)
+	}
+
+	return nil
+}
+
+// Len returns the number of stored entries.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// NewFileCache creates a cache stored in dir.
+// The directory is resolved through the Ferret filesystem at request time and created on first write.
+func NewFileCache(dir string) *FileCache {
+	return &FileCache{dir: dir}
+}
+
+func (c *FileCache) Get(ctx context.Context, key string) (*CacheEntry, error) {
+	reader, err := ferretfs.ReaderFrom(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("resolve filesystem: %w", err)
+	}
+
+	data, err := reader.ReadFile(c.path(key))
+	if err != nil {
+		if errors.Is(err, stdfs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("read cache entry %q: %w", c.path(key), err)
+	}
+
+	entry := new(CacheEntry)
+
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, fmt.Errorf("decode cache entry %q: %w", c.path(key), err)
+	}
+
+	return entry, nil
+}
+
+func (c *FileCache) Set(ctx context.Context, key string, entry *CacheEntry) error {
+	filesystem, err := ferretfs.FileSystemFrom(ctx)
+	if err != nil {
+		return fmt.Errorf("resolve filesystem: %w", err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encode cache entry %q: %w", c.path(key), err)
+	}
+
+	if err := filesystem.MkdirAll(c.dir, 0o777); err != nil {
+		return fmt.Errorf("create cache directory %q: %w", c.dir, err)
+	}
+
+	if err := filesystem.WriteFile(c.path(key), data, 0o666); err != nil {
+		return fmt.Errorf("write cache entry %q: %w", c.path(key), err)
+	}
+
+	return nil
+}
+
+func (c *FileCache) Delete(ctx context.Context, key string) error {
+	filesystem, err := ferretfs.FileSystemFrom(ctx)
+	if err != nil {
+		return fmt.Errorf("resolve filesystem: %w", err)
+	}
+
+	if err := filesystem.Remove(c.path(key)); err != nil && !errors.Is(err, stdfs.ErrNotExist) {
+		return fmt.Errorf("remove cache entry %q: %w", c.path(key), err)
+	}
+
+	return nil
+}
+
+func (c *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return path.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
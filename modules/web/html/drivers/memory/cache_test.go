@@ -0,0 +1,317 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	ferretfs "github.com/MontFerret/ferret/v2/pkg/fs"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+)
+
+const cachedPage = `<!DOCTYPE html><html><head></head><body><h1>Cached</h1></body></html>`
+
+func cachedResponder(header http.Header) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(http.StatusOK, cachedPage)
+
+		for k, v := range header {
+			resp.Header[k] = v
+		}
+
+		return resp, nil
+	}
+}
+
+func TestDriver_Cache(t *testing.T) {
+	Convey("Should serve fresh responses from the cache", t, func() {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("GET", "http://localhost:1111", cachedResponder(http.Header{
+			"Cache-Control": {"max-age=60"},
+		}))
+
+		drv := New(WithMemoryCache(10))
+
+		for range 3 {
+			page, err := drv.Open(context.Background(), drivers.Params{URL: "http://localhost:1111"})
+
+			So(err, ShouldBeNil)
+			So(page, ShouldNotBeNil)
+		}
+
+		So(httpmock.GetTotalCallCount(), ShouldEqual, 1)
+	})
+
+	Convey("Should not store no-store responses", t, func() {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("GET", "http://localhost:1111", cachedResponder(http.Header{
+			"Cache-Control": {"no-store"},
+		}))
+
+		cache := NewMemoryCache(10)
+		drv := New(WithCache(cache))
+
+		for range 2 {
+			_, err := drv.Open(context.Background(), drivers.Params{URL: "http://localhost:1111"})
+
+			So(err, ShouldBeNil)
+		}
+
+		So(httpmock.GetTotalCallCount(), ShouldEqual, 2)
+		So(cache.Len(), ShouldEqual, 0)
+	})
+
+	Convey("Should revalidate stale responses with ETag", t, func() {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var conditional int
+
+		httpmock.RegisterResponder("GET", "http://localhost:1111", func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("If-None-Match") == `"v1"` {
+				conditional++
+
+				return httpmock.NewStringResponse(http.StatusNotModified, ""), nil
+			}
+
+			return cachedResponder(http.Header{
+				"Cache-Control": {"no-cache"},
+				"Etag":          {`"v1"`},
+			})(req)
+		})
+
+		drv := New(WithMemoryCache(10))
+
+		for range 2 {
+			page, err := drv.Open(context.Background(), drivers.Params{URL: "http://localhost:1111"})
+
+			So(err, ShouldBeNil)
+			So(page.(*HTMLPage).response.StatusCode, ShouldEqual, http.StatusOK)
+		}
+
+		So(httpmock.GetTotalCallCount(), ShouldEqual, 2)
+		So(conditional, ShouldEqual, 1)
+	})
+
+	Convey("Should honor request no-cache directives", t, func() {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("GET", "http://localhost:1111", cachedResponder(http.Header{
+			"Cache-Control": {"max-age=60"},
+		}))
+
+		drv := New(WithMemoryCache(10))
+		headers := drivers.NewHTTPHeadersWith(map[string][]string{
+			"Cache-Control": {"no-cache"},
+		})
+
+		for range 2 {
+			_, err := drv.Open(context.Background(), drivers.Params{URL: "http://localhost:1111", Headers: headers})
+
+			So(err, ShouldBeNil)
+		}
+
+		So(httpmock.GetTotalCallCount(), ShouldEqual, 2)
+	})
+}
+
+func TestDriver_CacheReplay(t *testing.T) {
+	Convey("Should fail on a cache miss without touching the network", t, func() {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("GET", "http://localhost:1111", cachedResponder(nil))
+
+		drv := New(WithMemoryCache(10), WithCacheMode(CacheModeReplay))
+
+		_, err := drv.Open(context.Background(), drivers.Params{URL: "http://localhost:1111"})
+
+		So(err, ShouldNotBeNil)
+		So(errors.Is(err, ErrCacheMiss), ShouldBeTrue)
+		So(httpmock.GetTotalCallCount(), ShouldEqual, 0)
+	})
+
+	Convey("Should serve stale recorded responses", t, func() {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("GET", "http://localhost:1111", cachedResponder(http.Header{
+			"Cache-Control": {"max-age=0"},
+		}))
+
+		cache := NewMemoryCache(10)
+
+		_, err := New(WithCache(cache)).Open(context.Background(), drivers.Params{URL: "http://localhost:1111"})
+		So(err, ShouldBeNil)
+
+		page, err := New(WithCache(cache), WithCacheMode(CacheModeReplay)).
+			Open(context.Background(), drivers.Params{URL: "http://localhost:1111"})
+
+		So(err, ShouldBeNil)
+		So(page, ShouldNotBeNil)
+		So(httpmock.GetTotalCallCount(), ShouldEqual, 1)
+	})
+}
+
+func TestCacheEntry_fresh(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	newEntry := func(header http.Header, age time.Duration) *CacheEntry {
+		header.Set("Date", now.Add(-age).Format(http.TimeFormat))
+
+		return &CacheEntry{
+			Header:       header,
+			StatusCode:   http.StatusOK,
+			RequestTime:  now.Add(-age),
+			ResponseTime: now.Add(-age),
+		}
+	}
+
+	Convey("Should use max-age", t, func() {
+		So(newEntry(http.Header{"Cache-Control": {"max-age=60"}}, 30*time.Second).fresh(now, cacheControl{}), ShouldBeTrue)
+		So(newEntry(http.Header{"Cache-Control": {"max-age=60"}}, 90*time.Second).fresh(now, cacheControl{}), ShouldBeFalse)
+	})
+
+	Convey("Should use Expires", t, func() {
+		entry := newEntry(http.Header{"Expires": {now.Add(time.Minute).Format(http.TimeFormat)}}, 0)
+
+		So(entry.fresh(now, cacheControl{}), ShouldBeTrue)
+		So(entry.fresh(now.Add(2*time.Minute), cacheControl{}), ShouldBeFalse)
+	})
+
+	Convey("Should use the Last-Modified heuristic", t, func() {
+		entry := newEntry(http.Header{"Last-Modified": {now.Add(-100 * time.Minute).Format(http.TimeFormat)}}, 0)
+
+		So(entry.fresh(now.Add(5*time.Minute), cacheControl{}), ShouldBeTrue)
+		So(entry.fresh(now.Add(15*time.Minute), cacheControl{}), ShouldBeFalse)
+	})
+
+	Convey("Should apply request directives", t, func() {
+		entry := newEntry(http.Header{"Cache-Control": {"max-age=60"}}, 30*time.Second)
+
+		So(entry.fresh(now, parseCacheControl(http.Header{"Cache-Control": {"max-age=10"}})), ShouldBeFalse)
+		So(entry.fresh(now, parseCacheControl(http.Header{"Cache-Control": {"min-fresh=40"}})), ShouldBeFalse)
+
+		stale := newEntry(http.Header{"Cache-Control": {"max-age=60"}}, 90*time.Second)
+
+		So(stale.fresh(now, parseCacheControl(http.Header{"Cache-Control": {"max-stale=60"}})), ShouldBeTrue)
+		So(stale.fresh(now, parseCacheControl(http.Header{"Cache-Control": {"max-stale"}})), ShouldBeTrue)
+	})
+
+	Convey("Should not serve must-revalidate responses stale", t, func() {
+		stale := newEntry(http.Header{"Cache-Control": {"max-age=60, must-revalidate"}}, 90*time.Second)
+
+		So(stale.fresh(now, parseCacheControl(http.Header{"Cache-Control": {"max-stale"}})), ShouldBeFalse)
+	})
+}
+
+func TestCacheEntry_matches(t *testing.T) {
+	Convey("Should select stored variants by Vary headers", t, func() {
+		req, _ := http.NewRequest(http.MethodGet, "http://localhost:1111", nil)
+		req.Header.Set("Accept-Language", "en")
+
+		entry := &CacheEntry{
+			Vary: varyValues(req, http.Header{"Vary": {"accept-language"}}),
+		}
+
+		So(entry.matches(req), ShouldBeTrue)
+
+		req.Header.Set("Accept-Language", "de")
+
+		So(entry.matches(req), ShouldBeFalse)
+	})
+}
+
+func TestHTTPCache_finalURL(t *testing.T) {
+	Convey("Should keep the final URL of redirected responses", t, func() {
+		cache := newHTTPCache(&Options{Cache: NewMemoryCache(10)})
+		fetch := func(req *http.Request) (*http.Response, error) {
+			final, _ := http.NewRequest(http.MethodGet, "http://localhost:1111/final/", nil)
+
+			resp := httpmock.NewStringResponse(http.StatusOK, cachedPage)
+			resp.Header.Set("Cache-Control", "max-age=60")
+			resp.Request = final
+
+			return resp, nil
+		}
+
+		for range 2 {
+			req, _ := http.NewRequest(http.MethodGet, "http://localhost:1111/start", nil)
+
+			resp, err := cache.do(context.Background(), req, fetch)
+
+			So(err, ShouldBeNil)
+			So(resp.Request.URL.String(), ShouldEqual, "http://localhost:1111/final/")
+		}
+	})
+}
+
+func TestMemoryCache(t *testing.T) {
+	Convey("Should evict least recently used entries", t, func() {
+		ctx := context.Background()
+		cache := NewMemoryCache(2)
+
+		So(cache.Set(ctx, "a", &CacheEntry{URL: "a"}), ShouldBeNil)
+		So(cache.Set(ctx, "b", &CacheEntry{URL: "b"}), ShouldBeNil)
+
+		entry, err := cache.Get(ctx, "a")
+		So(err, ShouldBeNil)
+		So(entry.URL, ShouldEqual, "a")
+
+		So(cache.Set(ctx, "c", &CacheEntry{URL: "c"}), ShouldBeNil)
+		So(cache.Len(), ShouldEqual, 2)
+
+		entry, err = cache.Get(ctx, "b")
+		So(err, ShouldBeNil)
+		So(entry, ShouldBeNil)
+
+		entry, err = cache.Get(ctx, "a")
+		So(err, ShouldBeNil)
+		So(entry, ShouldNotBeNil)
+	})
+}
+
+func TestFileCache(t *testing.T) {
+	Convey("Should persist entries through the Ferret filesystem", t, func() {
+		filesystem, err := ferretfs.New(ferretfs.WithRoot(t.TempDir()))
+		So(err, ShouldBeNil)
+
+		ctx := ferretfs.WithFileSystem(context.Background(), filesystem)
+		cache := NewFileCache("cache/pages")
+
+		entry, err := cache.Get(ctx, "GET http://localhost:1111")
+		So(err, ShouldBeNil)
+		So(entry, ShouldBeNil)
+
+		So(cache.Set(ctx, "GET http://localhost:1111", &CacheEntry{
+			URL:        "http://localhost:1111",
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Etag": {`"v1"`}},
+			Body:       []byte(cachedPage),
+		}), ShouldBeNil)
+
+		entry, err = NewFileCache("cache/pages").Get(ctx, "GET http://localhost:1111")
+		So(err, ShouldBeNil)
+		So(entry.StatusCode, ShouldEqual, http.StatusOK)
+		So(entry.Header.Get("ETag"), ShouldEqual, `"v1"`)
+		So(string(entry.Body), ShouldEqual, cachedPage)
+
+		So(cache.Delete(ctx, "GET http://localhost:1111"), ShouldBeNil)
+
+		entry, err = cache.Get(ctx, "GET http://localhost:1111")
+		So(err, ShouldBeNil)
+		So(entry, ShouldBeNil)
+	})
+}
@@ -192,6 +192,10 @@ func cssxApplyTraversal(name cssx.Expression, args []any, input any) []any {
 }
 
 func cssxApplyFilter(name cssx.Expression, args []any, input any) []any {
+	if name == cssx.ExpressionVisible || name == cssx.ExpressionHidden {
+		return cssxApplyVisibility(name == cssx.ExpressionVisible, input)
+	}
+
 	criterion := cssxArgString(args, 0)
 	out := make([]any, 0)
 
@@ -306,6 +310,8 @@ func cssxMapItem(name cssx.Expression, args []any, input any, baseURL *url.URL)
 		return cssxToNumber(input)
 	case cssx.ExpressionToDate:
 		return cssxToDate(input, args)
+	case cssx.ExpressionTable:
+		return cssxTable(node, args)
 	}
 
 	return nil
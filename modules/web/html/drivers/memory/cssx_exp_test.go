@@ -2,6 +2,7 @@ package memory
 
 import (
 	"net/url"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -143,6 +144,35 @@ func TestCSSXSelectionFilters(t *testing.T) {
 	}
 }
 
+func TestCSSXVisibility(t *testing.T) {
+	root := mustSelection(t, `<div>
+		<p>shown</p>
+		<p hidden>attr</p>
+		<p style="display: none">display</p>
+		<div style="opacity:0"><p>faded</p></div>
+		<div style="visibility: hidden"><p style="visibility: visible">restored</p><p>inherited</p></div>
+		<input type="hidden" value="token">
+		<template><p>template</p></template>
+	</div>`)
+
+	paragraphs := cssxQueryAll(root, "p")
+
+	visible := cssxApplyCall(cssxcommon.ExpressionVisible, nil, []any{paragraphs}, nil)
+	if texts := nodeTexts(visible); !reflect.DeepEqual(texts, []string{"shown", "restored"}) {
+		t.Fatalf("unexpected visible result: %v", texts)
+	}
+
+	hidden := cssxApplyCall(cssxcommon.ExpressionHidden, nil, []any{paragraphs}, nil)
+	if texts := nodeTexts(hidden); !reflect.DeepEqual(texts, []string{"attr", "display", "faded", "inherited", "template"}) {
+		t.Fatalf("unexpected hidden result: %v", texts)
+	}
+
+	inputs := cssxApplyCall(cssxcommon.ExpressionVisible, nil, []any{cssxQueryAll(root, "input")}, nil)
+	if len(cssxToArray(inputs)) != 0 {
+		t.Fatalf("expected hidden inputs to be filtered out, got %v", inputs)
+	}
+}
+
 func TestCSSXSelectionModel(t *testing.T) {
 	root := mustSelection(t, `<div>
 		<section><p>A</p><p>B</p></section>
@@ -221,6 +251,94 @@ func TestCSSXSelectionModel(t *testing.T) {
 	}
 }
 
+func TestCSSXTable(t *testing.T) {
+	root := mustSelection(t, `<div>
+		<table class="prices">
+			<thead>
+				<tr><th rowspan="2">Item</th><th colspan="2">Price</th></tr>
+				<tr><th>USD</th><th>EUR</th></tr>
+			</thead>
+			<tbody>
+				<tr><td>Apple</td><td>1</td><td>0.9</td></tr>
+				<tr><td rowspan="2">Pear</td><td colspan="2">n/a</td></tr>
+				<tr><td>2</td></tr>
+			</tbody>
+		</table>
+		<table class="plain">
+			<tr><td>a</td><td>b</td></tr>
+			<tr><td>c</td></tr>
+		</table>
+		<table class="named">
+			<tr><th>Name</th><th></th><th>Name</th></tr>
+			<tr><td>x</td><td>y</td><td>z</td></tr>
+		</table>
+	</div>`)
+
+	cases := []struct {
+		name     string
+		selector string
+		args     []any
+		want     any
+	}{
+		{
+			name:     "thead with spans",
+			selector: ".prices",
+			want: []any{
+				map[string]any{"Item": "Apple", "Price USD": "1", "Price EUR": "0.9"},
+				map[string]any{"Item": "Pear", "Price USD": "n/a", "Price EUR": "n/a"},
+				map[string]any{"Item": "Pear", "Price USD": "2", "Price EUR": nil},
+			},
+		},
+		{
+			name:     "explicit header row",
+			selector: ".prices",
+			args:     []any{float64(1)},
+			want: []any{
+				map[string]any{"Item": "Apple", "USD": "1", "EUR": "0.9"},
+				map[string]any{"Item": "Pear", "USD": "n/a", "EUR": "n/a"},
+				map[string]any{"Item": "Pear", "USD": "2", "EUR": nil},
+			},
+		},
+		{
+			name:     "without header",
+			selector: ".plain",
+			want:     []any{[]any{"a", "b"}, []any{"c", nil}},
+		},
+		{
+			name:     "disabled header",
+			selector: ".named",
+			args:     []any{float64(-1)},
+			want:     []any{[]any{"Name", "", "Name"}, []any{"x", "y", "z"}},
+		},
+		{
+			name:     "header row of th cells",
+			selector: ".named",
+			want:     []any{map[string]any{"Name": "x", "column2": "y", "Name_2": "z"}},
+		},
+		{
+			name:     "header row out of range",
+			selector: ".plain",
+			args:     []any{float64(5)},
+			want:     []any{},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := firstValue(cssxApplyCall(cssxcommon.ExpressionTable, tc.args, []any{cssxQueryAll(root, tc.selector)}, nil))
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("unexpected table result: %#v", got)
+			}
+		})
+	}
+
+	if got := firstValue(cssxApplyCall(cssxcommon.ExpressionTable, nil, []any{cssxQueryAll(root, "div")}, nil)); got != nil {
+		t.Fatalf("expected NONE for non-table input, got %#v", got)
+	}
+}
+
 func mustDocument(t *testing.T, input string) *goquery.Document {
 	t.Helper()
 
@@ -0,0 +1,277 @@
+package memory
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	cssxTableMaxColSpan = 1000
+	cssxTableMaxRowSpan = 65534
+)
+
+var cssxTableDigits = regexp.MustCompile(`^\s*(\d+)`)
+
+type cssxTableGrid struct {
+	rows      [][]any
+	headRows  int
+	firstIsTh bool
+}
+
+// cssxTable extracts the rows of a table element.
+// Without a header the result is an array of row arrays, otherwise an array of objects keyed by header text.
+// The header is the thead rows or a leading row of th cells unless an explicit header row index is given;
+// a negative index disables the header.
+func cssxTable(node *html.Node, args []any) any {
+	if node == nil || node.Type != html.ElementNode || node.Data != "table" {
+		return nil
+	}
+
+	grid := cssxTableGridOf(node)
+	rows := grid.rows
+	header := 0
+
+	if len(args) > 0 {
+		index, ok := cssxToInt(cssxArgNumber(args, 0))
+		if !ok {
+			return nil
+		}
+
+		if index >= 0 {
+			if index >= len(rows) {
+				return []any{}
+			}
+
+			rows = rows[index:]
+			header = 1
+		}
+	} else if grid.headRows > 0 {
+		header = grid.headRows
+	} else if grid.firstIsTh {
+		header = 1
+	}
+
+	width := 0
+
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+
+	out := make([]any, 0, len(rows)-header)
+
+	if header == 0 {
+		for _, row := range rows {
+			out = append(out, cssxTablePad(row, width))
+		}
+
+		return out
+	}
+
+	keys := cssxTableKeys(rows[:header], width)
+
+	for _, row := range rows[header:] {
+		item := make(map[string]any, width)
+
+		for col, value := range cssxTablePad(row, width) {
+			item[keys[col]] = value
+		}
+
+		out = append(out, item)
+	}
+
+	return out
+}
+
+// cssxTableGridOf expands the cells of every row group into a grid of normalized cell texts.
+// Cells spanning several rows or columns repeat their text in each covered slot; row spans do not
+// extend past their row group, and a row span of 0 covers the rest of the group.
+func cssxTableGridOf(table *html.Node) cssxTableGrid {
+	var head, foot []*html.Node
+	var body [][]*html.Node
+
+	bodyRows := -1
+
+	for _, child := range cssxElementChildren(table) {
+		switch child.Data {
+		case "thead":
+			head = append(head, cssxTableChildren(child, "tr")...)
+		case "tfoot":
+			foot = append(foot, cssxTableChildren(child, "tr")...)
+		case "tbody":
+			body = append(body, cssxTableChildren(child, "tr"))
+			bodyRows = -1
+		case "tr":
+			// consecutive rows outside of sections form an implicit body
+			if bodyRows < 0 {
+				body = append(body, nil)
+				bodyRows = len(body) - 1
+			}
+
+			body[bodyRows] = append(body[bodyRows], child)
+		}
+	}
+
+	headRows := cssxTableGroup(head)
+	grid := cssxTableGrid{rows: headRows, headRows: len(headRows)}
+
+	for _, group := range append(body, foot) {
+		grid.rows = append(grid.rows, cssxTableGroup(group)...)
+	}
+
+	for _, group := range append(append([][]*html.Node{head}, body...), foot) {
+		if len(group) == 0 {
+			continue
+		}
+
+		cells := cssxTableCells(group[0])
+		grid.firstIsTh = len(cells) > 0
+
+		for _, cell := range cells {
+			if cell.Data != "th" {
+				grid.firstIsTh = false
+			}
+		}
+
+		break
+	}
+
+	return grid
+}
+
+func cssxTableGroup(rows []*html.Node) [][]any {
+	grid := make([][]any, len(rows))
+
+	for r, row := range rows {
+		col := 0
+
+		for _, cell := range cssxTableCells(row) {
+			for col < len(grid[r]) && grid[r][col] != nil {
+				col++
+			}
+
+			colSpan := min(max(cssxTableSpan(cell, "colspan", 1), 1), cssxTableMaxColSpan)
+			rowSpan := min(cssxTableSpan(cell, "rowspan", 1), cssxTableMaxRowSpan)
+
+			if rowSpan == 0 {
+				rowSpan = len(rows) - r
+			}
+
+			text := cssxNormalizeSpace(cssxTextContent(cell))
+
+			for dr := 0; dr < rowSpan && r+dr < len(rows); dr++ {
+				target := grid[r+dr]
+
+				for len(target) < col+colSpan {
+					target = append(target, nil)
+				}
+
+				for dc := 0; dc < colSpan; dc++ {
+					target[col+dc] = text
+				}
+
+				grid[r+dr] = target
+			}
+
+			col += colSpan
+		}
+	}
+
+	out := make([][]any, 0, len(grid))
+
+	for _, row := range grid {
+		if len(row) > 0 {
+			out = append(out, row)
+		}
+	}
+
+	return out
+}
+
+// cssxTableKeys names the columns after the header rows.
+// Texts repeated by spans are merged, empty names become columnN and duplicates get a _N suffix.
+func cssxTableKeys(header [][]any, width int) []string {
+	keys := make([]string, width)
+	seen := make(map[string]int, width)
+
+	for col := range keys {
+		parts := make([]string, 0, len(header))
+
+		for _, row := range header {
+			text, _ := cssxTablePad(row, width)[col].(string)
+
+			if text != "" && (len(parts) == 0 || parts[len(parts)-1] != text) {
+				parts = append(parts, text)
+			}
+		}
+
+		key := strings.Join(parts, " ")
+
+		if key == "" {
+			key = "column" + strconv.Itoa(col+1)
+		}
+
+		seen[key]++
+
+		if seen[key] > 1 {
+			key += "_" + strconv.Itoa(seen[key])
+		}
+
+		keys[col] = key
+	}
+
+	return keys
+}
+
+func cssxTablePad(row []any, width int) []any {
+	out := make([]any, width)
+	copy(out, row)
+
+	return out
+}
+
+func cssxTableChildren(node *html.Node, name string) []*html.Node {
+	out := make([]*html.Node, 0)
+
+	for _, child := range cssxElementChildren(node) {
+		if child.Data == name {
+			out = append(out, child)
+		}
+	}
+
+	return out
+}
+
+func cssxTableCells(row *html.Node) []*html.Node {
+	out := make([]*html.Node, 0)
+
+	for _, child := range cssxElementChildren(row) {
+		if child.Data == "td" || child.Data == "th" {
+			out = append(out, child)
+		}
+	}
+
+	return out
+}
+
+// cssxTableSpan parses a span attribute the way browsers do, reading its leading digits.
+func cssxTableSpan(cell *html.Node, name string, fallback int) int {
+	value, ok := cssxNodeAttr(cell, name)
+	if !ok {
+		return fallback
+	}
+
+	match := cssxTableDigits.FindStringSubmatch(value)
+	if match == nil {
+		return fallback
+	}
+
+	span, err := strconv.Atoi(match[1])
+	if err != nil {
+		return fallback
+	}
+
+	return span
+}
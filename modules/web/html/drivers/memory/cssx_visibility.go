@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// cssxHiddenElements are never rendered by browsers.
+var cssxHiddenElements = map[atom.Atom]bool{
+	atom.Head:     true,
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Template: true,
+	atom.Noscript: true,
+	atom.Title:    true,
+	atom.Meta:     true,
+	atom.Link:     true,
+	atom.Base:     true,
+}
+
+func cssxApplyVisibility(visible bool, input any) []any {
+	out := make([]any, 0)
+
+	for _, node := range cssxToNodes(input) {
+		if cssxNodeVisible(node) == visible {
+			out = append(out, node)
+		}
+	}
+
+	return out
+}
+
+// cssxNodeVisible approximates visibility without a layout engine.
+// An element is hidden by a non-rendered tag, the hidden attribute, a hidden input or an inline
+// display: none or opacity: 0 on itself or an ancestor, and by the nearest inline visibility declaration.
+func cssxNodeVisible(node *html.Node) bool {
+	if node == nil || node.Type != html.ElementNode {
+		return false
+	}
+
+	if node.DataAtom == atom.Input {
+		if kind, ok := cssxNodeAttr(node, "type"); ok && strings.EqualFold(strings.TrimSpace(kind), "hidden") {
+			return false
+		}
+	}
+
+	visibility := ""
+
+	for current := node; current != nil && current.Type == html.ElementNode; current = current.Parent {
+		if cssxHiddenElements[current.DataAtom] || cssxNodeHasAttr(current, "hidden") {
+			return false
+		}
+
+		style := cssxInlineStyle(current)
+
+		if style["display"] == "none" || style["opacity"] == "0" {
+			return false
+		}
+
+		if value, ok := style["visibility"]; ok && visibility == "" {
+			visibility = value
+		}
+	}
+
+	return visibility != "hidden" && visibility != "collapse"
+}
+
+func cssxInlineStyle(node *html.Node) map[string]string {
+	value, ok := cssxNodeAttr(node, "style")
+	if !ok {
+		return nil
+	}
+
+	out := make(map[string]string)
+
+	for _, declaration := range strings.Split(value, ";") {
+		name, val, found := strings.Cut(declaration, ":")
+		if !found {
+			continue
+		}
+
+		val = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(val), "!important"))
+		out[strings.ToLower(strings.TrimSpace(name))] = strings.ToLower(val)
+	}
+
+	return out
+}
@@ -218,6 +218,10 @@ func (doc *HTMLDocument) XPath(ctx context.Context, expression runtime.String) (
 	return doc.element.XPath(ctx, expression)
 }
 
+func (doc *HTMLDocument) XPathWithOptions(ctx context.Context, expression runtime.String, options drivers.XPathOptions) (runtime.Value, error) {
+	return doc.element.XPathWithOptions(ctx, expression, options)
+}
+
 func (doc *HTMLDocument) GetTitle() runtime.String {
 	title := doc.doc.Find("head > title")
 
@@ -271,23 +275,28 @@ func (doc *HTMLDocument) baseURL() (*neturl.URL, error) {
 		return nil, runtime.Error(err, "invalid document URL")
 	}
 
-	base := doc.doc.Find("base[href]").First()
+	return resolveBaseURL(doc.doc, fallback), nil
+}
+
+// resolveBaseURL applies the first <base href> of a document to its URL.
+func resolveBaseURL(doc *goquery.Document, fallback *neturl.URL) *neturl.URL {
+	base := doc.Find("base[href]").First()
 	if base.Length() == 0 {
-		return fallback, nil
+		return fallback
 	}
 
 	href, _ := base.Attr("href")
 	ref, err := neturl.Parse(strings.TrimSpace(href))
 	if err != nil {
-		return fallback, nil
+		return fallback
 	}
 
 	resolved := fallback.ResolveReference(ref)
 	switch strings.ToLower(resolved.Scheme) {
 	case "data", "javascript":
-		return fallback, nil
+		return fallback
 	default:
-		return resolved, nil
+		return resolved
 	}
 }
 
@@ -10,6 +10,7 @@ import (
 	"github.com/gobwas/glob"
 
 	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/contrib/modules/web/html/drivers/internal/archive"
 	"github.com/MontFerret/contrib/modules/web/html/internal/useragent"
 	"github.com/MontFerret/ferret/v2/pkg/logging"
 	"github.com/MontFerret/ferret/v2/pkg/runtime"
@@ -25,14 +26,28 @@ import (
 const DriverName = "memory"
 
 type Driver struct {
-	client  *pester.Client
-	options *Options
+	client     *pester.Client
+	cache      *httpCache
+	politeness *politeness
+	proxies    *proxyPool
+	options    *Options
 }
 
 func New(opts ...Option) *Driver {
 	drv := new(Driver)
 	drv.options = NewOptions(opts)
 	drv.client = newHTTPClient(drv.options)
+	drv.proxies = newProxyPool(drv.options)
+	drv.cache = newHTTPCache(drv.options)
+	drv.politeness = newPoliteness(drv.options)
+
+	if drv.proxies != nil {
+		setProxy(drv.client, drv.proxies.proxyURL)
+	}
+
+	if drv.politeness != nil {
+		drv.client.CheckRedirect = drv.politeness.checkRedirect
+	}
 
 	return drv
 }
@@ -69,17 +84,19 @@ func addProxy(httpClient *pester.Client, proxyStr string) error {
 		return err
 	}
 
-	proxy := http.ProxyURL(proxyURL)
+	setProxy(httpClient, http.ProxyURL(proxyURL))
 
+	return nil
+}
+
+func setProxy(httpClient *pester.Client, proxy func(*http.Request) (*url.URL, error)) {
 	if httpClient.Transport != nil {
 		httpClient.Transport.(*http.Transport).Proxy = proxy
 
-		return nil
+		return
 	}
 
 	httpClient.Transport = &http.Transport{Proxy: proxy}
-
-	return nil
 }
 
 func (drv *Driver) Name() string {
@@ -91,15 +108,35 @@ func (drv *Driver) Open(ctx context.Context, params drivers.Params) (drivers.HTM
 		return nil, runtime.Error(runtime.ErrNotSupported, "initScript is only supported by the CDP driver")
 	}
 
+	if params.Stealth != nil && !params.Stealth.Disabled {
+		return nil, runtime.Error(runtime.ErrNotSupported, "stealth is only supported by the CDP driver")
+	}
+
+	if params.Proxy != "" {
+		return nil, runtime.Error(runtime.ErrNotSupported, "proxy is only supported by the CDP driver, use a proxy pool instead")
+	}
+
 	req, err := http.NewRequest(http.MethodGet, params.URL, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	params = drivers.SetDefaultParams(drv.options.Options, params)
+
+	var jar *drivers.CookieJar
+
+	if drv.options.CookieJar != "" {
+		jar, err = drivers.OpenCookieJar(ctx, drv.options.CookieJar)
+		if err != nil {
+			return nil, err
+		}
+
+		params = jar.SetDefaultParams(params)
+	}
+
 	req = drv.makeRequest(ctx, req, params)
 
-	resp, err := drv.client.Do(req)
+	resp, err := drv.do(ctx, req)
 	if err != nil {
 		return nil, runtime.Errorf(err, "failed to retrieve a document %s", params.URL)
 	}
@@ -139,7 +176,18 @@ func (drv *Driver) Open(ctx context.Context, params drivers.Params) (drivers.HTM
 		Headers:    drivers.NewHTTPHeadersWith(resp.Header),
 	}
 
-	return NewHTMLPage(doc, params.URL, r, cookies)
+	page, err := NewHTMLPage(doc, params.URL, r, cookies)
+	if err != nil {
+		return nil, err
+	}
+
+	page.fetch = func(ctx context.Context, url string) (*archive.Resource, error) {
+		return drv.fetchResource(ctx, url, params)
+	}
+	page.jar = jar
+	page.logger = logging.From(ctx)
+
+	return page, nil
 }
 
 func (drv *Driver) Parse(_ context.Context, params drivers.ParseParams) (drivers.HTMLPage, error) {
@@ -160,6 +208,30 @@ func (drv *Driver) Close() error {
 	return nil
 }
 
+func (drv *Driver) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if drv.cache == nil {
+		return drv.fetch(req)
+	}
+
+	return drv.cache.do(ctx, req, drv.fetch)
+}
+
+func (drv *Driver) fetch(req *http.Request) (*http.Response, error) {
+	if drv.politeness == nil {
+		return drv.send(req)
+	}
+
+	return drv.politeness.do(req, drv.send)
+}
+
+func (drv *Driver) send(req *http.Request) (*http.Response, error) {
+	if drv.proxies == nil {
+		return drv.client.Do(req)
+	}
+
+	return drv.proxies.do(req, drv.client.Do)
+}
+
 func (drv *Driver) responseCodeAllowed(resp *http.Response, additional []drivers.StatusCodeFilter) bool {
 	var allowed bool
 	reqURL := resp.Request.URL.String()
@@ -215,8 +287,12 @@ func (drv *Driver) makeRequest(ctx context.Context, req *http.Request, params dr
 
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,image/apng,*/*;q=0.8")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.9,ru;q=0.8")
-	req.Header.Set("Cache-Control", "no-cache")
-	req.Header.Set("Pragma", "no-cache")
+
+	// the defaults would force revalidation of every cached response
+	if drv.cache == nil {
+		req.Header.Set("Cache-Control", "no-cache")
+		req.Header.Set("Pragma", "no-cache")
+	}
 
 	if params.Headers != nil {
 		headersData := params.Headers.Data
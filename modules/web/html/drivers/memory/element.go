@@ -194,6 +194,12 @@ func (el *HTMLElement) GetDOMProperty(ctx context.Context, name runtime.String)
 		return el.getReflectedAttribute("class"), nil
 	case "htmlFor":
 		return el.getReflectedAttribute("for"), nil
+	case "baseURI":
+		if el.doc == nil || el.doc.Url == nil {
+			return runtime.None, nil
+		}
+
+		return runtime.NewString(resolveBaseURL(el.doc, el.doc.Url).String()), nil
 	}
 
 	if value, ok := el.selection.Attr(prop); ok {
@@ -550,6 +556,15 @@ func (el *HTMLElement) XPath(_ context.Context, expression runtime.String) (runt
 	return EvalXPathTo(el.doc, el.selection, expression.String())
 }
 
+func (el *HTMLElement) XPathWithOptions(_ context.Context, expression runtime.String, options drivers.XPathOptions) (runtime.Value, error) {
+	options, err := drivers.NormalizeXPathOptions(options)
+	if err != nil {
+		return runtime.None, err
+	}
+
+	return EvalXPathToWithNamespaces(el.doc, el.selection, expression.String(), options.Namespaces)
+}
+
 func (el *HTMLElement) SetInnerHTMLBySelector(ctx context.Context, selector drivers.QuerySelector, innerHTML runtime.String) error {
 	if selector.Kind == drivers.CSSSelector {
 		selection := el.selection.Find(selector.String())
@@ -0,0 +1,197 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+const formControlSelector = "input, select, textarea"
+
+// FillForm sets the values of the controls contained in the element for later serialization.
+// Forms cannot be submitted without a browser.
+func (el *HTMLElement) FillForm(ctx context.Context, fields []drivers.FormField, options drivers.FormFillOptions) (drivers.FormFillResult, error) {
+	var result drivers.FormFillResult
+
+	if options.Submit {
+		return result, runtime.Error(runtime.ErrNotSupported, "form submission")
+	}
+
+	for _, field := range fields {
+		controls := el.findFormControls(field.Name.String())
+
+		if controls.Length() == 0 {
+			result.Unmatched = append(result.Unmatched, field.Name)
+
+			continue
+		}
+
+		for i := range controls.Nodes {
+			if err := el.fillFormControl(ctx, controls.Eq(i), field.Value); err != nil {
+				return result, err
+			}
+		}
+
+		result.Filled = append(result.Filled, field.Name)
+	}
+
+	return result, nil
+}
+
+// findFormControls returns the controls addressed by a field name, id, label text or CSS selector, in that order.
+func (el *HTMLElement) findFormControls(field string) *goquery.Selection {
+	controls := el.selection.Find(formControlSelector)
+
+	byName := controls.FilterFunction(func(_ int, control *goquery.Selection) bool {
+		name, ok := control.Attr("name")
+
+		return ok && name == field
+	})
+
+	if byName.Length() > 0 {
+		return byName
+	}
+
+	byID := controls.FilterFunction(func(_ int, control *goquery.Selection) bool {
+		id, ok := control.Attr("id")
+
+		return ok && id == field
+	})
+
+	if byID.Length() > 0 {
+		return byID
+	}
+
+	label := normalizeFormLabel(field)
+	labeled := make([]*html.Node, 0)
+
+	el.selection.Find("label").Each(func(_ int, node *goquery.Selection) {
+		if normalizeFormLabel(node.Text()) != label {
+			return
+		}
+
+		target := node.Find(formControlSelector).First()
+
+		if id, ok := node.Attr("for"); ok {
+			target = controls.FilterFunction(func(_ int, control *goquery.Selection) bool {
+				controlID, ok := control.Attr("id")
+
+				return ok && controlID == id
+			}).First()
+		}
+
+		labeled = append(labeled, target.Nodes...)
+	})
+
+	byLabel := controls.FilterNodes(labeled...)
+
+	if byLabel.Length() > 0 {
+		return byLabel
+	}
+
+	return el.selection.Find(field).Filter(formControlSelector)
+}
+
+func (el *HTMLElement) fillFormControl(ctx context.Context, control *goquery.Selection, value runtime.Value) error {
+	switch formControlKind(control) {
+	case "checkbox", "radio":
+		controlValue, ok := control.Attr("value")
+		if !ok {
+			controlValue = "on"
+		}
+
+		checked, err := drivers.FormChecked(ctx, value, controlValue)
+		if err != nil {
+			return err
+		}
+
+		if formControlKind(control) == "checkbox" {
+			if checked {
+				control.SetAttr("checked", "")
+			} else {
+				control.RemoveAttr("checked")
+			}
+
+			return nil
+		}
+
+		// radio buttons are unchecked by checking another button of the group
+		if checked {
+			name, _ := control.Attr("name")
+
+			el.selection.Find("input").FilterFunction(func(_ int, other *goquery.Selection) bool {
+				otherName, _ := other.Attr("name")
+
+				return formControlKind(other) == "radio" && otherName == name
+			}).RemoveAttr("checked")
+
+			control.SetAttr("checked", "")
+		}
+
+		return nil
+	case "select":
+		values, err := drivers.FormValueStrings(ctx, value)
+		if err != nil {
+			return err
+		}
+
+		_, multiple := control.Attr("multiple")
+		found := false
+
+		control.Find("option").Each(func(_ int, option *goquery.Selection) {
+			optionValue, ok := option.Attr("value")
+			if !ok {
+				optionValue = strings.TrimSpace(option.Text())
+			}
+
+			if slices.Contains(values, optionValue) && (multiple || !found) {
+				option.SetAttr("selected", "")
+				found = true
+			} else {
+				option.RemoveAttr("selected")
+			}
+		})
+
+		return nil
+	case "textarea":
+		control.SetText(formText(value))
+
+		return nil
+	default:
+		control.SetAttr("value", formText(value))
+
+		return nil
+	}
+}
+
+func formControlKind(control *goquery.Selection) string {
+	name := goquery.NodeName(control)
+
+	if name != "input" {
+		return name
+	}
+
+	kind, _ := control.Attr("type")
+
+	return strings.ToLower(strings.TrimSpace(kind))
+}
+
+func formText(value runtime.Value) string {
+	if value == nil || value == runtime.None {
+		return ""
+	}
+
+	return value.String()
+}
+
+func normalizeFormLabel(text string) string {
+	text = strings.TrimRight(text, " \t\r\n*:")
+
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
@@ -0,0 +1,141 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/contrib/modules/web/html/drivers/memory"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestElementFillForm(t *testing.T) {
+	ctx := context.Background()
+
+	newForm := func() (*goquery.Document, drivers.FormTarget) {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(`
+			<html>
+				<body>
+					<form id="signup">
+						<input name="email" value="old@example.com" />
+						<label for="nick">Nickname:</label>
+						<input id="nick" />
+						<label>Bio <textarea name="about"></textarea></label>
+						<input type="date" id="born" />
+						<input type="checkbox" name="terms" />
+						<input type="checkbox" name="topics" value="go" />
+						<input type="checkbox" name="topics" value="js" checked />
+						<input type="radio" name="plan" value="free" checked />
+						<input type="radio" name="plan" value="pro" />
+						<select name="country"><option value="de">DE</option><option value="fr" selected>FR</option></select>
+						<select name="langs" multiple><option>en</option><option>de</option><option>fr</option></select>
+					</form>
+				</body>
+			</html>
+		`))
+
+		So(err, ShouldBeNil)
+
+		el, err := memory.NewHTMLElement(doc, doc.Find("#signup"))
+		So(err, ShouldBeNil)
+
+		target, err := drivers.ToFormTarget(el)
+		So(err, ShouldBeNil)
+
+		return doc, target
+	}
+
+	fields := func(values map[string]runtime.Value) []drivers.FormField {
+		out, err := drivers.NewFormFields(ctx, runtime.NewObjectWith(values))
+		So(err, ShouldBeNil)
+
+		return out
+	}
+
+	Convey(".FillForm", t, func() {
+		Convey("Should match fields by name, id, label text and selector", func() {
+			doc, target := newForm()
+
+			result, err := target.FillForm(ctx, fields(map[string]runtime.Value{
+				"email":           runtime.NewString("new@example.com"),
+				"Nickname":        runtime.NewString("gopher"),
+				"bio":             runtime.NewString("Hello"),
+				"input[id=born]":  runtime.NewString("2000-01-02"),
+				"missing":         runtime.NewString("value"),
+				"[name=nickname]": runtime.NewString("value"),
+			}), drivers.FormFillOptions{})
+
+			So(err, ShouldBeNil)
+			So(result.Filled, ShouldResemble, []runtime.String{"Nickname", "bio", "email", "input[id=born]"})
+			So(result.Unmatched, ShouldResemble, []runtime.String{"[name=nickname]", "missing"})
+
+			So(doc.Find("[name=email]").AttrOr("value", ""), ShouldEqual, "new@example.com")
+			So(doc.Find("#nick").AttrOr("value", ""), ShouldEqual, "gopher")
+			So(doc.Find("textarea").Text(), ShouldEqual, "Hello")
+			So(doc.Find("#born").AttrOr("value", ""), ShouldEqual, "2000-01-02")
+		})
+
+		Convey("Should check boxes, radio buttons and select options", func() {
+			doc, target := newForm()
+
+			_, err := target.FillForm(ctx, fields(map[string]runtime.Value{
+				"terms":   runtime.True,
+				"topics":  runtime.NewArrayWith(runtime.NewString("go")),
+				"plan":    runtime.NewString("pro"),
+				"country": runtime.NewString("de"),
+				"langs":   runtime.NewArrayWith(runtime.NewString("en"), runtime.NewString("fr")),
+			}), drivers.FormFillOptions{})
+
+			So(err, ShouldBeNil)
+
+			checked := func(selector string) bool {
+				_, ok := doc.Find(selector).Attr("checked")
+
+				return ok
+			}
+
+			So(checked("[name=terms]"), ShouldBeTrue)
+			So(checked("[value=go]"), ShouldBeTrue)
+			So(checked("[value=js]"), ShouldBeFalse)
+			So(checked("[value=pro]"), ShouldBeTrue)
+			So(checked("[value=free]"), ShouldBeFalse)
+			So(doc.Find("[name=country] option[selected]").Text(), ShouldEqual, "DE")
+			So(doc.Find("[name=langs] option[selected]").Length(), ShouldEqual, 2)
+		})
+
+		Convey("Should fill ordered fields in their own order", func() {
+			doc, target := newForm()
+
+			fields, err := drivers.NewOrderedFormFields(ctx, runtime.NewArrayWith(
+				runtime.NewObjectWith(map[string]runtime.Value{"name": runtime.NewString("plan"), "value": runtime.NewString("pro")}),
+				runtime.NewObjectWith(map[string]runtime.Value{"name": runtime.NewString("email"), "value": runtime.NewString("new@example.com")}),
+			))
+			So(err, ShouldBeNil)
+
+			result, err := target.FillForm(ctx, fields, drivers.FormFillOptions{})
+
+			So(err, ShouldBeNil)
+			So(result.Filled, ShouldResemble, []runtime.String{"plan", "email"})
+			So(doc.Find("[name=email]").AttrOr("value", ""), ShouldEqual, "new@example.com")
+
+			_, err = drivers.NewOrderedFormFields(ctx, runtime.NewArrayWith(runtime.NewObjectWith(map[string]runtime.Value{
+				"value": runtime.NewString("pro"),
+			})))
+			So(errors.Is(err, runtime.ErrInvalidArgument), ShouldBeTrue)
+		})
+
+		Convey("Should not submit forms", func() {
+			_, target := newForm()
+
+			_, err := target.FillForm(ctx, nil, drivers.FormFillOptions{Submit: true})
+
+			So(errors.Is(err, runtime.ErrNotSupported), ShouldBeTrue)
+		})
+	})
+}
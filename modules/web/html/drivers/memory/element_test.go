@@ -521,5 +521,67 @@ func TestElement(t *testing.T) {
 			So(err, ShouldBeNil)
 			So(runtime.TypeOf(first).String(), ShouldEqual, drivers.HTMLElementType.String())
 		})
+
+		Convey("Namespaces", func() {
+			buff := bytes.NewBuffer([]byte(`<!DOCTYPE html><html xmlns="http://www.w3.org/1999/xhtml"><body>
+				<div xmlns:feed="urn:vendor:feed"><feed:item><feed:title>One</feed:title></feed:item><feed:item><feed:title>Two</feed:title></feed:item></div>
+				<svg><rect width="10"/></svg>
+			</body></html>`))
+			godoc, err := goquery.NewDocumentFromReader(buff)
+			So(err, ShouldBeNil)
+
+			doc, err := memory.NewRootHTMLDocument(godoc, "localhost:9090")
+			So(err, ShouldBeNil)
+
+			nt, err := doc.XPathWithOptions(ctx, runtime.NewString("//v:item/v:title/text()"), drivers.XPathOptions{
+				Namespaces: map[string]string{"v": "urn:vendor:feed"},
+			})
+
+			So(err, ShouldBeNil)
+			So(nt.String(), ShouldEqual, `["One","Two"]`)
+
+			nt, err = doc.XPathWithOptions(ctx, runtime.NewString("count(//s:rect) + count(//h:body)"), drivers.XPathOptions{
+				Namespaces: map[string]string{"s": "http://www.w3.org/2000/svg", "h": "http://www.w3.org/1999/xhtml"},
+			})
+
+			So(err, ShouldBeNil)
+			So(nt, ShouldEqual, runtime.NewFloat(2))
+
+			nt, err = doc.XPath(ctx, runtime.NewString("count(//feed:item)"))
+
+			So(err, ShouldBeNil)
+			So(nt, ShouldEqual, runtime.NewFloat(2))
+
+			_, err = doc.XPathWithOptions(ctx, runtime.NewString("//x:item"), drivers.XPathOptions{
+				Namespaces: map[string]string{"v": "urn:vendor:feed"},
+			})
+
+			So(err, ShouldNotBeNil)
+
+			_, err = doc.XPathWithOptions(ctx, runtime.NewString("//item"), drivers.XPathOptions{
+				Namespaces: map[string]string{"v:x": "urn:vendor:feed"},
+			})
+
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Extended functions", func() {
+			buff := bytes.NewBuffer([]byte(`<!DOCTYPE html><body><ul><li>Alpha</li><li>beta</li><li>Gamma</li></ul></body></html>`))
+			godoc, err := goquery.NewDocumentFromReader(buff)
+			So(err, ShouldBeNil)
+
+			doc, err := memory.NewRootHTMLDocument(godoc, "localhost:9090")
+			So(err, ShouldBeNil)
+
+			nt, err := doc.XPathWithOptions(ctx, runtime.NewString("string-join(//li[matches(., '^[A-Z]')]/text(), ', ')"), drivers.XPathOptions{})
+
+			So(err, ShouldBeNil)
+			So(nt.String(), ShouldEqual, "Alpha, Gamma")
+
+			nt, err = doc.XPathWithOptions(ctx, runtime.NewString("//li[lower-case(.) = 'beta']"), drivers.XPathOptions{})
+
+			So(err, ShouldBeNil)
+			So(nt.(*runtime.Array).Length(ctx), ShouldEqual, runtime.NewInt(1))
+		})
 	})
 }
@@ -5,8 +5,10 @@ import (
 	"hash/fnv"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/rs/zerolog"
 
 	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/contrib/modules/web/html/drivers/internal/archive"
 	"github.com/MontFerret/contrib/modules/web/html/drivers/internal/data"
 	"github.com/MontFerret/contrib/modules/web/html/drivers/internal/frameutil"
 	"github.com/MontFerret/ferret/v2/pkg/runtime"
@@ -15,7 +17,10 @@ import (
 type HTMLPage struct {
 	document *HTMLDocument
 	cookies  *drivers.HTTPCookies
+	jar      *drivers.CookieJar
+	logger   zerolog.Logger
 	frames   *runtime.Array
+	fetch    archive.Fetcher
 	response drivers.HTTPResponse
 }
 
@@ -129,6 +134,10 @@ func (p *HTMLPage) Copy() runtime.Value {
 		return runtime.None
 	}
 
+	page.fetch = p.fetch
+	page.jar = p.jar
+	page.logger = p.logger
+
 	return page
 }
 
@@ -145,6 +154,21 @@ func (p *HTMLPage) Length(ctx context.Context) (runtime.Int, error) {
 }
 
 func (p *HTMLPage) Close() error {
+	if p.jar == nil {
+		return nil
+	}
+
+	url := p.document.GetURL().String()
+
+	// like the CDP driver, a jar that cannot be saved does not fail closing the page
+	if err := p.jar.Save(url, p.cookies); err != nil {
+		p.logger.Warn().
+			Str("url", url).
+			Str("cookie_jar", p.jar.Path()).
+			Err(err).
+			Msg("failed to save cookie jar")
+	}
+
 	return nil
 }
 
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/contrib/modules/web/html/drivers/internal/archive"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+// Archive captures the page with the resources it references.
+// Stylesheets, images and frames are fetched with the driver that opened the page,
+// parsed pages keep absolute references to them instead.
+func (p *HTMLPage) Archive(ctx context.Context, params drivers.ArchiveParams) (runtime.Binary, error) {
+	markup, err := goquery.OuterHtml(p.document.doc.Selection)
+	if err != nil {
+		return runtime.NewBinary(nil), err
+	}
+
+	doc, err := html.Parse(strings.NewReader(markup))
+	if err != nil {
+		return runtime.NewBinary(nil), err
+	}
+
+	fetch := p.fetch
+	if fetch == nil {
+		fetch = func(_ context.Context, url string) (*archive.Resource, error) {
+			return nil, runtime.Errorf(runtime.ErrNotSupported, "fetch %s outside of a driver", url)
+		}
+	}
+
+	var out []byte
+
+	switch params.Format {
+	case drivers.ArchiveFormatHTML:
+		out, err = archive.HTML(ctx, doc, p.document.GetURL().String(), fetch)
+	case drivers.ArchiveFormatMHTML, "":
+		out, err = archive.MHTML(ctx, doc, p.document.GetURL().String(), fetch)
+	default:
+		return runtime.NewBinary(nil), runtime.Errorf(runtime.ErrInvalidArgument, "archive format %q", params.Format)
+	}
+
+	if err != nil {
+		return runtime.NewBinary(nil), err
+	}
+
+	return runtime.NewBinary(out), nil
+}
+
+// fetchResource loads a page resource with the headers, cookies and user agent of the page request.
+func (drv *Driver) fetchResource(ctx context.Context, url string, params drivers.Params) (*archive.Resource, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req = drv.makeRequest(ctx, req, params)
+	req.Header.Set("Accept", "*/*")
+
+	resp, err := drv.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("fetch %s: %s", url, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// relative references of the resource resolve against its final URL
+	if resp.Request != nil {
+		url = resp.Request.URL.String()
+	}
+
+	return &archive.Resource{
+		URL:         url,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        body,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+)
+
+const archivedPage = `<!DOCTYPE html><html><head><link rel="stylesheet" href="/site.css"></head>
+<body><img src="/logo.png"><iframe src="/frame.html"></iframe></body></html>`
+
+func TestHTMLPage_Archive(t *testing.T) {
+	Convey("Should inline resources fetched with the driver", t, func() {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("GET", "http://localhost:1111", httpmock.NewStringResponder(200, archivedPage))
+		httpmock.RegisterResponder("GET", "http://localhost:1111/site.css", func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(200, `body { background: url(bg.png) }`)
+			resp.Header.Set("Content-Type", "text/css")
+
+			return resp, nil
+		})
+		httpmock.RegisterResponder("GET", "http://localhost:1111/bg.png", httpmock.NewStringResponder(200, "bg"))
+		httpmock.RegisterResponder("GET", "http://localhost:1111/logo.png", func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(200, "logo")
+			resp.Header.Set("Content-Type", "image/png")
+
+			return resp, nil
+		})
+		httpmock.RegisterResponder("GET", "http://localhost:1111/frame.html",
+			httpmock.NewStringResponder(200, `<html><body><p>frame</p></body></html>`))
+
+		page, err := New().Open(context.Background(), drivers.Params{URL: "http://localhost:1111"})
+		So(err, ShouldBeNil)
+
+		out, err := page.(*HTMLPage).Archive(context.Background(), drivers.ArchiveParams{Format: drivers.ArchiveFormatHTML})
+		So(err, ShouldBeNil)
+
+		content := string(out)
+
+		So(content, ShouldContainSubstring, "<style>body { background: url(data:")
+		So(content, ShouldContainSubstring, `<img src="data:image/png;base64,bG9nbw=="/>`)
+		So(content, ShouldContainSubstring, "&lt;p&gt;frame&lt;/p&gt;")
+		So(strings.Contains(content, "<link"), ShouldBeFalse)
+
+		out, err = page.(*HTMLPage).Archive(context.Background(), drivers.ArchiveParams{Format: drivers.ArchiveFormatMHTML})
+		So(err, ShouldBeNil)
+
+		content = string(out)
+
+		So(content, ShouldContainSubstring, "Content-Type: multipart/related")
+		So(content, ShouldContainSubstring, "Content-Location: http://localhost:1111/logo.png")
+		So(content, ShouldContainSubstring, "Content-Location: http://localhost:1111/frame.html")
+	})
+
+	Convey("Should keep absolute references of parsed pages", t, func() {
+		page, err := New().Parse(context.Background(), drivers.ParseParams{Content: []byte(archivedPage)})
+		So(err, ShouldBeNil)
+
+		out, err := page.(*HTMLPage).Archive(context.Background(), drivers.ArchiveParams{Format: drivers.ArchiveFormatHTML})
+		So(err, ShouldBeNil)
+		So(string(out), ShouldContainSubstring, `<link rel="stylesheet" href="/site.css"/>`)
+	})
+}
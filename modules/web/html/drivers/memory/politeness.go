@@ -0,0 +1,449 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	robots "github.com/MontFerret/contrib/modules/web/robots/core"
+)
+
+const (
+	// maxRobotsSize is the amount of robots.txt content parsed per host, as required by RFC 9309.
+	maxRobotsSize = 500 * 1024
+
+	// maxRedirects is the redirect limit of the http client, kept when redirects are checked by the driver.
+	maxRedirects = 10
+)
+
+// ErrDisallowedByRobots is matched by errors returned for URLs excluded by robots.txt.
+var ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+
+// disallowAllRobots is applied to hosts whose robots.txt is unreachable.
+var disallowAllRobots = &robots.Document{
+	Groups: []robots.Group{{UserAgents: []string{"*"}, Disallow: []string{"/"}}},
+}
+
+type (
+	// RobotsError reports a URL rejected by the robots.txt rules of its host.
+	RobotsError struct {
+		URL       string
+		UserAgent string
+		Pattern   string
+	}
+
+	politeness struct {
+		hosts       map[string]*hostState
+		now         func() time.Time
+		userAgent   string
+		interval    time.Duration
+		mu          sync.Mutex
+		maxInFlight int
+		robots      bool
+	}
+
+	hostState struct {
+		next       time.Time
+		robots     *robots.Document
+		robotsErr  error
+		slots      chan struct{}
+		robotsLoad singleflight.Group
+		mu         sync.Mutex
+		loaded     bool
+	}
+
+	// redirectChain holds the in-flight slots of the hosts a request and its redirects have reached,
+	// so every hop is checked and paced like the first request and its slots are released with the final body.
+	redirectChain struct {
+		err      error
+		fetch    fetchFunc
+		hosts    map[*hostState]struct{}
+		releases []func()
+		mu       sync.Mutex
+	}
+
+	redirectChainKey struct{}
+
+	releasingBody struct {
+		io.ReadCloser
+		release func()
+	}
+)
+
+func (e *RobotsError) Error() string {
+	return fmt.Sprintf("%s: %s is excluded by rule %q for user-agent %q", ErrDisallowedByRobots, e.URL, e.Pattern, e.UserAgent)
+}
+
+func (e *RobotsError) Unwrap() error {
+	return ErrDisallowedByRobots
+}
+
+func newPoliteness(options *Options) *politeness {
+	if options.HostRateLimit <= 0 && options.HostConcurrency <= 0 && !options.Robots {
+		return nil
+	}
+
+	p := &politeness{
+		hosts:       make(map[string]*hostState),
+		now:         time.Now,
+		userAgent:   options.RobotsUserAgent,
+		maxInFlight: options.HostConcurrency,
+		robots:      options.Robots,
+	}
+
+	if options.HostRateLimit > 0 {
+		p.interval = time.Duration(float64(time.Second) / options.HostRateLimit)
+	}
+
+	return p
+}
+
+// do sends the request once the robots rules, the in-flight cap and the request rate of its host permit it.
+// Redirects followed by the client are admitted the same way by checkRedirect.
+// The in-flight slots are held until the response body is closed.
+func (p *politeness) do(req *http.Request, fetch fetchFunc) (*http.Response, error) {
+	chain := &redirectChain{
+		fetch: fetch,
+		hosts: make(map[*hostState]struct{}),
+	}
+	release := sync.OnceFunc(chain.release)
+
+	if err := p.admit(req, chain); err != nil {
+		release()
+
+		return nil, err
+	}
+
+	resp, err := fetch(req.WithContext(context.WithValue(req.Context(), redirectChainKey{}, chain)))
+	if err == nil && chain.failure() != nil {
+		_ = resp.Body.Close()
+		err = chain.failure()
+	}
+
+	if err != nil {
+		release()
+
+		return nil, err
+	}
+
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+
+	return resp, nil
+}
+
+// checkRedirect is the redirect policy of the client. A hop excluded by robots.txt stops the redirects
+// without an error, so the client does not retry it, and do reports it once the client returns.
+func (p *politeness) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+
+	chain, _ := req.Context().Value(redirectChainKey{}).(*redirectChain)
+	if chain == nil {
+		return nil
+	}
+
+	err := p.admit(req, chain)
+
+	var robotsErr *RobotsError
+
+	if errors.As(err, &robotsErr) {
+		chain.fail(err)
+
+		return http.ErrUseLastResponse
+	}
+
+	return err
+}
+
+// admit checks the request against the robots rules of its host, takes an in-flight slot of the host
+// unless the chain already holds one, and waits for the turn of the host.
+func (p *politeness) admit(req *http.Request, chain *redirectChain) error {
+	ctx := req.Context()
+	host := p.host(req.URL)
+
+	interval := p.interval
+
+	if p.robots {
+		doc, err := host.loadRobots(ctx, req.URL, chain.fetch)
+		if err != nil {
+			return err
+		}
+
+		if err := p.checkRobots(doc, req.URL); err != nil {
+			return err
+		}
+
+		if delay, ok := crawlDelay(doc, p.userAgent); ok {
+			interval = max(interval, time.Duration(delay*float64(time.Second)))
+		}
+	}
+
+	if !chain.holds(host) {
+		release, err := host.acquire(ctx)
+		if err != nil {
+			return err
+		}
+
+		chain.hold(host, release)
+	}
+
+	return host.wait(ctx, p.now, interval)
+}
+
+func (p *politeness) host(u *url.URL) *hostState {
+	key := u.Scheme + "://" + u.Host
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state, ok := p.hosts[key]
+
+	if !ok {
+		state = new(hostState)
+
+		if p.maxInFlight > 0 {
+			state.slots = make(chan struct{}, p.maxInFlight)
+		}
+
+		p.hosts[key] = state
+	}
+
+	return state
+}
+
+func (p *politeness) checkRobots(doc *robots.Document, u *url.URL) error {
+	result := robots.Match(*doc, u.RequestURI(), p.userAgent)
+
+	if result.Allowed {
+		return nil
+	}
+
+	err := &RobotsError{
+		URL:       u.String(),
+		UserAgent: result.UserAgent,
+	}
+
+	if result.Pattern != nil {
+		err.Pattern = *result.Pattern
+	}
+
+	return err
+}
+
+// crawlDelay returns the largest crawl delay in seconds declared by the groups robots.Match selects for the user-agent.
+func crawlDelay(doc *robots.Document, userAgent string) (float64, bool) {
+	agent := robots.Match(*doc, "/", userAgent).UserAgent
+
+	var delay float64
+	found := false
+
+	for _, group := range doc.Groups {
+		if group.CrawlDelay == nil || !namesAgent(group, agent) {
+			continue
+		}
+
+		delay = max(delay, *group.CrawlDelay)
+		found = true
+	}
+
+	return delay, found
+}
+
+func namesAgent(group robots.Group, agent string) bool {
+	for _, candidate := range group.UserAgents {
+		if strings.EqualFold(candidate, agent) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *hostState) acquire(ctx context.Context) (func(), error) {
+	if h.slots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case h.slots <- struct{}{}:
+		return func() { <-h.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// wait reserves the next request start time of the host and sleeps until it comes.
+func (h *hostState) wait(ctx context.Context, now func() time.Time, interval time.Duration) error {
+	if interval <= 0 {
+		return nil
+	}
+
+	h.mu.Lock()
+	current := now()
+	start := h.next
+
+	if start.Before(current) {
+		start = current
+	}
+
+	h.next = start.Add(interval)
+	h.mu.Unlock()
+
+	delay := start.Sub(current)
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// loadRobots returns the robots.txt rules of the host, fetching them once. The file is fetched outside
+// of the host lock, so requests that are already admitted keep their pace, and once for all the requests
+// that wait for it.
+func (h *hostState) loadRobots(ctx context.Context, u *url.URL, fetch fetchFunc) (*robots.Document, error) {
+	if doc, ok, err := h.loadedRobots(); ok {
+		return doc, err
+	}
+
+	_, err, _ := h.robotsLoad.Do("", func() (any, error) {
+		if _, ok, _ := h.loadedRobots(); ok {
+			return nil, nil
+		}
+
+		doc, err := fetchRobots(ctx, u, fetch)
+
+		var parseErr *robots.Error
+
+		if err != nil && !errors.As(err, &parseErr) {
+			// transport failures are not remembered, the next request tries again
+			return nil, err
+		}
+
+		h.mu.Lock()
+		h.robots, h.robotsErr, h.loaded = doc, err, true
+		h.mu.Unlock()
+
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	doc, _, err := h.loadedRobots()
+
+	return doc, err
+}
+
+func (h *hostState) loadedRobots() (*robots.Document, bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.robots, h.loaded, h.robotsErr
+}
+
+// fetchRobots fetches and parses robots.txt of the host of the URL.
+// Following RFC 9309, unavailable (4xx) files allow everything and unreachable (5xx) ones disallow everything.
+func fetchRobots(ctx context.Context, u *url.URL, fetch fetchFunc) (*robots.Document, error) {
+	robotsURL := url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}
+
+	// redirects of robots.txt are followed apart from the chain of the request that needs it
+	ctx = context.WithValue(ctx, redirectChainKey{}, (*redirectChain)(nil))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := fetch(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", robotsURL.String(), err)
+	}
+
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500:
+		return disallowAllRobots, nil
+	case resp.StatusCode >= 400:
+		return new(robots.Document), nil
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxRobotsSize))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", robotsURL.String(), err)
+	}
+
+	doc, err := robots.Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", robotsURL.String(), err)
+	}
+
+	return &doc, nil
+}
+
+func (c *redirectChain) holds(host *hostState) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.hosts[host]
+
+	return ok
+}
+
+func (c *redirectChain) hold(host *hostState, release func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.hosts[host] = struct{}{}
+	c.releases = append(c.releases, release)
+}
+
+func (c *redirectChain) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.err = err
+}
+
+func (c *redirectChain) failure() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+func (c *redirectChain) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, release := range c.releases {
+		release()
+	}
+
+	c.releases = nil
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+
+	return err
+}
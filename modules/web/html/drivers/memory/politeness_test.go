@@ -0,0 +1,276 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	robots "github.com/MontFerret/contrib/modules/web/robots/core"
+)
+
+const politePage = `<!DOCTYPE html><html><head></head><body></body></html>`
+
+func TestDriver_Robots(t *testing.T) {
+	Convey("Should reject URLs disallowed by robots.txt", t, func() {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("GET", "http://localhost:1111/robots.txt",
+			httpmock.NewStringResponder(200, "User-agent: *\nDisallow: /private\n\nUser-agent: FerretBot\nDisallow: /admin\n"))
+		httpmock.RegisterResponder("GET", "http://localhost:1111/private/page",
+			httpmock.NewStringResponder(200, politePage))
+		httpmock.RegisterResponder("GET", "http://localhost:1111/admin",
+			httpmock.NewStringResponder(200, politePage))
+
+		drv := New(WithRobots("FerretBot"))
+
+		page, err := drv.Open(context.Background(), drivers.Params{URL: "http://localhost:1111/private/page"})
+		So(err, ShouldBeNil)
+		So(page, ShouldNotBeNil)
+
+		_, err = drv.Open(context.Background(), drivers.Params{URL: "http://localhost:1111/admin"})
+		So(err, ShouldNotBeNil)
+		So(errors.Is(err, ErrDisallowedByRobots), ShouldBeTrue)
+
+		var robotsErr *RobotsError
+
+		So(errors.As(err, &robotsErr), ShouldBeTrue)
+		So(robotsErr.Pattern, ShouldEqual, "/admin")
+		So(robotsErr.UserAgent, ShouldEqual, "FerretBot")
+
+		info := httpmock.GetCallCountInfo()
+		So(info["GET http://localhost:1111/robots.txt"], ShouldEqual, 1)
+		So(info["GET http://localhost:1111/admin"], ShouldEqual, 0)
+	})
+
+	Convey("Should reject redirects to URLs disallowed by robots.txt", t, func() {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("GET", "http://localhost:1111/robots.txt",
+			httpmock.NewStringResponder(404, ""))
+		httpmock.RegisterResponder("GET", "http://localhost:2222/robots.txt",
+			httpmock.NewStringResponder(200, "User-agent: *\nDisallow: /admin\n"))
+		httpmock.RegisterResponder("GET", "http://localhost:1111/start", func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(http.StatusFound, "")
+			resp.Header.Set("Location", "http://localhost:2222/admin")
+
+			return resp, nil
+		})
+		httpmock.RegisterResponder("GET", "http://localhost:2222/admin",
+			httpmock.NewStringResponder(200, politePage))
+
+		_, err := New(WithRobots("")).Open(context.Background(), drivers.Params{URL: "http://localhost:1111/start"})
+		So(errors.Is(err, ErrDisallowedByRobots), ShouldBeTrue)
+
+		info := httpmock.GetCallCountInfo()
+		So(info["GET http://localhost:1111/start"], ShouldEqual, 1)
+		So(info["GET http://localhost:2222/robots.txt"], ShouldEqual, 1)
+		So(info["GET http://localhost:2222/admin"], ShouldEqual, 0)
+	})
+
+	Convey("Should allow everything when robots.txt is missing", t, func() {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("GET", "http://localhost:1111/robots.txt",
+			httpmock.NewStringResponder(404, ""))
+		httpmock.RegisterResponder("GET", "http://localhost:1111/admin",
+			httpmock.NewStringResponder(200, politePage))
+
+		_, err := New(WithRobots("")).Open(context.Background(), drivers.Params{URL: "http://localhost:1111/admin"})
+		So(err, ShouldBeNil)
+	})
+
+	Convey("Should disallow everything when robots.txt is unreachable", t, func() {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("GET", "http://localhost:1111/robots.txt",
+			httpmock.NewStringResponder(503, ""))
+		httpmock.RegisterResponder("GET", "http://localhost:1111/page",
+			httpmock.NewStringResponder(200, politePage))
+
+		_, err := New(WithRobots(""), WithMaxRetries(1)).
+			Open(context.Background(), drivers.Params{URL: "http://localhost:1111/page"})
+		So(errors.Is(err, ErrDisallowedByRobots), ShouldBeTrue)
+	})
+}
+
+func TestCrawlDelay(t *testing.T) {
+	Convey("Should take the largest delay of the selected user-agent groups", t, func() {
+		doc, err := robots.Parse("User-agent: *\nDisallow: /private\nCrawl-delay: 2\n\n" +
+			"User-agent: FerretBot\nDisallow: /admin\nCrawl-delay: 0.5\n\nUser-agent: ferretbot\nCrawl-delay: 1\n")
+		So(err, ShouldBeNil)
+
+		delay, ok := crawlDelay(&doc, "FerretBot")
+		So(ok, ShouldBeTrue)
+		So(delay, ShouldEqual, 1)
+
+		delay, ok = crawlDelay(&doc, "OtherBot")
+		So(ok, ShouldBeTrue)
+		So(delay, ShouldEqual, 2)
+
+		_, ok = crawlDelay(disallowAllRobots, "")
+		So(ok, ShouldBeFalse)
+	})
+}
+
+func TestPoliteness_RateLimit(t *testing.T) {
+	Convey("Should space out requests to the same host", t, func() {
+		p := newPoliteness(&Options{HostRateLimit: 10})
+
+		var mu sync.Mutex
+		starts := make([]time.Time, 0, 3)
+
+		fetch := func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			starts = append(starts, time.Now())
+			mu.Unlock()
+
+			return httpmock.NewStringResponse(200, politePage), nil
+		}
+
+		for range 3 {
+			req, _ := http.NewRequest(http.MethodGet, "http://localhost:1111", nil)
+			resp, err := p.do(req, fetch)
+
+			So(err, ShouldBeNil)
+			So(resp.Body.Close(), ShouldBeNil)
+		}
+
+		So(starts, ShouldHaveLength, 3)
+		So(starts[2].Sub(starts[0]), ShouldBeGreaterThanOrEqualTo, 180*time.Millisecond)
+	})
+
+	Convey("Should honor crawl-delay", t, func() {
+		p := newPoliteness(&Options{Robots: true})
+		host := p.host(&url.URL{Scheme: "http", Host: "localhost:1111"})
+
+		fetch := func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(200, "User-agent: *\nCrawl-delay: 0.2\n"), nil
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, "http://localhost:1111/page", nil)
+		resp, err := p.do(req, fetch)
+		So(err, ShouldBeNil)
+		So(resp.Body.Close(), ShouldBeNil)
+
+		So(host.next.Sub(time.Now()), ShouldBeGreaterThan, 100*time.Millisecond)
+	})
+
+	Convey("Should not hold up the host while robots.txt is fetched", t, func() {
+		p := newPoliteness(&Options{Robots: true})
+		u := &url.URL{Scheme: "http", Host: "localhost:1111"}
+		host := p.host(u)
+
+		fetching := make(chan struct{})
+		unblock := make(chan struct{})
+
+		fetch := func(req *http.Request) (*http.Response, error) {
+			close(fetching)
+			<-unblock
+
+			return httpmock.NewStringResponse(404, ""), nil
+		}
+
+		loaded := make(chan error, 1)
+
+		go func() {
+			_, err := host.loadRobots(context.Background(), u, fetch)
+			loaded <- err
+		}()
+
+		<-fetching
+
+		waited := make(chan error, 1)
+
+		go func() {
+			waited <- host.wait(context.Background(), time.Now, time.Millisecond)
+		}()
+
+		select {
+		case err := <-waited:
+			So(err, ShouldBeNil)
+		case <-time.After(time.Second):
+			So("wait was blocked by the robots.txt fetch", ShouldBeEmpty)
+		}
+
+		close(unblock)
+		So(<-loaded, ShouldBeNil)
+	})
+
+	Convey("Should stop waiting when the context is cancelled", t, func() {
+		p := newPoliteness(&Options{HostRateLimit: 0.1})
+		fetch := func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(200, politePage), nil
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, "http://localhost:1111", nil)
+		resp, err := p.do(req, fetch)
+		So(err, ShouldBeNil)
+		So(resp.Body.Close(), ShouldBeNil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		req, _ = http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost:1111", nil)
+		_, err = p.do(req, fetch)
+		So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+	})
+}
+
+func TestPoliteness_HostConcurrency(t *testing.T) {
+	Convey("Should cap in-flight requests per host until bodies are closed", t, func() {
+		p := newPoliteness(&Options{HostConcurrency: 2})
+
+		var inFlight, peak int32
+
+		fetch := func(req *http.Request) (*http.Response, error) {
+			current := atomic.AddInt32(&inFlight, 1)
+
+			for {
+				prev := atomic.LoadInt32(&peak)
+
+				if current <= prev || atomic.CompareAndSwapInt32(&peak, prev, current) {
+					break
+				}
+			}
+
+			return httpmock.NewStringResponse(200, politePage), nil
+		}
+
+		var wg sync.WaitGroup
+
+		for range 6 {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				req, _ := http.NewRequest(http.MethodGet, "http://localhost:1111", nil)
+				resp, err := p.do(req, fetch)
+				if err != nil {
+					return
+				}
+
+				time.Sleep(20 * time.Millisecond)
+				atomic.AddInt32(&inFlight, -1)
+				_ = resp.Body.Close()
+			}()
+		}
+
+		wg.Wait()
+
+		So(atomic.LoadInt32(&peak), ShouldEqual, 2)
+	})
+}
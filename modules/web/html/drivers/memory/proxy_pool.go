@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ProxyStrategy defines how a proxy is picked from the pool for each request.
+type ProxyStrategy int
+
+const (
+	// ProxyRoundRobin cycles through the healthy proxies.
+	ProxyRoundRobin ProxyStrategy = iota
+	// ProxyRandom picks a random healthy proxy.
+	ProxyRandom
+	// ProxyStickyHost keeps sending requests for a host through the same proxy while it stays healthy.
+	ProxyStickyHost
+)
+
+var (
+	DefaultProxyMaxFailures = 3
+	DefaultProxyCooldown    = time.Minute
+)
+
+// ErrNoHealthyProxy is returned when every proxy of the pool is evicted.
+var ErrNoHealthyProxy = errors.New("no healthy proxy available")
+
+type (
+	proxyPool struct {
+		err         error
+		now         func() time.Time
+		sticky      map[string]*poolProxy
+		proxies     []*poolProxy
+		cooldown    time.Duration
+		mu          sync.Mutex
+		next        int
+		maxFailures int
+		strategy    ProxyStrategy
+	}
+
+	poolProxy struct {
+		url          *url.URL
+		evictedUntil time.Time
+		failures     int
+	}
+
+	proxyContextKey struct{}
+)
+
+func newProxyPool(options *Options) *proxyPool {
+	if len(options.Proxies) == 0 {
+		return nil
+	}
+
+	pool := &proxyPool{
+		now:         time.Now,
+		sticky:      make(map[string]*poolProxy),
+		proxies:     make([]*poolProxy, 0, len(options.Proxies)),
+		cooldown:    options.ProxyCooldown,
+		maxFailures: options.ProxyMaxFailures,
+		strategy:    options.ProxyStrategy,
+	}
+
+	if pool.maxFailures <= 0 {
+		pool.maxFailures = DefaultProxyMaxFailures
+	}
+
+	if pool.cooldown <= 0 {
+		pool.cooldown = DefaultProxyCooldown
+	}
+
+	for _, address := range options.Proxies {
+		proxyURL, err := url.Parse(address)
+		if err == nil && proxyURL.Host == "" {
+			err = errors.New("missing host")
+		}
+
+		if err != nil {
+			// options cannot fail, the error is reported by the first request instead
+			pool.err = fmt.Errorf("invalid proxy %q: %w", address, err)
+
+			break
+		}
+
+		pool.proxies = append(pool.proxies, &poolProxy{url: proxyURL})
+	}
+
+	return pool
+}
+
+// do sends the request through a proxy picked from the pool and updates the proxy health with the outcome.
+// Transport errors and 407 responses count as failures; a proxy is evicted for the cooldown period
+// after maxFailures consecutive ones.
+func (p *proxyPool) do(req *http.Request, fetch fetchFunc) (*http.Response, error) {
+	proxy, err := p.pick(req.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := fetch(req.WithContext(context.WithValue(req.Context(), proxyContextKey{}, proxy)))
+
+	p.report(proxy, err == nil && resp.StatusCode != http.StatusProxyAuthRequired)
+
+	return resp, err
+}
+
+// proxyURL is used as the Proxy function of the transport and returns the proxy chosen for the request.
+// Credentials given as user info of the proxy URL are sent in the Proxy-Authorization header.
+func (p *proxyPool) proxyURL(req *http.Request) (*url.URL, error) {
+	if proxy, ok := req.Context().Value(proxyContextKey{}).(*poolProxy); ok {
+		return proxy.url, nil
+	}
+
+	return nil, nil
+}
+
+func (p *proxyPool) pick(target *url.URL) (*poolProxy, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	healthy := make([]*poolProxy, 0, len(p.proxies))
+
+	for _, proxy := range p.proxies {
+		if !now.Before(proxy.evictedUntil) {
+			healthy = append(healthy, proxy)
+		}
+	}
+
+	if len(healthy) == 0 {
+		return nil, ErrNoHealthyProxy
+	}
+
+	switch p.strategy {
+	case ProxyRandom:
+		return healthy[rand.IntN(len(healthy))], nil
+	case ProxyStickyHost:
+		if proxy, ok := p.sticky[target.Host]; ok && !now.Before(proxy.evictedUntil) {
+			return proxy, nil
+		}
+
+		proxy := p.roundRobin(healthy)
+		p.sticky[target.Host] = proxy
+
+		return proxy, nil
+	default:
+		return p.roundRobin(healthy), nil
+	}
+}
+
+func (p *proxyPool) roundRobin(healthy []*poolProxy) *poolProxy {
+	proxy := healthy[p.next%len(healthy)]
+	p.next++
+
+	return proxy
+}
+
+func (p *proxyPool) report(proxy *poolProxy, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if ok {
+		proxy.failures = 0
+
+		return
+	}
+
+	proxy.failures++
+
+	if proxy.failures >= p.maxFailures {
+		proxy.failures = 0
+		proxy.evictedUntil = p.now().Add(p.cooldown)
+	}
+}
@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+)
+
+func newTestProxy(hits *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+
+		if r.Header.Get("Proxy-Authorization") == "" {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(politePage))
+	}))
+}
+
+func TestDriver_ProxyPool(t *testing.T) {
+	Convey("Should rotate requests between authenticated proxies", t, func() {
+		var first, second int32
+
+		proxy1 := newTestProxy(&first)
+		defer proxy1.Close()
+
+		proxy2 := newTestProxy(&second)
+		defer proxy2.Close()
+
+		withAuth := func(server *httptest.Server) string {
+			u, _ := url.Parse(server.URL)
+			u.User = url.UserPassword("user", "secret")
+
+			return u.String()
+		}
+
+		drv := New(WithProxyPool(ProxyRoundRobin, withAuth(proxy1), withAuth(proxy2)), WithMaxRetries(1))
+
+		for range 4 {
+			page, err := drv.Open(context.Background(), drivers.Params{URL: "http://example.test/page"})
+
+			So(err, ShouldBeNil)
+			So(page, ShouldNotBeNil)
+		}
+
+		So(atomic.LoadInt32(&first), ShouldEqual, 2)
+		So(atomic.LoadInt32(&second), ShouldEqual, 2)
+	})
+
+	Convey("Should reject the per-document proxy param", t, func() {
+		_, err := New().Open(context.Background(), drivers.Params{
+			URL:   "http://example.test/page",
+			Proxy: "http://proxy.example.test:8080",
+		})
+
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestProxyPool(t *testing.T) {
+	target := &url.URL{Scheme: "http", Host: "localhost:1111"}
+
+	Convey("Should pick proxies by strategy", t, func() {
+		Convey("round-robin", func() {
+			pool := newProxyPool(&Options{Proxies: []string{"http://a:1", "http://b:1"}})
+
+			picked := make([]string, 0, 3)
+
+			for range 3 {
+				proxy, err := pool.pick(target)
+				So(err, ShouldBeNil)
+
+				picked = append(picked, proxy.url.Host)
+			}
+
+			So(picked, ShouldResemble, []string{"a:1", "b:1", "a:1"})
+		})
+
+		Convey("sticky per host", func() {
+			pool := newProxyPool(&Options{
+				Proxies:       []string{"http://a:1", "http://b:1"},
+				ProxyStrategy: ProxyStickyHost,
+			})
+
+			first, _ := pool.pick(target)
+			other, _ := pool.pick(&url.URL{Scheme: "http", Host: "localhost:2222"})
+			again, _ := pool.pick(target)
+
+			So(again, ShouldEqual, first)
+			So(other, ShouldNotEqual, first)
+		})
+
+		Convey("random", func() {
+			pool := newProxyPool(&Options{
+				Proxies:       []string{"http://a:1", "http://b:1"},
+				ProxyStrategy: ProxyRandom,
+			})
+
+			for range 10 {
+				proxy, err := pool.pick(target)
+
+				So(err, ShouldBeNil)
+				So(proxy.url.Host, ShouldBeIn, []string{"a:1", "b:1"})
+			}
+		})
+	})
+
+	Convey("Should evict failing proxies until the cooldown ends", t, func() {
+		now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		pool := newProxyPool(&Options{
+			Proxies:          []string{"http://a:1", "http://b:1"},
+			ProxyStrategy:    ProxyStickyHost,
+			ProxyMaxFailures: 2,
+			ProxyCooldown:    time.Minute,
+		})
+		pool.now = func() time.Time { return now }
+
+		var used []string
+
+		fetch := func(req *http.Request) (*http.Response, error) {
+			proxy, _ := pool.proxyURL(req)
+			used = append(used, proxy.Host)
+
+			if proxy.Host == "a:1" {
+				return nil, errors.New("connection refused")
+			}
+
+			return httpmock.NewStringResponse(http.StatusOK, politePage), nil
+		}
+
+		for range 3 {
+			req, _ := http.NewRequest(http.MethodGet, target.String(), nil)
+			_, _ = pool.do(req, fetch)
+		}
+
+		So(used, ShouldResemble, []string{"a:1", "a:1", "b:1"})
+
+		now = now.Add(2 * time.Minute)
+
+		proxy, err := pool.pick(&url.URL{Scheme: "http", Host: "localhost:3333"})
+		So(err, ShouldBeNil)
+		So(proxy.url.Host, ShouldEqual, "a:1")
+	})
+
+	Convey("Should fail when every proxy is evicted", t, func() {
+		pool := newProxyPool(&Options{Proxies: []string{"http://a:1"}, ProxyMaxFailures: 1})
+		fetch := func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(http.StatusProxyAuthRequired, ""), nil
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, target.String(), nil)
+		_, err := pool.do(req, fetch)
+		So(err, ShouldBeNil)
+
+		_, err = pool.do(req, fetch)
+		So(errors.Is(err, ErrNoHealthyProxy), ShouldBeTrue)
+	})
+
+	Convey("Should report invalid proxy addresses", t, func() {
+		pool := newProxyPool(&Options{Proxies: []string{"http://a:1", "::"}})
+
+		_, err := pool.pick(target)
+		So(err, ShouldNotBeNil)
+	})
+}
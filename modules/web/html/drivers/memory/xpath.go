@@ -9,22 +9,25 @@ import (
 	"golang.org/x/net/html"
 
 	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/contrib/modules/web/html/drivers/internal/xpathutil"
 
 	"github.com/MontFerret/ferret/v2/pkg/runtime"
 )
 
 func EvalXPathToNode(doc *goquery.Document, selection *goquery.Selection, expression string) (drivers.HTMLNode, error) {
-	node, err := htmlquery.Query(fromSelectionToNode(selection), expression)
+	exp, err := xpathutil.Compile(expression, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if node == nil {
+	res := exp.Select(xpathutil.NewNavigator(fromSelectionToNode(selection), nil))
+
+	if !res.MoveNext() {
 		return nil, nil
 	}
 
-	return parseXPathNode(doc, node)
+	return parseXPathNode(doc, xpathutil.Node(res.Current()))
 }
 
 func EvalXPathToElement(doc *goquery.Document, selection *goquery.Selection, expression string) (drivers.HTMLElement, error) {
@@ -48,7 +51,7 @@ func EvalXPathToNodes(doc *goquery.Document, selection *goquery.Selection, expre
 }
 
 func EvalXPathToNodesWith(selection *goquery.Selection, expression string, mapper func(node *html.Node) (runtime.Value, error)) (runtime.List, error) {
-	out, err := evalXPathToInternal(selection, expression)
+	out, err := evalXPathToInternal(selection, expression, nil)
 
 	if err != nil {
 		return nil, err
@@ -60,7 +63,7 @@ func EvalXPathToNodesWith(selection *goquery.Selection, expression string, mappe
 		ctx := context.Background()
 
 		for res.MoveNext() {
-			item, err := mapper(res.Current().(*htmlquery.NodeNavigator).Current())
+			item, err := mapper(xpathutil.Node(res.Current()))
 
 			if err != nil {
 				return nil, err
@@ -78,7 +81,12 @@ func EvalXPathToNodesWith(selection *goquery.Selection, expression string, mappe
 }
 
 func EvalXPathTo(doc *goquery.Document, selection *goquery.Selection, expression string) (runtime.Value, error) {
-	out, err := evalXPathToInternal(selection, expression)
+	return EvalXPathToWithNamespaces(doc, selection, expression, nil)
+}
+
+// EvalXPathToWithNamespaces evaluates an expression with namespace-prefix bindings.
+func EvalXPathToWithNamespaces(doc *goquery.Document, selection *goquery.Selection, expression string, namespaces map[string]string) (runtime.Value, error) {
+	out, err := evalXPathToInternal(selection, expression, namespaces)
 
 	if err != nil {
 		return nil, err
@@ -100,7 +108,7 @@ func EvalXPathTo(doc *goquery.Document, selection *goquery.Selection, expression
 			case xpath.AttributeNode:
 				item = runtime.NewString(node.Value())
 			default:
-				i, err := parseXPathNode(doc, node.(*htmlquery.NodeNavigator).Current())
+				i, err := parseXPathNode(doc, xpathutil.Node(node))
 
 				if err != nil {
 					return nil, err
@@ -120,14 +128,14 @@ func EvalXPathTo(doc *goquery.Document, selection *goquery.Selection, expression
 	}
 }
 
-func evalXPathToInternal(selection *goquery.Selection, expression string) (any, error) {
-	exp, err := xpath.Compile(expression)
+func evalXPathToInternal(selection *goquery.Selection, expression string, namespaces map[string]string) (any, error) {
+	exp, err := xpathutil.Compile(expression, namespaces)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return exp.Evaluate(htmlquery.CreateXPathNavigator(fromSelectionToNode(selection))), nil
+	return exp.Evaluate(xpathutil.NewNavigator(fromSelectionToNode(selection), namespaces)), nil
 }
 
 func parseXPathNode(doc *goquery.Document, node *html.Node) (drivers.HTMLNode, error) {
@@ -9,6 +9,7 @@ type (
 		Name      string       `json:"name"`
 		Proxy     string       `json:"proxy"`
 		UserAgent string       `json:"userAgent"`
+		CookieJar string       `json:"cookieJar"`
 	}
 
 	Option func(opts *Options)
@@ -77,3 +78,12 @@ func WithCookies(cookies []HTTPCookie) Option {
 		}
 	}
 }
+
+// WithCookieJar loads cookies from a jar file of the Ferret filesystem when a page is opened
+// and saves the page cookies back when the page is closed.
+// Files with the .json extension use the JSON format, other files use the Netscape cookies.txt format.
+func WithCookieJar(path string) Option {
+	return func(opts *Options) {
+		opts.CookieJar = path
+	}
+}
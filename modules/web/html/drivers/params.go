@@ -3,11 +3,25 @@ package drivers
 type (
 	InitScriptTiming string
 
+	StealthPatch string
+
 	InitScript struct {
 		Source string           `json:"source"`
 		Timing InitScriptTiming `json:"timing"`
 	}
 
+	// Stealth is an opt-in profile of browser fingerprint patches.
+	// Every patch is applied unless it is listed in Skip.
+	// A disabled profile turns stealth off for a page, overriding the profile of the driver.
+	Stealth struct {
+		Languages     []string       `json:"languages"`
+		Skip          []StealthPatch `json:"skip"`
+		Platform      string         `json:"platform"`
+		WebGLVendor   string         `json:"webglVendor"`
+		WebGLRenderer string         `json:"webglRenderer"`
+		Disabled      bool           `json:"-"`
+	}
+
 	ResourceFilter struct {
 		URL  string `json:"url"`
 		Type string `json:"type"`
@@ -37,9 +51,11 @@ type (
 		Viewport    *Viewport    `json:"viewport"`
 		Ignore      *Ignore      `json:"ignore"`
 		InitScript  *InitScript  `json:"initScript"`
+		Stealth     *Stealth     `json:"stealth"`
 		URL         string       `json:"url"`
 		UserAgent   string       `json:"userAgent"`
 		Charset     string       `json:"charset"`
+		Proxy       string       `json:"proxy"`
 		KeepCookies bool         `json:"keepCookies"`
 	}
 
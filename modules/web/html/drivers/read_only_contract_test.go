@@ -158,6 +158,7 @@ func TestMemoryElementDOMPropertyFallback(t *testing.T) {
 	assertElementRead(t, ctx, choices, "className", runtime.NewString("picker"))
 	assertElementRead(t, ctx, choices, "disabled", runtime.True)
 	assertElementRead(t, ctx, choices, "nodeName", runtime.NewString("select"))
+	assertElementRead(t, ctx, choices, "baseURI", runtime.NewString("https://example.com"))
 
 	firstChild, err := choices.Get(ctx, runtime.NewInt(0))
 	if err != nil {
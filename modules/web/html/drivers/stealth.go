@@ -0,0 +1,108 @@
+package drivers
+
+import (
+	"strings"
+
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+const (
+	// StealthWebDriver hides navigator.webdriver.
+	StealthWebDriver StealthPatch = "webdriver"
+	// StealthPlugins exposes the PDF viewer plugins and mime types of a desktop browser.
+	StealthPlugins StealthPatch = "plugins"
+	// StealthLanguages aligns navigator.languages with the Accept-Language header.
+	StealthLanguages StealthPatch = "languages"
+	// StealthWebGL reports a consumer GPU as the unmasked WebGL vendor and renderer.
+	StealthWebGL StealthPatch = "webgl"
+	// StealthChromeRuntime defines the window.chrome.runtime object of a regular browser.
+	StealthChromeRuntime StealthPatch = "chromeRuntime"
+	// StealthPermissions aligns the notifications permission state with Notification.permission.
+	StealthPermissions StealthPatch = "permissions"
+	// StealthUserAgent removes the headless markers from the user agent and its client hints.
+	StealthUserAgent StealthPatch = "userAgent"
+
+	DefaultStealthWebGLVendor   = "Intel Inc."
+	DefaultStealthWebGLRenderer = "Intel Iris OpenGL Engine"
+)
+
+var stealthPatches = []StealthPatch{
+	StealthWebDriver,
+	StealthPlugins,
+	StealthLanguages,
+	StealthWebGL,
+	StealthChromeRuntime,
+	StealthPermissions,
+	StealthUserAgent,
+}
+
+// StealthPatches returns the names of all fingerprint patches of the stealth profile.
+func StealthPatches() []StealthPatch {
+	res := make([]StealthPatch, len(stealthPatches))
+	copy(res, stealthPatches)
+
+	return res
+}
+
+// NormalizeStealth validates a stealth profile and applies its defaults.
+// Languages are left empty, so that drivers can derive them from the Accept-Language header.
+// A disabled profile normalizes to none.
+func NormalizeStealth(profile *Stealth) (*Stealth, error) {
+	if profile == nil || profile.Disabled {
+		return nil, nil
+	}
+
+	result := *profile
+	result.Languages = make([]string, 0, len(profile.Languages))
+
+	for _, lang := range profile.Languages {
+		if lang = strings.TrimSpace(lang); lang != "" {
+			result.Languages = append(result.Languages, lang)
+		}
+	}
+
+	result.Skip = make([]StealthPatch, 0, len(profile.Skip))
+
+	for _, patch := range profile.Skip {
+		if !isStealthPatch(patch) {
+			return nil, runtime.Errorf(runtime.ErrInvalidArgument, "unknown stealth patch: %s", patch)
+		}
+
+		result.Skip = append(result.Skip, patch)
+	}
+
+	if result.WebGLVendor == "" {
+		result.WebGLVendor = DefaultStealthWebGLVendor
+	}
+
+	if result.WebGLRenderer == "" {
+		result.WebGLRenderer = DefaultStealthWebGLRenderer
+	}
+
+	return &result, nil
+}
+
+// Enabled reports whether the profile applies the given patch.
+func (s *Stealth) Enabled(patch StealthPatch) bool {
+	if s == nil || s.Disabled {
+		return false
+	}
+
+	for _, skipped := range s.Skip {
+		if skipped == patch {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isStealthPatch(patch StealthPatch) bool {
+	for _, known := range stealthPatches {
+		if known == patch {
+			return true
+		}
+	}
+
+	return false
+}
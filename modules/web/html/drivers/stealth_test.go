@@ -0,0 +1,39 @@
+package drivers
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+func TestNormalizeStealth(t *testing.T) {
+	if got, err := NormalizeStealth(nil); got != nil || err != nil {
+		t.Fatalf("expected absent profile, got %#v, %v", got, err)
+	}
+
+	got, err := NormalizeStealth(&Stealth{
+		Languages: []string{" de-DE ", "", "de"},
+		Skip:      []StealthPatch{StealthWebGL},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(got.Languages, []string{"de-DE", "de"}) {
+		t.Fatalf("unexpected languages: %#v", got.Languages)
+	}
+
+	if got.WebGLVendor != DefaultStealthWebGLVendor || got.WebGLRenderer != DefaultStealthWebGLRenderer {
+		t.Fatalf("expected default WebGL identity, got %q / %q", got.WebGLVendor, got.WebGLRenderer)
+	}
+
+	if got.Enabled(StealthWebGL) || !got.Enabled(StealthWebDriver) {
+		t.Fatalf("expected only the webgl patch to be skipped")
+	}
+
+	if _, err := NormalizeStealth(&Stealth{Skip: []StealthPatch{"canvas"}}); !errors.Is(err, runtime.ErrInvalidArgument) {
+		t.Fatalf("expected invalid argument for an unknown patch, got %v", err)
+	}
+}
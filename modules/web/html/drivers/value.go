@@ -27,6 +27,11 @@ type (
 		XPath(ctx context.Context, expression runtime.String) (runtime.Value, error)
 	}
 
+	// XPathTarget evaluates XPath expressions with namespace-prefix bindings and the extended function library.
+	XPathTarget interface {
+		XPathWithOptions(ctx context.Context, expression runtime.String, options XPathOptions) (runtime.Value, error)
+	}
+
 	ContentTarget interface {
 		GetTextContent(ctx context.Context) (runtime.String, error)
 		SetTextContent(ctx context.Context, textContent runtime.String) error
@@ -75,6 +80,20 @@ type (
 		GetDOMProperty(ctx context.Context, name runtime.String) (runtime.Value, error)
 	}
 
+	// FormTarget fills the form controls contained in an element.
+	FormTarget interface {
+		FillForm(ctx context.Context, fields []FormField, options FormFillOptions) (FormFillResult, error)
+	}
+
+	// GeometryTarget exposes the rendered geometry and state of an element.
+	GeometryTarget interface {
+		// GetBoundingBox returns the border box of the element in viewport coordinates, or None when it is not rendered.
+		GetBoundingBox(ctx context.Context) (runtime.Value, error)
+		IsVisible(ctx context.Context) (runtime.Boolean, error)
+		IsInViewport(ctx context.Context) (runtime.Boolean, error)
+		IsEnabled(ctx context.Context) (runtime.Boolean, error)
+	}
+
 	InteractionTarget interface {
 		Click(ctx context.Context, count runtime.Int) error
 		ClickBySelector(ctx context.Context, selector QuerySelector, count runtime.Int) error
@@ -166,6 +185,15 @@ type (
 		MoveMouseByXY(ctx context.Context, x, y runtime.Float) (runtime.Boolean, error)
 	}
 
+	// DocumentGestureTarget drives drag-and-drop and touch gestures in the document viewport.
+	// Touch gestures require a page with mobile emulation.
+	DocumentGestureTarget interface {
+		Drag(ctx context.Context, from, to GesturePoint, options GestureOptions) error
+		Tap(ctx context.Context, at GesturePoint) error
+		Swipe(ctx context.Context, from, to GesturePoint, options GestureOptions) error
+		Pinch(ctx context.Context, at GesturePoint, options PinchOptions) error
+	}
+
 	PageStateTarget interface {
 		IsClosed() runtime.Boolean
 		GetURL() runtime.String
@@ -196,6 +224,11 @@ type (
 		CaptureScreenshot(ctx context.Context, params ScreenshotParams) (runtime.Binary, error)
 	}
 
+	// PageArchiveTarget captures the page together with its resources.
+	PageArchiveTarget interface {
+		Archive(ctx context.Context, params ArchiveParams) (runtime.Binary, error)
+	}
+
 	PageNavigationTarget interface {
 		WaitForNavigation(ctx context.Context, targetURL runtime.String) error
 		WaitForFrameNavigation(ctx context.Context, frame HTMLDocument, targetURL runtime.String) error
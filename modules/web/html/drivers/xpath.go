@@ -0,0 +1,53 @@
+package drivers
+
+import (
+	"strings"
+
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+// XPathOptions defines how an XPath expression is evaluated.
+type XPathOptions struct {
+	// Namespaces binds namespace prefixes used by the expression to namespace URIs.
+	Namespaces map[string]string `json:"namespaces"`
+}
+
+// NormalizeXPathOptions validates the namespace bindings of XPath options.
+func NormalizeXPathOptions(options XPathOptions) (XPathOptions, error) {
+	if len(options.Namespaces) == 0 {
+		return XPathOptions{}, nil
+	}
+
+	namespaces := make(map[string]string, len(options.Namespaces))
+
+	for prefix, uri := range options.Namespaces {
+		if !isNCName(prefix) {
+			return options, runtime.Errorf(runtime.ErrInvalidArgument, "invalid namespace prefix: %q", prefix)
+		}
+
+		if strings.TrimSpace(uri) == "" {
+			return options, runtime.Errorf(runtime.ErrInvalidArgument, "namespace URI of prefix %s is empty", prefix)
+		}
+
+		namespaces[prefix] = uri
+	}
+
+	return XPathOptions{Namespaces: namespaces}, nil
+}
+
+func isNCName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, c := range name {
+		switch {
+		case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= 0x80:
+		case i > 0 && (c == '-' || c == '.' || (c >= '0' && c <= '9')):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -3,6 +3,8 @@ module github.com/MontFerret/contrib/modules/web/html
 go 1.25.6
 
 require (
+	github.com/JohannesKaufmann/html-to-markdown/v2 v2.5.2
+	github.com/MontFerret/contrib/modules/web/robots v1.0.0-rc.15
 	github.com/MontFerret/cssx v0.2.0
 	github.com/MontFerret/ferret/v2 v2.0.0-alpha.46
 	github.com/PuerkitoBio/goquery v1.12.0
@@ -26,6 +28,7 @@ require (
 )
 
 require (
+	github.com/JohannesKaufmann/dom v0.3.1 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/golang/groupcache v0.0.0-20210331224755-41bb18bfe9da // indirect
 	github.com/gopherjs/gopherjs v1.17.2 // indirect
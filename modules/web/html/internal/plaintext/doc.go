@@ -0,0 +1,2 @@
+// Package plaintext converts HTML subtrees to readable plain text.
+package plaintext
@@ -0,0 +1,203 @@
+package plaintext
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+var blockElements = map[string]struct{}{
+	"address":    {},
+	"article":    {},
+	"aside":      {},
+	"blockquote": {},
+	"body":       {},
+	"caption":    {},
+	"dd":         {},
+	"details":    {},
+	"dialog":     {},
+	"div":        {},
+	"dl":         {},
+	"dt":         {},
+	"fieldset":   {},
+	"figcaption": {},
+	"figure":     {},
+	"footer":     {},
+	"form":       {},
+	"h1":         {},
+	"h2":         {},
+	"h3":         {},
+	"h4":         {},
+	"h5":         {},
+	"h6":         {},
+	"header":     {},
+	"hgroup":     {},
+	"hr":         {},
+	"html":       {},
+	"li":         {},
+	"main":       {},
+	"nav":        {},
+	"ol":         {},
+	"p":          {},
+	"pre":        {},
+	"section":    {},
+	"summary":    {},
+	"table":      {},
+	"tbody":      {},
+	"td":         {},
+	"tfoot":      {},
+	"th":         {},
+	"thead":      {},
+	"tr":         {},
+	"ul":         {},
+}
+
+// skippedElements hold no readable content.
+var skippedElements = map[string]struct{}{
+	"audio":    {},
+	"button":   {},
+	"canvas":   {},
+	"embed":    {},
+	"head":     {},
+	"iframe":   {},
+	"input":    {},
+	"link":     {},
+	"meta":     {},
+	"noscript": {},
+	"object":   {},
+	"script":   {},
+	"select":   {},
+	"style":    {},
+	"svg":      {},
+	"template": {},
+	"textarea": {},
+	"video":    {},
+}
+
+func isBlock(node *html.Node) bool {
+	_, ok := blockElements[node.Data]
+
+	return ok
+}
+
+// Skipped reports whether a node holds no readable content: scripts, media, form controls, hidden elements and
+// nodes other than elements and text.
+func Skipped(node *html.Node) bool {
+	switch node.Type {
+	case html.ElementNode:
+	case html.TextNode, html.DocumentNode:
+		return false
+	default:
+		return true
+	}
+
+	if _, ok := skippedElements[node.Data]; ok {
+		return true
+	}
+
+	if hasAttr(node, "hidden") || strings.EqualFold(getAttr(node, "aria-hidden"), "true") {
+		return true
+	}
+
+	style := strings.ToLower(strings.ReplaceAll(getAttr(node, "style"), " ", ""))
+
+	return strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden")
+}
+
+func children(node *html.Node) []*html.Node {
+	res := make([]*html.Node, 0, 4)
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		res = append(res, child)
+	}
+
+	return res
+}
+
+func textContent(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+
+	var b strings.Builder
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		switch child.Type {
+		case html.TextNode:
+			b.WriteString(child.Data)
+		case html.ElementNode:
+			if child.Data == "br" {
+				b.WriteString("\n")
+			} else {
+				b.WriteString(textContent(child))
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func getAttr(node *html.Node, name string) string {
+	for _, attr := range node.Attr {
+		if attr.Namespace == "" && attr.Key == name {
+			return attr.Val
+		}
+	}
+
+	return ""
+}
+
+func hasAttr(node *html.Node, name string) bool {
+	for _, attr := range node.Attr {
+		if attr.Namespace == "" && attr.Key == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// collapseSpace replaces every whitespace run with a single space, keeping leading and trailing spaces.
+func collapseSpace(value string) string {
+	var b strings.Builder
+
+	space := false
+
+	for _, c := range value {
+		if unicode.IsSpace(c) {
+			space = true
+
+			continue
+		}
+
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+
+		b.WriteRune(c)
+	}
+
+	if space {
+		b.WriteByte(' ')
+	}
+
+	return b.String()
+}
+
+func leadingSpace(value string) string {
+	if strings.HasPrefix(value, " ") {
+		return " "
+	}
+
+	return ""
+}
+
+func trailingSpace(value string) string {
+	if strings.HasSuffix(value, " ") {
+		return " "
+	}
+
+	return ""
+}
@@ -0,0 +1,260 @@
+package plaintext
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Options defines how HTML is converted.
+type Options struct {
+	// BaseURL resolves relative link URLs.
+	BaseURL *url.URL
+	// Tables renders tables as GFM pipe tables, otherwise every row is a line of tab-separated cells.
+	Tables bool
+	// Links appends link targets to the link text.
+	Links bool
+}
+
+// lineBreak marks <br> elements in inline content until lines are finished.
+const lineBreak = "\n"
+
+type renderer struct {
+	opts Options
+}
+
+// Text converts the given nodes and their subtrees to plain text.
+// Blocks are separated by blank lines and list items keep their markers.
+func Text(nodes []*html.Node, opts Options) string {
+	r := &renderer{opts: opts}
+
+	return strings.Join(r.blocks(nodes), "\n\n")
+}
+
+// blocks renders a sequence of sibling nodes, grouping inline runs into paragraphs.
+func (r *renderer) blocks(nodes []*html.Node) []string {
+	res := make([]string, 0, len(nodes))
+
+	var run strings.Builder
+
+	flush := func() {
+		if paragraph := r.paragraph(run.String()); paragraph != "" {
+			res = append(res, paragraph)
+		}
+
+		run.Reset()
+	}
+
+	for _, node := range nodes {
+		if Skipped(node) {
+			continue
+		}
+
+		switch {
+		case node.Type == html.DocumentNode:
+			flush()
+			res = append(res, r.blocks(children(node))...)
+		case node.Type == html.ElementNode && isBlock(node):
+			flush()
+			res = append(res, r.block(node)...)
+		default:
+			run.WriteString(r.inline(node))
+		}
+	}
+
+	flush()
+
+	return res
+}
+
+func (r *renderer) block(node *html.Node) []string {
+	switch node.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6", "dt":
+		return nonEmpty(strings.ReplaceAll(r.paragraph(r.inlineChildren(node)), "\n", " "))
+	case "ul", "ol":
+		return nonEmpty(r.list(node))
+	case "li":
+		return nonEmpty(r.listItem(node, "- "))
+	case "pre":
+		if code := strings.Trim(textContent(node), "\n"); strings.TrimSpace(code) != "" {
+			return []string{code}
+		}
+
+		return nil
+	case "table", "thead", "tbody", "tfoot", "tr":
+		return r.table(node)
+	case "hr":
+		return nil
+	default:
+		return r.blocks(children(node))
+	}
+}
+
+func (r *renderer) list(node *html.Node) string {
+	ordered := node.Data == "ol"
+	idx := 1
+
+	if start, err := strconv.Atoi(strings.TrimSpace(getAttr(node, "start"))); err == nil && ordered {
+		idx = start
+	}
+
+	items := make([]string, 0, 8)
+
+	for _, child := range children(node) {
+		if child.Type != html.ElementNode || Skipped(child) {
+			continue
+		}
+
+		switch child.Data {
+		case "li":
+			marker := "- "
+
+			if ordered {
+				marker = strconv.Itoa(idx) + ". "
+				idx++
+			}
+
+			if item := r.listItem(child, marker); item != "" {
+				items = append(items, item)
+			}
+		case "ul", "ol":
+			// lists nested without an item belong to the previous item
+			if nested := r.list(child); nested != "" {
+				items = append(items, prefixLines(nested, "  "))
+			}
+		}
+	}
+
+	return strings.Join(items, "\n")
+}
+
+func (r *renderer) listItem(node *html.Node, marker string) string {
+	content := r.blocks(children(node))
+	if len(content) == 0 {
+		return ""
+	}
+
+	lines := strings.Split(strings.Join(content, "\n"), "\n")
+	indent := strings.Repeat(" ", len(marker))
+
+	for i := range lines {
+		switch {
+		case i == 0:
+			lines[i] = marker + lines[i]
+		case lines[i] != "":
+			lines[i] = indent + lines[i]
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (r *renderer) inline(node *html.Node) string {
+	switch node.Type {
+	case html.TextNode:
+		return collapseSpace(node.Data)
+	case html.ElementNode:
+		if Skipped(node) {
+			return ""
+		}
+
+		switch node.Data {
+		case "br":
+			return lineBreak
+		case "img":
+			return ""
+		case "a":
+			return r.link(node)
+		case "code", "kbd", "samp", "tt":
+			return collapseSpace(textContent(node))
+		}
+
+		content := r.inlineChildren(node)
+
+		// blocks nested in inline content still separate words
+		if isBlock(node) {
+			return " " + content + " "
+		}
+
+		return content
+	default:
+		return ""
+	}
+}
+
+func (r *renderer) inlineChildren(node *html.Node) string {
+	var b strings.Builder
+
+	for _, child := range children(node) {
+		b.WriteString(r.inline(child))
+	}
+
+	return b.String()
+}
+
+func (r *renderer) link(node *html.Node) string {
+	content := r.inlineChildren(node)
+	label := strings.TrimSpace(content)
+	href := strings.TrimSpace(getAttr(node, "href"))
+
+	if !r.opts.Links || label == "" || href == "" || strings.HasPrefix(href, "#") ||
+		strings.HasPrefix(strings.ToLower(href), "javascript:") {
+		return content
+	}
+
+	target := r.resolve(href)
+	if target == label {
+		return content
+	}
+
+	return leadingSpace(content) + label + " (" + target + ")" + trailingSpace(content)
+}
+
+// paragraph finishes inline content: whitespace is collapsed and lines are trimmed.
+func (r *renderer) paragraph(content string) string {
+	lines := strings.Split(content, lineBreak)
+	res := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		if line = strings.TrimSpace(collapseSpace(line)); line != "" {
+			res = append(res, line)
+		}
+	}
+
+	return strings.Join(res, "\n")
+}
+
+func (r *renderer) resolve(ref string) string {
+	if r.opts.BaseURL == nil {
+		return ref
+	}
+
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+
+	return r.opts.BaseURL.ResolveReference(parsed).String()
+}
+
+func nonEmpty(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	return []string{value}
+}
+
+func prefixLines(content, prefix string) string {
+	lines := strings.Split(content, "\n")
+
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,89 @@
+package plaintext_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/MontFerret/contrib/modules/web/html/internal/plaintext"
+)
+
+const region = `
+<section id="offer">
+  <h2>Ferret   <em>Plush</em></h2>
+  <script>track()</script>
+  <p>Soft, <strong>washable</strong> and *cute*. See <a href="/care" title="Care guide">care notes</a>
+     or <a href="javascript:void(0)">nothing</a>.<br>Ships in 2 days.</p>
+  <img src="/img/front.jpg" alt="Front view">
+  <img src="data:image/png;base64,AAAA" alt="pixel">
+  <ul>
+    <li>Size: 30cm</li>
+    <li>Colors
+      <ol start="3"><li>Gray</li><li>White</li></ol>
+    </li>
+  </ul>
+  <table>
+    <caption>Prices</caption>
+    <tr><th>Region</th><th>Price</th></tr>
+    <tr><td>EU</td><td>19 | 21 EUR</td></tr>
+    <tr><td colspan="2">Free shipping</td></tr>
+  </table>
+  <pre><code class="language-js">plush.squeeze()
+plush.hug()</code></pre>
+  <blockquote><p>Best plush ever</p></blockquote>
+  <div hidden>Hidden note</div>
+</section>`
+
+func parse(t *testing.T, content string) []*html.Node {
+	t.Helper()
+
+	doc, err := html.Parse(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	return []*html.Node{doc}
+}
+
+func TestText(t *testing.T) {
+	Convey("Text", t, func() {
+		base, _ := url.Parse("https://shop.example/products/plush")
+
+		Convey("Should convert an element subtree to plain text", func() {
+			out := plaintext.Text(parse(t, region), plaintext.Options{BaseURL: base})
+
+			So(out, ShouldEqual, strings.Join([]string{
+				"Ferret Plush",
+				"Soft, washable and *cute*. See care notes or nothing.\nShips in 2 days.",
+				"- Size: 30cm\n- Colors\n  3. Gray\n  4. White",
+				"Prices",
+				"Region\tPrice\nEU\t19 | 21 EUR\nFree shipping",
+				"plush.squeeze()\nplush.hug()",
+				"Best plush ever",
+			}, "\n\n"))
+		})
+
+		Convey("Should render detached table rows", func() {
+			nodes, err := html.ParseFragment(strings.NewReader(`<tr><td>EU</td><td>19 EUR</td></tr>`), &html.Node{
+				Type:     html.ElementNode,
+				Data:     "template",
+				DataAtom: atom.Template,
+			})
+			So(err, ShouldBeNil)
+
+			So(plaintext.Text(nodes, plaintext.Options{Tables: true}), ShouldEqual, "| EU | 19 EUR |\n| --- | --- |")
+		})
+
+		Convey("Should preserve links and render tables when enabled", func() {
+			out := plaintext.Text(parse(t, region), plaintext.Options{BaseURL: base, Links: true, Tables: true})
+
+			So(out, ShouldContainSubstring, "See care notes (https://shop.example/care) or nothing.")
+			So(out, ShouldContainSubstring, "| Region | Price |\n| --- | --- |")
+		})
+	})
+}
@@ -0,0 +1,113 @@
+package plaintext
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// maxColspan caps the columns a single cell can span.
+const maxColspan = 100
+
+// table renders the caption and the rows of a table, a table section or a single row.
+// GFM tables need a header, so the first row is promoted to one when the table has none.
+func (r *renderer) table(node *html.Node) []string {
+	res := make([]string, 0, 2)
+	rows := make([][]string, 0, 8)
+
+	var collect func(parent *html.Node)
+
+	collect = func(parent *html.Node) {
+		for _, child := range children(parent) {
+			if child.Type != html.ElementNode || Skipped(child) {
+				continue
+			}
+
+			switch child.Data {
+			case "caption":
+				if caption := r.paragraph(r.inlineChildren(child)); caption != "" {
+					res = append(res, strings.ReplaceAll(caption, "\n", " "))
+				}
+			case "thead", "tbody", "tfoot":
+				collect(child)
+			case "tr":
+				if row := r.tableRow(child); len(row) > 0 {
+					rows = append(rows, row)
+				}
+			}
+		}
+	}
+
+	if node.Data == "tr" {
+		if row := r.tableRow(node); len(row) > 0 {
+			rows = append(rows, row)
+		}
+	} else {
+		collect(node)
+	}
+
+	if len(rows) == 0 {
+		return res
+	}
+
+	width := 0
+
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+
+	lines := make([]string, 0, len(rows)+1)
+
+	for i, row := range rows {
+		for len(row) < width {
+			row = append(row, "")
+		}
+
+		if !r.opts.Tables {
+			lines = append(lines, strings.TrimRight(strings.Join(row, "\t"), "\t"))
+
+			continue
+		}
+
+		lines = append(lines, "| "+strings.Join(row, " | ")+" |")
+
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", width))
+		}
+	}
+
+	return append(res, strings.Join(lines, "\n"))
+}
+
+func (r *renderer) tableRow(node *html.Node) []string {
+	row := make([]string, 0, 8)
+
+	for _, cell := range children(node) {
+		if cell.Type != html.ElementNode || (cell.Data != "td" && cell.Data != "th") || Skipped(cell) {
+			continue
+		}
+
+		content := strings.ReplaceAll(strings.Join(r.blocks(children(cell)), " "), "\n", " ")
+		content = strings.ReplaceAll(content, "  ", " ")
+
+		if r.opts.Tables {
+			content = strings.ReplaceAll(content, "|", `\|`)
+		} else {
+			content = strings.ReplaceAll(content, "\t", " ")
+		}
+
+		row = append(row, content)
+
+		span, err := strconv.Atoi(strings.TrimSpace(getAttr(cell, "colspan")))
+		if err != nil {
+			continue
+		}
+
+		for i := 1; i < min(span, maxColspan); i++ {
+			row = append(row, "")
+		}
+	}
+
+	return row
+}
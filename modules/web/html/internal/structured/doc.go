@@ -0,0 +1,2 @@
+// Package structured extracts JSON-LD, Microdata, RDFa Lite, OpenGraph and Twitter Card data from HTML documents.
+package structured
@@ -0,0 +1,39 @@
+package structured
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/goccy/go-json"
+)
+
+// extractJSONLD parses every JSON-LD script block.
+// Top-level arrays are flattened and blocks that are not valid JSON are skipped.
+func extractJSONLD(doc *goquery.Document) []any {
+	res := make([]any, 0)
+
+	doc.Find("script[type]").Each(func(_ int, s *goquery.Selection) {
+		mediaType, _, _ := strings.Cut(s.AttrOr("type", ""), ";")
+
+		if !strings.EqualFold(strings.TrimSpace(mediaType), "application/ld+json") {
+			return
+		}
+
+		content := strings.TrimSpace(s.Text())
+		content = strings.TrimSuffix(strings.TrimPrefix(content, "<!--"), "-->")
+
+		var value any
+
+		if err := json.Unmarshal([]byte(content), &value); err != nil {
+			return
+		}
+
+		if list, ok := value.([]any); ok {
+			res = append(res, list...)
+		} else if value != nil {
+			res = append(res, value)
+		}
+	})
+
+	return res
+}
@@ -0,0 +1,77 @@
+package structured
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// openGraphNamespaces are the OpenGraph object type prefixes kept in the property names.
+var openGraphNamespaces = []string{"article:", "book:", "books:", "fb:", "music:", "product:", "profile:", "video:"}
+
+// extractOpenGraph collects OpenGraph meta tags.
+// The og: prefix is dropped, object type properties such as article:author keep theirs.
+func extractOpenGraph(doc *goquery.Document, base *url.URL) map[string]any {
+	return extractMeta(doc, base, func(name string) (string, bool) {
+		if key, ok := strings.CutPrefix(name, "og:"); ok {
+			return key, true
+		}
+
+		for _, ns := range openGraphNamespaces {
+			if strings.HasPrefix(name, ns) {
+				return name, true
+			}
+		}
+
+		return "", false
+	})
+}
+
+// extractTwitter collects Twitter Card meta tags without the twitter: prefix.
+func extractTwitter(doc *goquery.Document, base *url.URL) map[string]any {
+	return extractMeta(doc, base, func(name string) (string, bool) {
+		return strings.CutPrefix(name, "twitter:")
+	})
+}
+
+// extractMeta collects meta tags accepted by match, looking at both property and name attributes.
+// Repeated properties become arrays and URL-valued properties are resolved.
+func extractMeta(doc *goquery.Document, base *url.URL, match func(name string) (string, bool)) map[string]any {
+	res := make(map[string]any)
+
+	doc.Find("meta[content]").Each(func(_ int, s *goquery.Selection) {
+		name := s.AttrOr("property", s.AttrOr("name", ""))
+		key, ok := match(strings.ToLower(strings.TrimSpace(name)))
+
+		if !ok || key == "" {
+			return
+		}
+
+		value := strings.TrimSpace(s.AttrOr("content", ""))
+
+		if isURLProperty(key) {
+			value = resolveURL(base, value)
+		}
+
+		switch current := res[key].(type) {
+		case nil:
+			res[key] = value
+		case []any:
+			res[key] = append(current, value)
+		default:
+			res[key] = []any{current, value}
+		}
+	})
+
+	return res
+}
+
+func isURLProperty(key string) bool {
+	switch key {
+	case "url", "image", "image:src", "video", "audio", "player":
+		return true
+	}
+
+	return strings.HasSuffix(key, ":url") || strings.HasSuffix(key, ":secure_url")
+}
@@ -0,0 +1,147 @@
+package structured
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+type (
+	microdataParser struct {
+		doc    *goquery.Document
+		base   *url.URL
+		order  map[*html.Node]int
+		memory map[*html.Node]bool
+	}
+
+	microdataProperty struct {
+		node  *html.Node
+		value any
+		names []string
+	}
+)
+
+// extractMicrodata returns the top-level Microdata items following the WHATWG
+// algorithm: items are elements with itemscope and without itemprop, properties
+// are collected from descendants and from the elements listed in itemref.
+func extractMicrodata(doc *goquery.Document, base *url.URL) []*Item {
+	p := &microdataParser{
+		doc:    doc,
+		base:   base,
+		order:  make(map[*html.Node]int),
+		memory: make(map[*html.Node]bool),
+	}
+
+	items := make([]*Item, 0)
+
+	doc.Find("*").Each(func(i int, s *goquery.Selection) {
+		p.order[s.Get(0)] = i
+	})
+
+	doc.Find("[itemscope]").Each(func(_ int, s *goquery.Selection) {
+		if _, ok := s.Attr("itemprop"); ok {
+			return
+		}
+
+		items = append(items, p.item(s))
+	})
+
+	return items
+}
+
+func (p *microdataParser) item(scope *goquery.Selection) *Item {
+	node := scope.Get(0)
+	item := newItem(strings.TrimSpace(scope.AttrOr("itemid", "")), strings.Fields(scope.AttrOr("itemtype", "")))
+
+	if item.ID != "" {
+		item.ID = resolveURL(p.base, item.ID)
+	}
+
+	p.memory[node] = true
+	defer delete(p.memory, node)
+
+	properties := p.properties(scope)
+
+	sort.SliceStable(properties, func(i, j int) bool {
+		return p.order[properties[i].node] < p.order[properties[j].node]
+	})
+
+	for _, prop := range properties {
+		item.add(prop.names, prop.value)
+	}
+
+	return item
+}
+
+func (p *microdataParser) properties(scope *goquery.Selection) []microdataProperty {
+	res := make([]microdataProperty, 0)
+	visited := map[*html.Node]bool{scope.Get(0): true}
+	pending := scope.Children().Nodes
+
+	for _, id := range strings.Fields(scope.AttrOr("itemref", "")) {
+		ref := p.doc.Find("[id]").FilterFunction(func(_ int, s *goquery.Selection) bool {
+			return s.AttrOr("id", "") == id
+		}).First()
+
+		pending = append(pending, ref.Nodes...)
+	}
+
+	for len(pending) > 0 {
+		node := pending[0]
+		pending = pending[1:]
+
+		if visited[node] {
+			continue
+		}
+
+		visited[node] = true
+		el := p.doc.FindNodes(node)
+		_, isScope := el.Attr("itemscope")
+
+		if names := strings.Fields(el.AttrOr("itemprop", "")); len(names) > 0 {
+			prop := microdataProperty{node: node, names: names}
+
+			switch {
+			case !isScope:
+				prop.value = microdataValue(el, p.base)
+			case p.memory[node]:
+				// an item referencing one of its ancestors
+				prop.value = "ERROR"
+			default:
+				prop.value = p.item(el)
+			}
+
+			res = append(res, prop)
+		}
+
+		if !isScope {
+			pending = append(pending, el.Children().Nodes...)
+		}
+	}
+
+	return res
+}
+
+func microdataValue(el *goquery.Selection, base *url.URL) string {
+	switch goquery.NodeName(el) {
+	case "meta":
+		return el.AttrOr("content", "")
+	case "audio", "embed", "iframe", "img", "source", "track", "video":
+		return resolveURL(base, el.AttrOr("src", ""))
+	case "a", "area", "link":
+		return resolveURL(base, el.AttrOr("href", ""))
+	case "object":
+		return resolveURL(base, el.AttrOr("data", ""))
+	case "data", "meter":
+		return el.AttrOr("value", "")
+	case "time":
+		if value, ok := el.Attr("datetime"); ok {
+			return value
+		}
+	}
+
+	return text(el)
+}
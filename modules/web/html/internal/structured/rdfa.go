@@ -0,0 +1,135 @@
+package structured
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// initialPrefixes is the subset of the RDFa initial context that is common in web pages.
+var initialPrefixes = map[string]string{
+	"dc":      "http://purl.org/dc/terms/",
+	"dcterms": "http://purl.org/dc/terms/",
+	"foaf":    "http://xmlns.com/foaf/0.1/",
+	"og":      "http://ogp.me/ns#",
+	"rdf":     "http://www.w3.org/1999/02/22-rdf-syntax-ns#",
+	"rdfs":    "http://www.w3.org/2000/01/rdf-schema#",
+	"schema":  "http://schema.org/",
+	"xsd":     "http://www.w3.org/2001/XMLSchema#",
+}
+
+// extractRDFa returns the RDFa Lite items of the document.
+// An item is created for every typeof attribute; properties outside of any item,
+// such as OpenGraph meta tags, are not reported here.
+func extractRDFa(doc *goquery.Document, base *url.URL) []*Item {
+	items := make([]*Item, 0)
+
+	doc.Find("[typeof]").Each(func(_ int, s *goquery.Selection) {
+		_, isProperty := s.Attr("property")
+
+		if isProperty && s.ParentsFiltered("[typeof]").Length() > 0 {
+			return
+		}
+
+		items = append(items, rdfaItem(s, base))
+	})
+
+	return items
+}
+
+func rdfaItem(scope *goquery.Selection, base *url.URL) *Item {
+	id := scope.AttrOr("resource", scope.AttrOr("about", ""))
+
+	if id != "" {
+		id = resolveURL(base, id)
+	}
+
+	terms := strings.Fields(scope.AttrOr("typeof", ""))
+	types := make([]string, 0, len(terms))
+
+	for _, term := range terms {
+		types = append(types, expandTerm(scope, term))
+	}
+
+	item := newItem(id, types)
+
+	rdfaProperties(item, scope.Children(), base)
+
+	return item
+}
+
+func rdfaProperties(item *Item, children *goquery.Selection, base *url.URL) {
+	children.Each(func(_ int, el *goquery.Selection) {
+		_, isScope := el.Attr("typeof")
+
+		if names := strings.Fields(el.AttrOr("property", "")); len(names) > 0 {
+			if isScope {
+				item.add(names, rdfaItem(el, base))
+			} else {
+				item.add(names, rdfaValue(el, base))
+			}
+		}
+
+		if !isScope {
+			rdfaProperties(item, el.Children(), base)
+		}
+	})
+}
+
+func rdfaValue(el *goquery.Selection, base *url.URL) string {
+	if value, ok := el.Attr("content"); ok {
+		return value
+	}
+
+	for _, attr := range []string{"resource", "href", "src"} {
+		if value, ok := el.Attr(attr); ok {
+			return resolveURL(base, value)
+		}
+	}
+
+	if value, ok := el.Attr("datetime"); ok {
+		return value
+	}
+
+	return text(el)
+}
+
+// expandTerm turns a type term into an IRI using the prefix and vocab attributes in scope.
+func expandTerm(el *goquery.Selection, term string) string {
+	if prefix, reference, ok := strings.Cut(term, ":"); ok {
+		if strings.HasPrefix(reference, "//") {
+			return term
+		}
+
+		if iri, found := lookupPrefix(el, prefix); found {
+			return iri + reference
+		}
+
+		return term
+	}
+
+	for s := el; s.Length() > 0; s = s.Parent() {
+		if vocab, ok := s.Attr("vocab"); ok {
+			return strings.TrimSpace(vocab) + term
+		}
+	}
+
+	return term
+}
+
+func lookupPrefix(el *goquery.Selection, prefix string) (string, bool) {
+	for s := el; s.Length() > 0; s = s.Parent() {
+		fields := strings.Fields(s.AttrOr("prefix", ""))
+
+		for i := 0; i+1 < len(fields); i += 2 {
+			if strings.TrimSuffix(fields[i], ":") == prefix {
+				return fields[i+1], true
+			}
+		}
+	}
+
+	iri, ok := initialPrefixes[prefix]
+
+	return iri, ok
+}
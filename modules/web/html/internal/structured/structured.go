@@ -0,0 +1,154 @@
+package structured
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type (
+	// Data holds the structured data found in a document.
+	Data struct {
+		OpenGraph map[string]any
+		Twitter   map[string]any
+		JSONLD    []any
+		Microdata []*Item
+		RDFa      []*Item
+	}
+
+	// Item is a Microdata or RDFa item.
+	// Property values are strings or nested items, in document order.
+	Item struct {
+		Properties map[string][]any
+		ID         string
+		Type       []string
+	}
+)
+
+// Extract collects the structured data of the document.
+// Relative URLs are resolved against the <base> element or, without one, against baseURL.
+func Extract(doc *goquery.Document, baseURL *url.URL) Data {
+	base := resolveBase(doc, baseURL)
+
+	return Data{
+		JSONLD:    extractJSONLD(doc),
+		Microdata: extractMicrodata(doc, base),
+		RDFa:      extractRDFa(doc, base),
+		OpenGraph: extractOpenGraph(doc, base),
+		Twitter:   extractTwitter(doc, base),
+	}
+}
+
+// Value converts the data into plain maps and slices.
+func (d Data) Value() map[string]any {
+	return map[string]any{
+		"jsonLd":    append(make([]any, 0, len(d.JSONLD)), d.JSONLD...),
+		"microdata": itemValues(d.Microdata),
+		"rdfa":      itemValues(d.RDFa),
+		"openGraph": d.OpenGraph,
+		"twitter":   d.Twitter,
+	}
+}
+
+// Value converts the item into a plain map with "type", "properties" and an optional "id".
+func (i *Item) Value() map[string]any {
+	types := make([]any, 0, len(i.Type))
+
+	for _, t := range i.Type {
+		types = append(types, t)
+	}
+
+	properties := make(map[string]any, len(i.Properties))
+
+	for name, values := range i.Properties {
+		list := make([]any, 0, len(values))
+
+		for _, value := range values {
+			if nested, ok := value.(*Item); ok {
+				list = append(list, nested.Value())
+			} else {
+				list = append(list, value)
+			}
+		}
+
+		properties[name] = list
+	}
+
+	res := map[string]any{
+		"type":       types,
+		"properties": properties,
+	}
+
+	if i.ID != "" {
+		res["id"] = i.ID
+	}
+
+	return res
+}
+
+func newItem(id string, types []string) *Item {
+	if types == nil {
+		types = []string{}
+	}
+
+	return &Item{
+		ID:         id,
+		Type:       types,
+		Properties: make(map[string][]any),
+	}
+}
+
+func (i *Item) add(names []string, value any) {
+	for _, name := range names {
+		i.Properties[name] = append(i.Properties[name], value)
+	}
+}
+
+func itemValues(items []*Item) []any {
+	res := make([]any, 0, len(items))
+
+	for _, item := range items {
+		res = append(res, item.Value())
+	}
+
+	return res
+}
+
+func resolveBase(doc *goquery.Document, baseURL *url.URL) *url.URL {
+	href, ok := doc.Find("base[href]").First().Attr("href")
+	if !ok {
+		return baseURL
+	}
+
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return baseURL
+	}
+
+	if baseURL == nil {
+		return ref
+	}
+
+	return baseURL.ResolveReference(ref)
+}
+
+func resolveURL(base *url.URL, value string) string {
+	value = strings.TrimSpace(value)
+
+	if base == nil || value == "" {
+		return value
+	}
+
+	ref, err := url.Parse(value)
+	if err != nil {
+		return value
+	}
+
+	return base.ResolveReference(ref).String()
+}
+
+// text returns the text content of the selection with collapsed whitespace.
+func text(s *goquery.Selection) string {
+	return strings.Join(strings.Fields(s.Text()), " ")
+}
@@ -0,0 +1,148 @@
+package structured_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/MontFerret/contrib/modules/web/html/internal/structured"
+)
+
+const productPage = `
+<html prefix="og: http://ogp.me/ns#">
+  <head>
+    <title>Ferret Plush</title>
+    <meta property="og:title" content="Ferret Plush" />
+    <meta property="og:type" content="product" />
+    <meta property="og:image" content="/img/front.jpg" />
+    <meta property="og:image" content="/img/back.jpg" />
+    <meta property="product:price:amount" content="19.99" />
+    <meta name="twitter:card" content="summary_large_image" />
+    <meta name="twitter:image" content="/img/card.jpg" />
+    <script type="application/ld+json">
+      {"@context": "https://schema.org", "@type": "Product", "name": "Ferret Plush", "offers": {"@type": "Offer", "price": 19.99}}
+    </script>
+    <script type="application/ld+json">
+      [{"@type": "BreadcrumbList"}, {"@type": "Organization", "name": "Ferret Toys"}]
+    </script>
+    <script type="application/ld+json">{ not json }</script>
+  </head>
+  <body>
+    <div itemscope itemtype="https://schema.org/Product" itemid="/products/42" itemref="reviews">
+      <h1 itemprop="name">Ferret   Plush</h1>
+      <img itemprop="image" src="/img/front.jpg" />
+      <div itemprop="offers" itemscope itemtype="https://schema.org/Offer">
+        <meta itemprop="priceCurrency" content="USD" />
+        <data itemprop="price" value="19.99">$19.99</data>
+        <link itemprop="availability" href="https://schema.org/InStock" />
+      </div>
+    </div>
+    <section id="reviews">
+      <span itemprop="ratingValue">4.5</span>
+    </section>
+    <div vocab="https://schema.org/" typeof="Person" resource="#jane">
+      <span property="name">Jane Doe</span>
+      <a property="url" href="/jane">Profile</a>
+      <div property="address" typeof="PostalAddress">
+        <span property="addressLocality">Springfield</span>
+      </div>
+      <div typeof="og:Thing"><span property="name">Detached</span></div>
+    </div>
+  </body>
+</html>`
+
+func extract(t *testing.T, content string) structured.Data {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base, _ := url.Parse("https://shop.example.com/catalog/ferret")
+
+	return structured.Extract(doc, base)
+}
+
+func TestExtract(t *testing.T) {
+	data := extract(t, productPage)
+
+	Convey("Should parse JSON-LD blocks", t, func() {
+		So(data.JSONLD, ShouldHaveLength, 3)
+		So(data.JSONLD[0].(map[string]any)["name"], ShouldEqual, "Ferret Plush")
+		So(data.JSONLD[2].(map[string]any)["@type"], ShouldEqual, "Organization")
+	})
+
+	Convey("Should build Microdata item trees", t, func() {
+		So(data.Microdata, ShouldHaveLength, 1)
+
+		product := data.Microdata[0]
+		So(product.ID, ShouldEqual, "https://shop.example.com/products/42")
+		So(product.Type, ShouldResemble, []string{"https://schema.org/Product"})
+		So(product.Properties["name"], ShouldResemble, []any{"Ferret Plush"})
+		So(product.Properties["image"], ShouldResemble, []any{"https://shop.example.com/img/front.jpg"})
+		So(product.Properties["ratingValue"], ShouldResemble, []any{"4.5"})
+
+		offer := product.Properties["offers"][0].(*structured.Item)
+		So(offer.Properties["priceCurrency"], ShouldResemble, []any{"USD"})
+		So(offer.Properties["price"], ShouldResemble, []any{"19.99"})
+		So(offer.Properties["availability"], ShouldResemble, []any{"https://schema.org/InStock"})
+	})
+
+	Convey("Should build RDFa items", t, func() {
+		So(data.RDFa, ShouldHaveLength, 2)
+
+		person := data.RDFa[0]
+		So(person.ID, ShouldEqual, "https://shop.example.com/catalog/ferret#jane")
+		So(person.Type, ShouldResemble, []string{"https://schema.org/Person"})
+		So(person.Properties["name"], ShouldResemble, []any{"Jane Doe"})
+		So(person.Properties["url"], ShouldResemble, []any{"https://shop.example.com/jane"})
+
+		address := person.Properties["address"][0].(*structured.Item)
+		So(address.Type, ShouldResemble, []string{"https://schema.org/PostalAddress"})
+		So(address.Properties["addressLocality"], ShouldResemble, []any{"Springfield"})
+
+		So(data.RDFa[1].Type, ShouldResemble, []string{"http://ogp.me/ns#Thing"})
+		So(data.RDFa[1].Properties["name"], ShouldResemble, []any{"Detached"})
+	})
+
+	Convey("Should collect OpenGraph and Twitter Card tags", t, func() {
+		So(data.OpenGraph["title"], ShouldEqual, "Ferret Plush")
+		So(data.OpenGraph["image"], ShouldResemble, []any{
+			"https://shop.example.com/img/front.jpg",
+			"https://shop.example.com/img/back.jpg",
+		})
+		So(data.OpenGraph["product:price:amount"], ShouldEqual, "19.99")
+		So(data.Twitter["card"], ShouldEqual, "summary_large_image")
+		So(data.Twitter["image"], ShouldEqual, "https://shop.example.com/img/card.jpg")
+	})
+
+	Convey("Should keep Microdata properties in tree order", t, func() {
+		data := extract(t, `<div itemscope><div><span itemprop="a">1</span></div><span itemprop="a">2</span></div>`)
+
+		So(data.Microdata[0].Properties["a"], ShouldResemble, []any{"1", "2"})
+	})
+
+	Convey("Should stop Microdata reference cycles", t, func() {
+		data := extract(t, `<div itemscope id="loop"><div itemprop="self" itemscope itemref="loop"></div></div>`)
+
+		So(data.Microdata, ShouldHaveLength, 1)
+		So(data.Microdata[0].Properties["self"], ShouldHaveLength, 1)
+	})
+}
+
+func TestData_Value(t *testing.T) {
+	Convey("Should convert items into plain maps", t, func() {
+		value := extract(t, productPage).Value()
+
+		microdata := value["microdata"].([]any)
+		product := microdata[0].(map[string]any)
+		offers := product["properties"].(map[string]any)["offers"].([]any)
+
+		So(product["type"], ShouldResemble, []any{"https://schema.org/Product"})
+		So(offers[0].(map[string]any)["type"], ShouldResemble, []any{"https://schema.org/Offer"})
+		So(value["jsonLd"], ShouldHaveLength, 3)
+	})
+}
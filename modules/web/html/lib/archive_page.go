@@ -0,0 +1,91 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+	"github.com/MontFerret/ferret/v2/pkg/sdk"
+)
+
+// ArchivePage captures a page or URL together with its resources.
+//
+// The "mhtml" format (default) returns a multipart/related archive, the "html" format
+// returns a single HTML document with stylesheets, images, and frames inlined.
+//
+// @param target {HTMLPage|String} Page or URL to archive.
+// @param params {Object?} Archive options: format.
+// @return {Binary} Archive bytes.
+func ArchivePage(ctx context.Context, args ...runtime.Value) (runtime.Value, error) {
+	err := runtime.ValidateArgs(args, 1, 2)
+
+	if err != nil {
+		return runtime.None, err
+	}
+
+	arg1 := args[0]
+
+	err = runtime.ValidateType(arg1, drivers.HTMLPageType, runtime.TypeString)
+
+	if err != nil {
+		return runtime.None, err
+	}
+
+	archiveParams := drivers.ArchiveParams{Format: drivers.ArchiveFormatMHTML}
+
+	if len(args) == 2 {
+		values, err := runtime.CastMap(args[1])
+
+		if err != nil {
+			return runtime.None, err
+		}
+
+		parsed, err := parseArchiveParams(ctx, values)
+
+		if err != nil {
+			return runtime.None, err
+		}
+
+		archiveParams = parsed
+	}
+
+	page, closeAfter, err := OpenOrCastPage(ctx, arg1)
+
+	if err != nil {
+		return runtime.None, err
+	}
+
+	defer func() {
+		if closeAfter {
+			page.Close()
+		}
+	}()
+
+	target, err := drivers.ToPageArchiveTarget(page)
+	if err != nil {
+		return runtime.None, err
+	}
+
+	archive, err := target.Archive(ctx, archiveParams)
+
+	if err != nil {
+		return runtime.None, err
+	}
+
+	return archive, nil
+}
+
+func parseArchiveParams(ctx context.Context, values runtime.Map) (drivers.ArchiveParams, error) {
+	res := drivers.ArchiveParams{Format: drivers.ArchiveFormatMHTML}
+
+	if err := sdk.Decode(ctx, values, &res, sdk.DisallowUnknownFields()); err != nil {
+		return drivers.ArchiveParams{}, err
+	}
+
+	if !drivers.IsArchiveFormatValid(string(res.Format)) {
+		return drivers.ArchiveParams{}, fmt.Errorf("unsupported format: %s", res.Format)
+	}
+
+	return res, nil
+}
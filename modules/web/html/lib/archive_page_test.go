@@ -0,0 +1,44 @@
+package lib
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+func TestArchivePage(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	page := newMemoryPage(t, `<html><head><title>Prices</title></head><body><img src="/logo.png"></body></html>`, nil)
+
+	out, err := ArchivePage(ctx, page, runtime.NewObjectWith(map[string]runtime.Value{
+		"format": runtime.NewString("html"),
+	}))
+	if err != nil {
+		t.Fatalf("archive page: %v", err)
+	}
+
+	content := string(out.(runtime.Binary))
+
+	if !strings.Contains(content, `<base href="https://example.com"/>`) || !strings.Contains(content, `<img src="https://example.com/logo.png"/>`) {
+		t.Fatalf("unexpected html archive: %s", content)
+	}
+
+	out, err = ArchivePage(ctx, page)
+	if err != nil {
+		t.Fatalf("archive page: %v", err)
+	}
+
+	if content := string(out.(runtime.Binary)); !strings.Contains(content, "Snapshot-Content-Location: https://example.com") {
+		t.Fatalf("expected mhtml archive by default, got %s", content)
+	}
+
+	if _, err := ArchivePage(ctx, page, runtime.NewObjectWith(map[string]runtime.Value{
+		"format": runtime.NewString("pdf"),
+	})); err == nil {
+		t.Fatal("expected unsupported format error")
+	}
+}
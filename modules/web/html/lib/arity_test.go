@@ -29,12 +29,13 @@ func TestExactArityFunctionsUseFixedRegistries(t *testing.T) {
 			}
 
 			definitions := ns.Function()
-			assertFixedArity(t, definitions.A1(), definitions.Var(), "DOWNLOAD")
+			assertFixedArity(t, definitions.A1(), definitions.Var(), "DOWNLOAD", "STRUCTURED_DATA")
 			assertFixedArity(
 				t,
 				definitions.A2(),
 				definitions.Var(),
 				"COOKIE_GET",
+				"COOKIES_IMPORT",
 				"ELEMENT",
 				"ELEMENT_EXISTS",
 				"ELEMENTS",
@@ -42,6 +43,8 @@ func TestExactArityFunctionsUseFixedRegistries(t *testing.T) {
 				"INNER_HTML_ALL",
 				"INNER_TEXT_ALL",
 				"PAGINATION",
+				"SCROLL_PAGINATION",
+				"TAP",
 			)
 			assertFixedArity(t, definitions.A3(), definitions.Var(), "MOUSE")
 		})
@@ -0,0 +1,53 @@
+package lib
+
+import (
+	"context"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+// CookiesExport serializes page cookies as a cookie jar.
+// The Netscape format is the cookies.txt file read by curl and wget.
+//
+// @param page {HTMLPage} Target page.
+// @param format {String} [format="netscape"] Jar format, "netscape" or "json".
+// @return {String} Serialized cookies.
+func CookiesExport(ctx context.Context, args ...runtime.Value) (runtime.Value, error) {
+	if err := runtime.ValidateArgs(args, 1, 2); err != nil {
+		return runtime.None, err
+	}
+
+	target, err := drivers.ToPageCookieReader(args[0])
+	if err != nil {
+		return runtime.None, err
+	}
+
+	format := drivers.CookieJarFormatNetscape
+
+	if len(args) > 1 {
+		if err := runtime.ValidateArgType(args[1], 1, runtime.TypeString); err != nil {
+			return runtime.None, err
+		}
+
+		format = args[1].String()
+	}
+
+	cookies, err := target.GetCookies(ctx)
+	if err != nil {
+		return runtime.None, err
+	}
+
+	list := make([]drivers.HTTPCookie, 0, len(cookies.Data))
+
+	for _, cookie := range cookies.Data {
+		list = append(list, cookie)
+	}
+
+	out, err := drivers.EncodeCookies(list, format)
+	if err != nil {
+		return runtime.None, err
+	}
+
+	return runtime.NewString(string(out)), nil
+}
@@ -0,0 +1,53 @@
+package lib
+
+import (
+	"context"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+// CookiesImport sets page cookies from a cookie jar.
+// Jars in the Netscape cookies.txt and JSON formats are detected automatically.
+//
+// @param page {HTMLPage} Target page.
+// @param data {String|Binary|Any} Serialized cookie jar, or cookie values.
+// @return {None} No value.
+func CookiesImport(ctx context.Context, root, data runtime.Value) (runtime.Value, error) {
+	target, err := drivers.ToPageCookieTarget(root)
+	if err != nil {
+		return runtime.None, err
+	}
+
+	var cookies *drivers.HTTPCookies
+
+	switch v := data.(type) {
+	case runtime.String:
+		cookies, err = decodeCookieJar([]byte(v))
+	case runtime.Binary:
+		cookies, err = decodeCookieJar(v)
+	default:
+		cookies, err = parseCookiesValue(ctx, data)
+	}
+
+	if err != nil {
+		return runtime.None, err
+	}
+
+	return runtime.None, target.SetCookies(ctx, cookies)
+}
+
+func decodeCookieJar(data []byte) (*drivers.HTTPCookies, error) {
+	list, err := drivers.DecodeCookies(data)
+	if err != nil {
+		return nil, err
+	}
+
+	cookies := drivers.NewHTTPCookies()
+
+	for _, cookie := range list {
+		cookies.SetCookie(cookie)
+	}
+
+	return cookies, nil
+}
@@ -26,15 +26,20 @@ type (
 		Viewport    *drivers.Viewport    `json:"viewport"`
 		Ignore      *drivers.Ignore      `json:"ignore"`
 		Charset     *string              `json:"charset"`
+		Proxy       *string              `json:"proxy"`
 		InitScript  *drivers.InitScript  `json:"initScript"`
+		Stealth     runtime.Value        `json:"stealth"`
 	}
 )
 
 // Open loads an HTML page from a URL.
 //
 // Options may select a driver, timeout, user agent, cookie reuse, cookies,
-// headers, ignored resources or status codes, viewport, source charset, and an
-// initScript.
+// headers, ignored resources or status codes, viewport, source charset, a
+// proxy, an initScript, and a stealth profile.
+// For CDP, a proxy opens the page in its own browser context.
+// For CDP, stealth is true or an object with languages, platform, webglVendor,
+// webglRenderer and a skip list of fingerprint patches.
 // For CDP, beforeDocument uses the browser's new-document
 // mechanism; same-target frames inherit it subject to browser target limits.
 // afterNavigation runs after Ferret's controlled navigation reaches main-frame
@@ -136,6 +141,10 @@ func newPageLoadParams(ctx context.Context, url runtime.String, arg runtime.Valu
 			res.Charset = *input.Charset
 		}
 
+		if input.Proxy != nil {
+			res.Proxy = *input.Proxy
+		}
+
 		if input.InitScript != nil {
 			initScript, err := drivers.NormalizeInitScript(input.InitScript)
 			if err != nil {
@@ -145,6 +154,15 @@ func newPageLoadParams(ctx context.Context, url runtime.String, arg runtime.Valu
 			res.InitScript = initScript
 		}
 
+		if input.Stealth != nil && input.Stealth != runtime.None {
+			stealth, err := parseStealth(ctx, input.Stealth)
+			if err != nil {
+				return PageLoadParams{}, err
+			}
+
+			res.Stealth = stealth
+		}
+
 		if input.Cookies != nil && input.Cookies != runtime.None {
 			cookies, err := parseCookiesValue(ctx, input.Cookies)
 			if err != nil {
@@ -159,3 +177,25 @@ func newPageLoadParams(ctx context.Context, url runtime.String, arg runtime.Valu
 
 	return res, nil
 }
+
+func parseStealth(ctx context.Context, value runtime.Value) (*drivers.Stealth, error) {
+	if err := runtime.ValidateType(value, runtime.TypeBoolean, runtime.TypeMap); err != nil {
+		return nil, err
+	}
+
+	var profile drivers.Stealth
+
+	switch v := value.(type) {
+	case runtime.Boolean:
+		if !v {
+			// kept as a disabled profile, so that it overrides a profile set on the driver
+			return &drivers.Stealth{Disabled: true}, nil
+		}
+	case runtime.Map:
+		if err := sdk.Decode(ctx, v, &profile, sdk.DisallowUnknownFields()); err != nil {
+			return nil, err
+		}
+	}
+
+	return drivers.NormalizeStealth(&profile)
+}
@@ -0,0 +1,51 @@
+package lib
+
+import (
+	"context"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+// Drag drags with the mouse from one point to another.
+//
+// Points are element selectors or { x, y } viewport coordinates.
+// HTML5 drag-and-drop operations started by the movement are dropped at the target point.
+//
+// @param root {HTMLPage|HTMLDocument} Page or document.
+// @param from {String|Object} Selector or point to start dragging from.
+// @param to {String|Object} Selector or point to drop at.
+// @param options {Object?} Options with the number of mouse move steps.
+// @return {Boolean} True when the drag completes.
+func Drag(ctx context.Context, args ...runtime.Value) (runtime.Value, error) {
+	if err := runtime.ValidateArgs(args, 3, 4); err != nil {
+		return runtime.False, err
+	}
+
+	doc, err := drivers.ToDocumentGestureTarget(args[0])
+	if err != nil {
+		return runtime.False, err
+	}
+
+	from, err := toGesturePoint(ctx, args[1])
+	if err != nil {
+		return runtime.False, err
+	}
+
+	to, err := toGesturePoint(ctx, args[2])
+	if err != nil {
+		return runtime.False, err
+	}
+
+	var opts drivers.GestureOptions
+
+	if len(args) > 3 {
+		opts, err = toGestureOptions(ctx, args[3])
+
+		if err != nil {
+			return runtime.False, err
+		}
+	}
+
+	return runtime.True, doc.Drag(ctx, from, to, opts)
+}
@@ -0,0 +1,111 @@
+package lib
+
+import (
+	"context"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+	"github.com/MontFerret/ferret/v2/pkg/sdk"
+)
+
+// FillForm fills form controls by field name, id, label text or CSS selector.
+//
+// Text controls are typed into, checkboxes and radio buttons are checked by boolean or matching values,
+// and selects take a value or a list of values. The memory driver sets values for later serialization only.
+// Fields of an object are filled in name order; an array of {name, value} objects is filled in its own order.
+//
+// @param root {HTMLPage|HTMLDocument|HTMLElement} HTML root or form element.
+// @param selectorOrValues {String|Object|Object[]} Form selector or field values.
+// @param valuesOrOptions {Object|Object[]?} Field values when a selector is supplied, otherwise options.
+// @param options {Object?} Options with submit and the typing delay in milliseconds.
+// @return {Object} Object with filled and unmatched field names.
+func FillForm(ctx context.Context, args ...runtime.Value) (runtime.Value, error) {
+	if err := runtime.ValidateArgs(args, 2, 4); err != nil {
+		return runtime.None, err
+	}
+
+	el, err := toRootElement(args[0])
+	if err != nil {
+		return runtime.None, err
+	}
+
+	rest := args[1:]
+
+	if !isFormValues(rest[0]) || len(args) == 4 {
+		selector, err := drivers.ToQuerySelector(ctx, rest[0])
+		if err != nil {
+			return runtime.None, err
+		}
+
+		found, err := el.QuerySelector(ctx, selector)
+		if err != nil {
+			return runtime.None, err
+		}
+
+		if found == runtime.None {
+			return runtime.None, runtime.Errorf(runtime.ErrNotFound, "form by selector %s", selector)
+		}
+
+		el, err = drivers.ToElement(found)
+		if err != nil {
+			return runtime.None, err
+		}
+
+		rest = rest[1:]
+	}
+
+	if len(rest) == 0 {
+		return runtime.None, runtime.Error(runtime.ErrMissedArgument, "values")
+	}
+
+	var opts drivers.FormFillOptions
+
+	if len(rest) > 1 {
+		if err := sdk.Decode(ctx, rest[1], &opts, sdk.DisallowUnknownFields()); err != nil {
+			return runtime.None, err
+		}
+
+		if opts.Delay < 0 {
+			return runtime.None, runtime.Errorf(runtime.ErrInvalidArgument, "delay must be greater than or equal to 0")
+		}
+	}
+
+	target, err := drivers.ToFormTarget(el)
+	if err != nil {
+		return runtime.None, err
+	}
+
+	fields, err := toFormFields(ctx, rest[0])
+	if err != nil {
+		return runtime.None, err
+	}
+
+	result, err := target.FillForm(ctx, fields, opts)
+	if err != nil {
+		return runtime.None, err
+	}
+
+	return result.ToValue(), nil
+}
+
+func isFormValues(value runtime.Value) bool {
+	switch value.(type) {
+	case runtime.Map, runtime.List:
+		return true
+	default:
+		return false
+	}
+}
+
+func toFormFields(ctx context.Context, value runtime.Value) ([]drivers.FormField, error) {
+	if list, ok := value.(runtime.List); ok {
+		return drivers.NewOrderedFormFields(ctx, list)
+	}
+
+	values, err := runtime.CastMap(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return drivers.NewFormFields(ctx, values)
+}
@@ -0,0 +1,64 @@
+package lib
+
+import (
+	"context"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+	"github.com/MontFerret/ferret/v2/pkg/sdk"
+)
+
+type gesturePointInput struct {
+	X *float64 `json:"x"`
+	Y *float64 `json:"y"`
+}
+
+// toGesturePoint converts an { x, y } object into viewport coordinates and any other value into an element selector.
+func toGesturePoint(ctx context.Context, value runtime.Value) (drivers.GesturePoint, error) {
+	if m, ok := value.(runtime.Map); ok {
+		_, hasX, err := sdk.TryGetByKey[runtime.Value](ctx, m, runtime.String("x"))
+		if err != nil {
+			return drivers.GesturePoint{}, err
+		}
+
+		_, hasY, err := sdk.TryGetByKey[runtime.Value](ctx, m, runtime.String("y"))
+		if err != nil {
+			return drivers.GesturePoint{}, err
+		}
+
+		if hasX || hasY {
+			var input gesturePointInput
+
+			if err := sdk.Decode(ctx, value, &input, sdk.DisallowUnknownFields()); err != nil {
+				return drivers.GesturePoint{}, err
+			}
+
+			if input.X == nil || input.Y == nil {
+				return drivers.GesturePoint{}, runtime.Error(runtime.ErrMissedArgument, "point requires both x and y")
+			}
+
+			return drivers.NewGesturePointByXY(runtime.Float(*input.X), runtime.Float(*input.Y)), nil
+		}
+	}
+
+	selector, err := drivers.ToQuerySelector(ctx, value)
+	if err != nil {
+		return drivers.GesturePoint{}, err
+	}
+
+	return drivers.NewGesturePointBySelector(selector), nil
+}
+
+func toGestureOptions(ctx context.Context, value runtime.Value) (drivers.GestureOptions, error) {
+	var result drivers.GestureOptions
+
+	if err := sdk.Decode(ctx, value, &result, sdk.DisallowUnknownFields()); err != nil {
+		return result, err
+	}
+
+	if result.Steps < 0 {
+		return result, runtime.Errorf(runtime.ErrInvalidArgument, "steps must be greater than or equal to 0")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,61 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+func TestToGesturePointAcceptsSelectorsAndCoordinates(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	point, err := toGesturePoint(ctx, runtime.NewObjectWith(map[string]runtime.Value{
+		"x": runtime.NewInt(10),
+		"y": runtime.NewFloat(20.5),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if point.Selector != nil || point.X != 10 || point.Y != 20.5 {
+		t.Fatalf("expected viewport point (10, 20.5), got %+v", point)
+	}
+
+	point, err = toGesturePoint(ctx, runtime.NewString("#card"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if point.Selector == nil || point.Selector.String() != "#card" {
+		t.Fatalf("expected selector point, got %+v", point)
+	}
+
+	if _, err := toGesturePoint(ctx, runtime.NewObjectWith(map[string]runtime.Value{"x": runtime.NewInt(10)})); err == nil {
+		t.Fatal("expected a point without y to be rejected")
+	}
+}
+
+func TestGesturesRequireGestureCapability(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	page := newMemoryPage(t, `<html><body><div id="card"></div></body></html>`, drivers.NewHTTPCookies())
+	card := runtime.NewString("#card")
+
+	if _, err := Drag(ctx, page, card, card); !errors.Is(err, runtime.ErrNotSupported) {
+		t.Fatalf("expected DRAG to report unsupported capability, got %v", err)
+	}
+
+	if _, err := Tap(ctx, page, card); !errors.Is(err, runtime.ErrNotSupported) {
+		t.Fatalf("expected TAP to report unsupported capability, got %v", err)
+	}
+
+	if _, err := Pinch(ctx, page, card, runtime.NewObjectWith(map[string]runtime.Value{"scale": runtime.NewFloat(0.5)})); !errors.Is(err, runtime.ErrNotSupported) {
+		t.Fatalf("expected PINCH to report unsupported capability, got %v", err)
+	}
+}
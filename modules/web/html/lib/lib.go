@@ -39,6 +39,7 @@ func coreFunctionDefinitions() []sdk.FunctionDef {
 		sdk.Func("X", XPathSelector),
 		sdk.Func("XPATH", XPath),
 		sdk.Func("FRAMES", Frames),
+		sdk.Func("ARCHIVE_PAGE", ArchivePage),
 		sdk.Func("ATTR_GET", AttributeGet),
 		sdk.Func("ATTR_QUERY", AttributeQuery),
 		sdk.Func("ATTR_REMOVE", AttributeRemove),
@@ -47,13 +48,17 @@ func coreFunctionDefinitions() []sdk.FunctionDef {
 		sdk.Func("COOKIE_DEL", CookieDel),
 		sdk.Func("COOKIE_GET", CookieGet),
 		sdk.Func("COOKIE_SET", CookieSet),
+		sdk.Func("COOKIES_EXPORT", CookiesExport),
+		sdk.Func("COOKIES_IMPORT", CookiesImport),
 		sdk.Func("CLICK", Click),
 		sdk.Func("CLICK_ALL", ClickAll),
 		sdk.Func("DOWNLOAD", Download),
+		sdk.Func("DRAG", Drag),
 		sdk.Func("ELEMENT", Element),
 		sdk.Func("ELEMENT_EXISTS", ElementExists),
 		sdk.Func("ELEMENTS", Elements),
 		sdk.Func("ELEMENTS_COUNT", ElementsCount),
+		sdk.Func("FILL_FORM", FillForm),
 		sdk.Func("FOCUS", Focus),
 		sdk.Func("HOVER", Hover),
 		sdk.Func("UNHOVER", Unhover),
@@ -65,6 +70,7 @@ func coreFunctionDefinitions() []sdk.FunctionDef {
 		sdk.Func("INNER_TEXT_ALL", GetInnerTextAll),
 		sdk.Func("INPUT", Input),
 		sdk.Func("INPUT_CLEAR", InputClear),
+		sdk.Func("MARKDOWN", Markdown),
 		sdk.Func("MOUSE", MouseMoveXY),
 		sdk.Func("NAVIGATE", Navigate),
 		sdk.Func("NAVIGATE_BACK", NavigateBack),
@@ -72,17 +78,23 @@ func coreFunctionDefinitions() []sdk.FunctionDef {
 		sdk.Func("PAGINATION", Pagination),
 		sdk.Func("PARSE", Parse),
 		sdk.Func("PDF", PDF),
+		sdk.Func("PINCH", Pinch),
 		sdk.Func("PRESS", Press),
 		sdk.Func("PRESS_SELECTOR", PressSelector),
 		sdk.Func("SCREENSHOT", Screenshot),
 		sdk.Func("SCROLL", ScrollXY),
 		sdk.Func("SCROLL_BOTTOM", ScrollBottom),
 		sdk.Func("SCROLL_ELEMENT", ScrollInto),
+		sdk.Func("SCROLL_PAGINATION", ScrollPagination),
 		sdk.Func("SCROLL_TOP", ScrollTop),
 		sdk.Func("SELECT", Select),
+		sdk.Func("STRUCTURED_DATA", StructuredData),
 		sdk.Func("STYLE_GET", StyleGet),
 		sdk.Func("STYLE_REMOVE", StyleRemove),
 		sdk.Func("STYLE_SET", StyleSet),
+		sdk.Func("SWIPE", Swipe),
+		sdk.Func("TAP", Tap),
+		sdk.Func("TEXT", Text),
 		sdk.Func("WAIT_ATTR", WaitAttribute),
 		sdk.Func("WAIT_NO_ATTR", WaitNoAttribute),
 		sdk.Func("WAIT_ATTR_ALL", WaitAttributeAll),
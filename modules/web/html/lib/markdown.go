@@ -0,0 +1,292 @@
+package lib
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/table"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/contrib/modules/web/html/internal/plaintext"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+	"github.com/MontFerret/ferret/v2/pkg/sdk"
+)
+
+type markdownOptions struct {
+	BaseURL string `json:"baseUrl"`
+	Images  bool   `json:"images"`
+	Tables  bool   `json:"tables"`
+}
+
+// Markdown converts a page, a document, an element subtree or HTML content to Markdown.
+//
+// Tables become GFM tables, and relative link and image URLs are resolved against
+// the base URL of the document. Scripts, styles, form controls and hidden elements are dropped.
+//
+// @param source {HTMLPage|HTMLDocument|HTMLElement|String} Page, document, element, or HTML content.
+// @param options {Object?} Conversion options: baseUrl, images (default true), tables (default true).
+// @return {String} Markdown content.
+func Markdown(ctx context.Context, args ...runtime.Value) (runtime.Value, error) {
+	if err := runtime.ValidateArgs(args, 1, 2); err != nil {
+		return runtime.None, err
+	}
+
+	opts := markdownOptions{Images: true, Tables: true}
+
+	if len(args) > 1 {
+		if err := sdk.Decode(ctx, args[1], &opts, sdk.DisallowUnknownFields()); err != nil {
+			return runtime.None, err
+		}
+	}
+
+	nodes, baseURL, err := markdownSource(ctx, args[0], opts.BaseURL)
+	if err != nil {
+		return runtime.None, err
+	}
+
+	root := markdownRoot(nodes)
+	prepareMarkdown(root, baseURL, opts.Images)
+
+	out, err := newMarkdownConverter(opts.Tables).ConvertNode(root)
+	if err != nil {
+		return runtime.None, runtime.Errorf(err, "failed to convert to Markdown")
+	}
+
+	return runtime.NewString(string(out)), nil
+}
+
+// newMarkdownConverter returns the converter used by web/article, without the table plugin when tables are off.
+func newMarkdownConverter(tables bool) *converter.Converter {
+	plugins := []converter.Plugin{
+		base.NewBasePlugin(),
+		commonmark.NewCommonmarkPlugin(),
+	}
+
+	if tables {
+		plugins = append(plugins, table.NewTablePlugin(
+			table.WithHeaderPromotion(true),
+		))
+	}
+
+	return converter.NewConverter(converter.WithPlugins(plugins...))
+}
+
+// markdownRoot returns the node to convert. Fragment nodes are moved into a container,
+// which is a table for table parts, as rows and sections are only rendered inside one.
+func markdownRoot(nodes []*html.Node) *html.Node {
+	if len(nodes) == 1 && nodes[0].Type == html.DocumentNode {
+		return nodes[0]
+	}
+
+	root := &html.Node{
+		Type:     html.ElementNode,
+		Data:     "div",
+		DataAtom: atom.Div,
+	}
+
+	for _, node := range nodes {
+		switch node.DataAtom {
+		case atom.Caption, atom.Thead, atom.Tbody, atom.Tfoot, atom.Tr:
+			root.Data, root.DataAtom = "table", atom.Table
+		}
+
+		root.AppendChild(node)
+	}
+
+	return root
+}
+
+// prepareMarkdown drops the nodes that TEXT skips as well, and images that are turned off or inlined
+// as data: URLs. Link and image URLs are resolved against the base URL before the conversion,
+// so that relative paths follow the base URL of the document.
+func prepareMarkdown(node *html.Node, baseURL *url.URL, images bool) {
+	for child := node.FirstChild; child != nil; {
+		next := child.NextSibling
+
+		switch {
+		case plaintext.Skipped(child):
+			node.RemoveChild(child)
+		case child.DataAtom == atom.Img:
+			src := strings.TrimSpace(markdownAttr(child, "src"))
+			if src == "" {
+				src = strings.TrimSpace(markdownAttr(child, "data-src"))
+			}
+
+			if !images || src == "" || strings.HasPrefix(strings.ToLower(src), "data:") {
+				node.RemoveChild(child)
+			} else {
+				setMarkdownAttr(child, "src", resolveMarkdownURL(baseURL, src))
+			}
+		case child.DataAtom == atom.A:
+			if href := strings.TrimSpace(markdownAttr(child, "href")); href != "" && !strings.HasPrefix(strings.ToLower(href), "javascript:") {
+				setMarkdownAttr(child, "href", resolveMarkdownURL(baseURL, href))
+			}
+
+			prepareMarkdown(child, baseURL, images)
+		default:
+			prepareMarkdown(child, baseURL, images)
+		}
+
+		child = next
+	}
+}
+
+func resolveMarkdownURL(baseURL *url.URL, ref string) string {
+	if baseURL == nil {
+		return ref
+	}
+
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+
+	return baseURL.ResolveReference(parsed).String()
+}
+
+func markdownAttr(node *html.Node, name string) string {
+	for _, attr := range node.Attr {
+		if attr.Namespace == "" && attr.Key == name {
+			return attr.Val
+		}
+	}
+
+	return ""
+}
+
+func setMarkdownAttr(node *html.Node, name, value string) {
+	for i, attr := range node.Attr {
+		if attr.Namespace == "" && attr.Key == name {
+			node.Attr[i].Val = value
+
+			return
+		}
+	}
+
+	node.Attr = append(node.Attr, html.Attribute{Key: name, Val: value})
+}
+
+// markdownSource parses the content of a conversion source.
+// The base URL option takes precedence over the base URL of the document the source belongs to.
+func markdownSource(ctx context.Context, source runtime.Value, base string) ([]*html.Node, *url.URL, error) {
+	var (
+		content  string
+		baseURL  *url.URL
+		fragment bool
+		err      error
+	)
+
+	switch v := source.(type) {
+	case runtime.String:
+		content = v.String()
+	case drivers.HTMLPage:
+		content, baseURL, err = markdownDocument(ctx, v.GetMainFrame())
+	case drivers.HTMLDocument:
+		content, baseURL, err = markdownDocument(ctx, v)
+	case drivers.HTMLElement:
+		content, baseURL, err = markdownElement(ctx, v)
+		fragment = true
+	default:
+		return nil, nil, runtime.TypeErrorOf(source, drivers.HTMLPageType, drivers.HTMLDocumentType, drivers.HTMLElementType, runtime.TypeString)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if base != "" {
+		baseURL, err = url.Parse(base)
+		if err != nil {
+			return nil, nil, runtime.Errorf(runtime.ErrInvalidArgument, "invalid base URL %q", base)
+		}
+	}
+
+	if !fragment {
+		doc, err := html.Parse(strings.NewReader(content))
+		if err != nil {
+			return nil, nil, runtime.Errorf(err, "failed to parse a document")
+		}
+
+		return []*html.Node{doc}, baseURL, nil
+	}
+
+	// the template context keeps table parts such as rows and cells that a body would drop
+	nodes, err := html.ParseFragment(strings.NewReader(content), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "template",
+		DataAtom: atom.Template,
+	})
+	if err != nil {
+		return nil, nil, runtime.Errorf(err, "failed to parse an element")
+	}
+
+	return nodes, baseURL, nil
+}
+
+func markdownDocument(ctx context.Context, doc drivers.HTMLDocument) (string, *url.URL, error) {
+	content, baseURL, err := documentHTML(ctx, doc)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// the effective base URL honors <base> elements
+	if target, err := drivers.ToDocumentURLTarget(doc); err == nil {
+		if base, err := target.GetBaseURL(ctx); err == nil && base != runtime.EmptyString {
+			if parsed, err := url.Parse(base.String()); err == nil {
+				baseURL = parsed
+			}
+		}
+	}
+
+	return content, baseURL, nil
+}
+
+// markdownElement returns the outer markup of an element and the base URL of its document.
+func markdownElement(ctx context.Context, el drivers.HTMLElement) (string, *url.URL, error) {
+	name, err := el.GetNodeName(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	attrTarget, err := drivers.ToAttributeTarget(el)
+	if err != nil {
+		return "", nil, err
+	}
+
+	attrs, err := elementAttributes(ctx, attrTarget)
+	if err != nil {
+		return "", nil, err
+	}
+
+	contentTarget, err := drivers.ToContentTarget(el)
+	if err != nil {
+		return "", nil, err
+	}
+
+	inner, err := contentTarget.GetInnerHTML(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	tag := strings.ToLower(name.String())
+	content := "<" + tag + attrs + ">" + inner.String() + "</" + tag + ">"
+
+	var baseURL *url.URL
+
+	// elements expose the effective base URL of their document, including <base>
+	if target, ok := el.(drivers.DOMPropertyTarget); ok {
+		if value, err := target.GetDOMProperty(ctx, "baseURI"); err == nil {
+			if base, ok := value.(runtime.String); ok && base != runtime.EmptyString {
+				baseURL, _ = url.Parse(base.String())
+			}
+		}
+	}
+
+	return content, baseURL, nil
+}
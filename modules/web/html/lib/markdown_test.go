@@ -0,0 +1,145 @@
+package lib
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+const markdownMarkup = `<html>
+  <head><title>Ferret Plush</title><base href="https://cdn.example.com/shop/"></head>
+  <body>
+    <nav><a href="/">Home</a></nav>
+    <article id="offer">
+      <h2>Ferret Plush</h2>
+      <p>See <a href="care">care notes</a>.</p>
+      <img src="front.jpg" alt="Front">
+      <table><tr><th>Region</th><th>Price</th></tr><tr><td>EU</td><td>19 EUR</td></tr></table>
+    </article>
+  </body>
+</html>`
+
+func TestMarkdownConvertsElementSubtree(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	page := newMemoryPage(t, markdownMarkup, nil)
+
+	found, err := page.GetMainFrame().QuerySelector(ctx, drivers.NewCSSSelector("#offer"))
+	if err != nil {
+		t.Fatalf("resolve offer: %v", err)
+	}
+
+	out, err := Markdown(ctx, found)
+	if err != nil {
+		t.Fatalf("convert element: %v", err)
+	}
+
+	for _, expected := range []string{
+		"## Ferret Plush\n\nSee [care notes](https://cdn.example.com/shop/care).",
+		"![Front](https://cdn.example.com/shop/front.jpg)",
+		"| Region ",
+		"| EU ",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Fatalf("expected %q in %q", expected, out.String())
+		}
+	}
+
+	if strings.Contains(out.String(), "Home") {
+		t.Fatalf("expected only the element subtree, got %q", out.String())
+	}
+}
+
+func TestMarkdownConvertsPagesAndContent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	page := newMemoryPage(t, markdownMarkup, nil)
+	options := runtime.NewObjectWith(map[string]runtime.Value{
+		"images": runtime.False,
+		"tables": runtime.False,
+	})
+
+	for _, tc := range []struct {
+		source runtime.Value
+		link   string
+	}{
+		{source: page, link: "[Home](https://cdn.example.com/)"},
+		{source: runtime.NewString(`<nav><a href="/">Home</a></nav>`), link: "[Home](/)"},
+	} {
+		out, err := Markdown(ctx, tc.source, options)
+		if err != nil {
+			t.Fatalf("convert %T: %v", tc.source, err)
+		}
+
+		if got := out.String(); !strings.Contains(got, tc.link) || strings.Contains(got, "![") {
+			t.Fatalf("unexpected Markdown for %T: %q", tc.source, got)
+		}
+	}
+}
+
+func TestTextConvertsElementSubtree(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	page := newMemoryPage(t, markdownMarkup, nil)
+
+	found, err := page.GetMainFrame().QuerySelector(ctx, drivers.NewCSSSelector("#offer"))
+	if err != nil {
+		t.Fatalf("resolve offer: %v", err)
+	}
+
+	out, err := Text(ctx, found, runtime.NewObjectWith(map[string]runtime.Value{
+		"preserveLinks": runtime.True,
+	}))
+	if err != nil {
+		t.Fatalf("convert element: %v", err)
+	}
+
+	expected := "Ferret Plush\n\nSee care notes (https://cdn.example.com/shop/care).\n\nRegion\tPrice\nEU\t19 EUR"
+
+	if out.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestMarkdownRejectsInvalidArguments(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	if _, err := Markdown(ctx, runtime.NewInt(1)); err == nil {
+		t.Fatal("expected an unsupported source to fail")
+	}
+
+	if _, err := Markdown(ctx, runtime.NewString("<p>x</p>"), runtime.NewObjectWith(map[string]runtime.Value{
+		"links": runtime.True,
+	})); err == nil {
+		t.Fatal("expected unknown Markdown options to fail")
+	}
+
+	if _, err := Text(ctx, runtime.NewString("<p>x</p>"), runtime.NewObjectWith(map[string]runtime.Value{
+		"images": runtime.True,
+	})); err == nil {
+		t.Fatal("expected unknown text options to fail")
+	}
+}
+
+func TestMarkdownDropsUnreadableContent(t *testing.T) {
+	t.Parallel()
+
+	out, err := Markdown(context.Background(), runtime.NewString(
+		`<p>Shown</p><div hidden>Hidden</div><script>track()</script><img src="data:image/png;base64,AAAA" alt="pixel">`,
+	))
+	if err != nil {
+		t.Fatalf("convert content: %v", err)
+	}
+
+	if out.String() != "Shown" {
+		t.Fatalf("expected only the readable content, got %q", out.String())
+	}
+}
@@ -0,0 +1,48 @@
+package lib
+
+import (
+	"context"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+	"github.com/MontFerret/ferret/v2/pkg/sdk"
+)
+
+// Pinch pinches with two fingers around an element or a point.
+//
+// A scale below 1 pinches in and a scale above 1 spreads the fingers out. The default scale is 2.
+// Touch gestures require a page opened with mobile viewport emulation.
+//
+// @param root {HTMLPage|HTMLDocument} Page or document.
+// @param at {String|Object} Selector or { x, y } viewport point to pinch around.
+// @param options {Object?} Options with scale and the number of touch move steps.
+// @return {Boolean} True when the pinch completes.
+func Pinch(ctx context.Context, args ...runtime.Value) (runtime.Value, error) {
+	if err := runtime.ValidateArgs(args, 2, 3); err != nil {
+		return runtime.False, err
+	}
+
+	doc, err := drivers.ToDocumentGestureTarget(args[0])
+	if err != nil {
+		return runtime.False, err
+	}
+
+	at, err := toGesturePoint(ctx, args[1])
+	if err != nil {
+		return runtime.False, err
+	}
+
+	opts := drivers.PinchOptions{Scale: drivers.DefaultPinchScale}
+
+	if len(args) > 2 {
+		if err := sdk.Decode(ctx, args[2], &opts, sdk.DisallowUnknownFields()); err != nil {
+			return runtime.False, err
+		}
+
+		if opts.Scale <= 0 {
+			return runtime.False, runtime.Errorf(runtime.ErrInvalidArgument, "scale must be greater than 0")
+		}
+	}
+
+	return runtime.True, doc.Pinch(ctx, at, opts)
+}
@@ -0,0 +1,245 @@
+package lib
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/MontFerret/ferret/v2/pkg/logging"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+	"github.com/MontFerret/ferret/v2/pkg/sdk"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/contrib/modules/web/html/internal/logutil"
+)
+
+// scrollPaginationPollInterval is how often the item count is checked while waiting for new items.
+const scrollPaginationPollInterval = 100 * time.Millisecond
+
+type (
+	ScrollPaginationParams struct {
+		ItemSelector runtime.Value `json:"itemSelector"`
+		MaxPages     runtime.Int   `json:"maxPages"`
+		IdleTimeout  runtime.Int   `json:"idleTimeout"`
+	}
+
+	ScrollPaging struct {
+		logger   zerolog.Logger
+		page     drivers.HTMLPage
+		selector drivers.QuerySelector
+		params   ScrollPaginationParams
+	}
+
+	ScrollPagingIterator struct {
+		logger   zerolog.Logger
+		page     drivers.HTMLPage
+		selector drivers.QuerySelector
+		params   ScrollPaginationParams
+		count    runtime.Int
+		pos      runtime.Int
+	}
+)
+
+// ScrollPagination returns an iterator that advances an infinite-scroll page by scrolling to the bottom.
+//
+// The first iteration yields the current page without scrolling.
+// Each following iteration scrolls to the bottom and waits until more items match the item selector,
+// the page network goes idle, or the idle timeout elapses.
+// The iteration stops when no new items appear or maxPages is reached.
+//
+// @param page {HTMLPage} Page to paginate.
+// @param params {Object} Options with itemSelector, maxPages and idleTimeout in milliseconds.
+// @return {Iterator<Int>} Iterator over zero-based page positions.
+func ScrollPagination(ctx context.Context, root, paramsValue runtime.Value) (runtime.Value, error) {
+	page, err := drivers.ToPage(root)
+
+	if err != nil {
+		return runtime.None, err
+	}
+
+	params, err := parseScrollPaginationParams(ctx, paramsValue)
+
+	if err != nil {
+		return runtime.None, err
+	}
+
+	selector, err := drivers.ToQuerySelector(ctx, params.ItemSelector)
+
+	if err != nil {
+		return runtime.None, err
+	}
+
+	logger := logutil.WithComponent(logging.From(ctx).With(), "stdlib_html_scroll_pagination").
+		Str("selector", selector.String()).
+		Logger()
+
+	return sdk.NewIterableValue(&ScrollPaging{logger, page, selector, params}), nil
+}
+
+func (p *ScrollPaging) Iterate(_ context.Context) (runtime.Iterator, error) {
+	return &ScrollPagingIterator{
+		logger:   p.logger,
+		page:     p.page,
+		selector: p.selector,
+		params:   p.params,
+		pos:      -1,
+	}, nil
+}
+
+func (i *ScrollPagingIterator) Next(ctx context.Context) (runtime.Value, runtime.Value, error) {
+	if i.params.MaxPages > 0 && i.pos+1 >= i.params.MaxPages {
+		i.logger.Trace().Int("max_pages", int(i.params.MaxPages)).Msg("page limit is reached. exit")
+
+		return runtime.None, runtime.None, io.EOF
+	}
+
+	i.pos++
+
+	i.logger.Trace().Int("position", int(i.pos)).Msg("starting to advance iteration")
+
+	frame := i.page.GetMainFrame()
+
+	if i.pos == 0 {
+		count, err := frame.CountBySelector(ctx, i.selector)
+
+		if err != nil {
+			i.logger.Trace().Err(err).Msg("failed to count items")
+
+			return runtime.None, runtime.None, err
+		}
+
+		i.count = count
+
+		i.logger.Trace().Int("count", int(count)).Msg("starting point of pagination. nothing to do. exit")
+
+		return runtime.ZeroInt, runtime.ZeroInt, nil
+	}
+
+	viewport, err := drivers.ToDocumentViewportTarget(frame)
+
+	if err != nil {
+		i.logger.Trace().Err(err).Msg("viewport capability is not supported. exit")
+
+		return runtime.None, runtime.None, err
+	}
+
+	// subscribe before scrolling so that requests triggered by the scroll are observed
+	idle, closeIdle := i.subscribeIdle(ctx)
+	defer closeIdle()
+
+	i.logger.Trace().Msg("scrolling to the bottom...")
+
+	if _, err := viewport.ScrollBottom(ctx, drivers.ScrollOptions{}); err != nil {
+		i.logger.Trace().Err(err).Msg("failed to scroll. exit")
+
+		return runtime.None, runtime.None, err
+	}
+
+	count, err := i.waitForItems(ctx, frame, idle)
+
+	if err != nil {
+		i.logger.Trace().Err(err).Msg("failed to wait for new items")
+
+		return runtime.None, runtime.None, err
+	}
+
+	if count <= i.count {
+		i.logger.Trace().Int("count", int(count)).Msg("no new items appeared. exit")
+
+		return runtime.None, runtime.None, io.EOF
+	}
+
+	i.logger.Trace().Int("count", int(count)).Msg("new items appeared. iteration has succeeded")
+
+	i.count = count
+
+	return i.pos, i.pos, nil
+}
+
+// subscribeIdle subscribes to the network idle event of pages that can observe their network.
+func (i *ScrollPagingIterator) subscribeIdle(ctx context.Context) (<-chan runtime.Message, func()) {
+	observable, ok := i.page.(runtime.Observable)
+
+	if !ok {
+		return nil, func() {}
+	}
+
+	stream, err := observable.Subscribe(ctx, runtime.Subscription{
+		EventName: runtime.NewString(drivers.NetworkIdleEvent),
+	})
+
+	if err != nil {
+		i.logger.Trace().Err(err).Msg("network idle event is not supported. falling back to item polling")
+
+		return nil, func() {}
+	}
+
+	return stream.Read(ctx), func() {
+		_ = stream.Close()
+	}
+}
+
+// waitForItems waits until more items than before match the selector, the network goes idle or the idle timeout elapses.
+// It returns the latest item count.
+func (i *ScrollPagingIterator) waitForItems(ctx context.Context, frame drivers.HTMLDocument, idle <-chan runtime.Message) (runtime.Int, error) {
+	waitCtx, cancel := waitTimeout(ctx, i.params.IdleTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(scrollPaginationPollInterval)
+	defer ticker.Stop()
+
+	for {
+		count, err := frame.CountBySelector(ctx, i.selector)
+
+		if err != nil || count > i.count {
+			return count, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return count, ctx.Err()
+		case <-waitCtx.Done():
+			i.logger.Trace().Msg("idle timeout elapsed")
+
+			return count, nil
+		case msg, ok := <-idle:
+			if !ok || msg.Err() != nil {
+				// keep polling until the timeout when the idle stream is gone
+				idle = nil
+
+				continue
+			}
+
+			i.logger.Trace().Msg("network is idle")
+
+			return frame.CountBySelector(ctx, i.selector)
+		case <-ticker.C:
+		}
+	}
+}
+
+func parseScrollPaginationParams(ctx context.Context, value runtime.Value) (ScrollPaginationParams, error) {
+	params := ScrollPaginationParams{
+		IdleTimeout: drivers.DefaultWaitTimeout,
+	}
+
+	if err := sdk.Decode(ctx, value, &params, sdk.DisallowUnknownFields()); err != nil {
+		return ScrollPaginationParams{}, err
+	}
+
+	if params.ItemSelector == nil || params.ItemSelector == runtime.None {
+		return ScrollPaginationParams{}, runtime.Error(runtime.ErrMissedArgument, "itemSelector")
+	}
+
+	if params.MaxPages < 0 {
+		return ScrollPaginationParams{}, runtime.Errorf(runtime.ErrInvalidArgument, "maxPages must be greater than or equal to 0")
+	}
+
+	if params.IdleTimeout <= 0 {
+		return ScrollPaginationParams{}, runtime.Errorf(runtime.ErrInvalidArgument, "idleTimeout must be greater than 0")
+	}
+
+	return params, nil
+}
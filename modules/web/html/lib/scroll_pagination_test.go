@@ -0,0 +1,154 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/contrib/modules/web/html/drivers/memory"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+func TestScrollPaginationStopsWhenNoNewItemsAppear(t *testing.T) {
+	t.Parallel()
+
+	page := newFeedPage(t, 2, 6, 2)
+	positions := collectScrollPages(t, page, runtime.NewObjectWith(map[string]runtime.Value{
+		"itemSelector": runtime.NewString(".post"),
+		"idleTimeout":  runtime.NewInt(50),
+	}))
+
+	if len(positions) != 3 {
+		t.Fatalf("expected 3 pages, got %v", positions)
+	}
+
+	if page.frame.scrolls != 3 {
+		t.Fatalf("expected the last scroll to find no new items, got %d scrolls", page.frame.scrolls)
+	}
+}
+
+func TestScrollPaginationRespectsMaxPages(t *testing.T) {
+	t.Parallel()
+
+	page := newFeedPage(t, 2, 100, 2)
+	positions := collectScrollPages(t, page, runtime.NewObjectWith(map[string]runtime.Value{
+		"itemSelector": runtime.NewString(".post"),
+		"maxPages":     runtime.NewInt(2),
+		"idleTimeout":  runtime.NewInt(50),
+	}))
+
+	if len(positions) != 2 || page.frame.scrolls != 1 {
+		t.Fatalf("expected 2 pages after 1 scroll, got %v after %d scrolls", positions, page.frame.scrolls)
+	}
+}
+
+func TestScrollPaginationValidatesParams(t *testing.T) {
+	t.Parallel()
+
+	page := newFeedPage(t, 2, 6, 2)
+
+	for name, params := range map[string]runtime.Value{
+		"missing selector": runtime.NewObject(),
+		"negative pages": runtime.NewObjectWith(map[string]runtime.Value{
+			"itemSelector": runtime.NewString(".post"),
+			"maxPages":     runtime.NewInt(-1),
+		}),
+		"unknown option": runtime.NewObjectWith(map[string]runtime.Value{
+			"itemSelector": runtime.NewString(".post"),
+			"next":         runtime.NewString(".next"),
+		}),
+	} {
+		if _, err := ScrollPagination(context.Background(), page, params); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+
+	doc := newTestDocument(t, `<html><body></body></html>`)
+	params := runtime.NewObjectWith(map[string]runtime.Value{"itemSelector": runtime.NewString(".post")})
+
+	if _, err := ScrollPagination(context.Background(), doc, params); err == nil {
+		t.Fatal("expected document input to remain invalid for SCROLL_PAGINATION")
+	}
+}
+
+func collectScrollPages(t *testing.T, page drivers.HTMLPage, params runtime.Value) []runtime.Value {
+	t.Helper()
+
+	ctx := context.Background()
+
+	value, err := ScrollPagination(ctx, page, params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	iterable, ok := value.(runtime.Iterable)
+	if !ok {
+		t.Fatalf("expected scroll pagination to be iterable, got %T", value)
+	}
+
+	iterator, err := iterable.Iterate(ctx)
+	if err != nil {
+		t.Fatalf("unexpected iterate error: %v", err)
+	}
+
+	var positions []runtime.Value
+
+	for {
+		pos, _, err := iterator.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			return positions
+		}
+
+		if err != nil {
+			t.Fatalf("unexpected iteration error: %v", err)
+		}
+
+		positions = append(positions, pos)
+	}
+}
+
+type (
+	feedPage struct {
+		*memory.HTMLPage
+		frame *feedDocument
+	}
+
+	feedDocument struct {
+		*testDocument
+		loaded  runtime.Int
+		total   runtime.Int
+		batch   runtime.Int
+		scrolls int
+	}
+)
+
+func newFeedPage(t *testing.T, loaded, total, batch runtime.Int) *feedPage {
+	t.Helper()
+
+	return &feedPage{
+		HTMLPage: newMemoryPage(t, `<html><body></body></html>`, drivers.NewHTTPCookies()),
+		frame: &feedDocument{
+			testDocument: newTestDocument(t, `<html><body></body></html>`),
+			loaded:       loaded,
+			total:        total,
+			batch:        batch,
+		},
+	}
+}
+
+func (p *feedPage) GetMainFrame() drivers.HTMLDocument {
+	return p.frame
+}
+
+func (doc *feedDocument) CountBySelector(_ context.Context, _ drivers.QuerySelector) (runtime.Int, error) {
+	return doc.loaded, nil
+}
+
+func (doc *feedDocument) ScrollBottom(_ context.Context, _ drivers.ScrollOptions) (runtime.Boolean, error) {
+	doc.scrolls++
+	doc.loaded = min(doc.loaded+doc.batch, doc.total)
+
+	return runtime.True, nil
+}
@@ -0,0 +1,138 @@
+package lib
+
+import (
+	"context"
+	"html"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/contrib/modules/web/html/internal/structured"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+var htmlStartTag = regexp.MustCompile(`(?is)^\s*(?:<!--.*?-->\s*|<!doctype[^>]*>\s*)*<html[\s>]`)
+
+// StructuredData extracts JSON-LD blocks, Microdata and RDFa items, OpenGraph and Twitter Card tags.
+//
+// Microdata and RDFa items are objects with type, properties and an optional id,
+// where every property holds an array of strings or nested items.
+// OpenGraph and Twitter Card tags are objects keyed by property name without the og: and twitter: prefixes.
+//
+// @param source {HTMLPage|HTMLDocument|String} Page, document, or HTML content.
+// @return {Object} Object with jsonLd, microdata, rdfa, openGraph and twitter fields.
+func StructuredData(ctx context.Context, source runtime.Value) (runtime.Value, error) {
+	var (
+		content string
+		baseURL *url.URL
+		err     error
+	)
+
+	switch v := source.(type) {
+	case runtime.String:
+		content = v.String()
+	case drivers.HTMLPage:
+		content, baseURL, err = documentHTML(ctx, v.GetMainFrame())
+	case drivers.HTMLDocument:
+		content, baseURL, err = documentHTML(ctx, v)
+	default:
+		return runtime.None, runtime.TypeErrorOf(source, drivers.HTMLPageType, drivers.HTMLDocumentType, runtime.TypeString)
+	}
+
+	if err != nil {
+		return runtime.None, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return runtime.None, runtime.Errorf(err, "failed to parse a document")
+	}
+
+	return runtime.ValueOf(structured.Extract(doc, baseURL).Value())
+}
+
+// documentHTML returns the markup of the document including the attributes of its <html> element,
+// which carry RDFa vocabularies and prefixes and sometimes Microdata scopes.
+func documentHTML(ctx context.Context, doc drivers.HTMLDocument) (string, *url.URL, error) {
+	baseURL, _ := url.Parse(doc.GetURL().String())
+
+	target, err := drivers.ToContentTarget(doc.GetElement())
+	if err != nil {
+		return "", nil, err
+	}
+
+	content, err := target.GetInnerHTML(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if htmlStartTag.MatchString(content.String()) {
+		return content.String(), baseURL, nil
+	}
+
+	// drivers backed by a browser return the content of the document element only
+	attrs, err := documentElementAttributes(ctx, doc)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return "<html" + attrs + ">" + content.String() + "</html>", baseURL, nil
+}
+
+func documentElementAttributes(ctx context.Context, doc drivers.HTMLDocument) (string, error) {
+	query, err := drivers.ToQueryTarget(doc)
+	if err != nil {
+		return "", err
+	}
+
+	found, err := query.QuerySelector(ctx, drivers.NewCSSSelector("html"))
+	if err != nil || found == runtime.None {
+		return "", err
+	}
+
+	target, err := drivers.ToAttributeTarget(found)
+	if err != nil {
+		return "", err
+	}
+
+	return elementAttributes(ctx, target)
+}
+
+// elementAttributes serializes the attributes of an element in name order.
+func elementAttributes(ctx context.Context, target drivers.AttributeTarget) (string, error) {
+	attrs, err := target.GetAttributes(ctx)
+	if err != nil || attrs == nil {
+		return "", err
+	}
+
+	values := make(map[string]string)
+
+	err = attrs.ForEach(ctx, func(_ context.Context, value, key runtime.Value) (runtime.Boolean, error) {
+		values[key.String()] = value.String()
+
+		return runtime.True, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(values))
+
+	for name := range values {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var b strings.Builder
+
+	for _, name := range names {
+		b.WriteString(" " + name + `="` + html.EscapeString(values[name]) + `"`)
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,85 @@
+package lib
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+const structuredDataMarkup = `<html vocab="https://schema.org/">
+  <head>
+    <meta property="og:title" content="Ferret Plush" />
+    <meta property="og:image" content="/img/front.jpg" />
+    <script type="application/ld+json">{"@type": "Product", "name": "Ferret Plush"}</script>
+  </head>
+  <body>
+    <div itemscope itemtype="https://schema.org/Offer"><span itemprop="price">19.99</span></div>
+    <div typeof="Person"><span property="name">Jane Doe</span></div>
+  </body>
+</html>`
+
+func TestStructuredData(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	page := newMemoryPage(t, structuredDataMarkup, nil)
+
+	for _, tc := range []struct {
+		source runtime.Value
+		image  string
+	}{
+		{source: page, image: "https://example.com/img/front.jpg"},
+		{source: page.GetMainFrame(), image: "https://example.com/img/front.jpg"},
+		{source: runtime.NewString(structuredDataMarkup), image: "/img/front.jpg"},
+	} {
+		out, err := StructuredData(ctx, tc.source)
+		if err != nil {
+			t.Fatalf("extract structured data: %v", err)
+		}
+
+		data, ok := out.(runtime.Map)
+		if !ok {
+			t.Fatalf("expected object, got %T", out)
+		}
+
+		for _, key := range []string{"jsonLd", "microdata", "rdfa"} {
+			value, err := data.Get(ctx, runtime.NewString(key))
+			if err != nil {
+				t.Fatalf("get %s: %v", key, err)
+			}
+
+			list, ok := value.(runtime.List)
+			if !ok {
+				t.Fatalf("expected %s to be an array, got %T", key, value)
+			}
+
+			length, err := list.Length(ctx)
+			if err != nil || length != 1 {
+				t.Fatalf("expected one %s entry, got %d", key, length)
+			}
+		}
+
+		openGraph, err := data.Get(ctx, runtime.NewString("openGraph"))
+		if err != nil {
+			t.Fatalf("get openGraph: %v", err)
+		}
+
+		image, err := openGraph.(runtime.Map).Get(ctx, runtime.NewString("image"))
+		if err != nil {
+			t.Fatalf("get og:image: %v", err)
+		}
+
+		if image.String() != tc.image {
+			t.Fatalf("expected og:image %q, got %q", tc.image, image.String())
+		}
+	}
+}
+
+func TestStructuredDataRejectsUnsupportedSource(t *testing.T) {
+	t.Parallel()
+
+	if _, err := StructuredData(context.Background(), runtime.NewInt(1)); err == nil {
+		t.Fatal("expected type error")
+	}
+}
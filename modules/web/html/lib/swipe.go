@@ -0,0 +1,51 @@
+package lib
+
+import (
+	"context"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+// Swipe swipes with one finger from one point to another.
+//
+// Points are element selectors or { x, y } viewport coordinates.
+// Touch gestures require a page opened with mobile viewport emulation.
+//
+// @param root {HTMLPage|HTMLDocument} Page or document.
+// @param from {String|Object} Selector or point to start swiping from.
+// @param to {String|Object} Selector or point to swipe to.
+// @param options {Object?} Options with the number of touch move steps.
+// @return {Boolean} True when the swipe completes.
+func Swipe(ctx context.Context, args ...runtime.Value) (runtime.Value, error) {
+	if err := runtime.ValidateArgs(args, 3, 4); err != nil {
+		return runtime.False, err
+	}
+
+	doc, err := drivers.ToDocumentGestureTarget(args[0])
+	if err != nil {
+		return runtime.False, err
+	}
+
+	from, err := toGesturePoint(ctx, args[1])
+	if err != nil {
+		return runtime.False, err
+	}
+
+	to, err := toGesturePoint(ctx, args[2])
+	if err != nil {
+		return runtime.False, err
+	}
+
+	var opts drivers.GestureOptions
+
+	if len(args) > 3 {
+		opts, err = toGestureOptions(ctx, args[3])
+
+		if err != nil {
+			return runtime.False, err
+		}
+	}
+
+	return runtime.True, doc.Swipe(ctx, from, to, opts)
+}
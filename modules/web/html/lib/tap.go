@@ -0,0 +1,29 @@
+package lib
+
+import (
+	"context"
+
+	"github.com/MontFerret/contrib/modules/web/html/drivers"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+// Tap taps an element or a point with one finger.
+//
+// Touch gestures require a page opened with mobile viewport emulation.
+//
+// @param root {HTMLPage|HTMLDocument} Page or document.
+// @param at {String|Object} Selector or { x, y } viewport point to tap.
+// @return {Boolean} True when the tap completes.
+func Tap(ctx context.Context, root, atValue runtime.Value) (runtime.Value, error) {
+	doc, err := drivers.ToDocumentGestureTarget(root)
+	if err != nil {
+		return runtime.False, err
+	}
+
+	at, err := toGesturePoint(ctx, atValue)
+	if err != nil {
+		return runtime.False, err
+	}
+
+	return runtime.True, doc.Tap(ctx, at)
+}
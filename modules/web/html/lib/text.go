@@ -0,0 +1,48 @@
+package lib
+
+import (
+	"context"
+
+	"github.com/MontFerret/contrib/modules/web/html/internal/plaintext"
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+	"github.com/MontFerret/ferret/v2/pkg/sdk"
+)
+
+type textOptions struct {
+	BaseURL       string `json:"baseUrl"`
+	PreserveLinks bool   `json:"preserveLinks"`
+	Tables        bool   `json:"tables"`
+}
+
+// Text converts a page, a document, an element subtree or HTML content to readable plain text.
+//
+// Blocks are separated by blank lines, list items keep their markers and table rows
+// become lines of tab-separated cells. Scripts, styles, form controls and hidden elements are dropped.
+//
+// @param source {HTMLPage|HTMLDocument|HTMLElement|String} Page, document, element, or HTML content.
+// @param options {Object?} Conversion options: baseUrl, preserveLinks appends resolved link targets to the link text, tables renders GFM pipe tables.
+// @return {String} Plain text content.
+func Text(ctx context.Context, args ...runtime.Value) (runtime.Value, error) {
+	if err := runtime.ValidateArgs(args, 1, 2); err != nil {
+		return runtime.None, err
+	}
+
+	var opts textOptions
+
+	if len(args) > 1 {
+		if err := sdk.Decode(ctx, args[1], &opts, sdk.DisallowUnknownFields()); err != nil {
+			return runtime.None, err
+		}
+	}
+
+	nodes, baseURL, err := markdownSource(ctx, args[0], opts.BaseURL)
+	if err != nil {
+		return runtime.None, err
+	}
+
+	return runtime.NewString(plaintext.Text(nodes, plaintext.Options{
+		BaseURL: baseURL,
+		Links:   opts.PreserveLinks,
+		Tables:  opts.Tables,
+	})), nil
+}
@@ -5,21 +5,49 @@ import (
 
 	"github.com/MontFerret/contrib/modules/web/html/drivers"
 	"github.com/MontFerret/ferret/v2/pkg/runtime"
+	"github.com/MontFerret/ferret/v2/pkg/sdk"
 )
 
 // XPath evaluates an XPath expression against an HTML root.
 //
+// Besides the XPath 1.0 core library, expressions can call extended functions
+// like matches(), lower-case(), ends-with(), replace() and string-join().
+//
 // @param root {HTMLPage|HTMLDocument|HTMLElement} HTML root.
 // @param expression {String} XPath expression.
+// @param options {Object?} Options with namespaces, an object binding the prefixes used by the expression to namespace URIs.
 // @return {Any} XPath evaluation result.
-func XPath(ctx context.Context, arg1, arg2 runtime.Value) (runtime.Value, error) {
-	target, err := drivers.ToQueryTarget(arg1)
+func XPath(ctx context.Context, args ...runtime.Value) (runtime.Value, error) {
+	if err := runtime.ValidateArgs(args, 2, 3); err != nil {
+		return runtime.None, err
+	}
+
+	expr := runtime.ToString(args[1])
+
+	// without options, drivers lacking the XPath capability still evaluate plain expressions
+	if len(args) == 2 {
+		if target, err := drivers.ToXPathTarget(args[0]); err == nil {
+			return target.XPathWithOptions(ctx, expr, drivers.XPathOptions{})
+		}
+
+		target, err := drivers.ToQueryTarget(args[0])
+		if err != nil {
+			return runtime.None, err
+		}
 
+		return target.XPath(ctx, expr)
+	}
+
+	target, err := drivers.ToXPathTarget(args[0])
 	if err != nil {
 		return runtime.None, err
 	}
 
-	expr := runtime.ToString(arg2)
+	var opts drivers.XPathOptions
+
+	if err := sdk.Decode(ctx, args[2], &opts, sdk.DisallowUnknownFields()); err != nil {
+		return runtime.None, err
+	}
 
-	return target.XPath(ctx, expr)
+	return target.XPathWithOptions(ctx, expr, opts)
 }
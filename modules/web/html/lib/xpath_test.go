@@ -0,0 +1,45 @@
+package lib
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MontFerret/ferret/v2/pkg/runtime"
+)
+
+func TestXPathBindsNamespacesFromPage(t *testing.T) {
+	t.Parallel()
+
+	page := newTestPage(t, `<html><body><div xmlns:feed="urn:vendor:feed"><feed:item>One</feed:item><feed:item>Two</feed:item></div></body></html>`)
+	options := runtime.NewObjectWith(map[string]runtime.Value{
+		"namespaces": runtime.NewObjectWith(map[string]runtime.Value{
+			"v": runtime.NewString("urn:vendor:feed"),
+		}),
+	})
+
+	value, err := XPath(context.Background(), page, runtime.NewString("string-join(//v:item/text(), ',')"), options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := value.String(); got != "One,Two" {
+		t.Fatalf("expected namespaced items to be joined, got %q", got)
+	}
+}
+
+func TestXPathRejectsUnknownOptions(t *testing.T) {
+	t.Parallel()
+
+	page := newTestPage(t, `<html><body></body></html>`)
+	options := runtime.NewObjectWith(map[string]runtime.Value{
+		"prefixes": runtime.NewObject(),
+	})
+
+	if _, err := XPath(context.Background(), page, runtime.NewString("//div"), options); err == nil {
+		t.Fatal("expected unknown XPath options to fail")
+	}
+
+	if _, err := XPath(context.Background(), page); err == nil {
+		t.Fatal("expected a missing expression to fail")
+	}
+}
@@ -13,7 +13,7 @@ require (
 	github.com/MontFerret/contrib/modules/document/xlsx v1.0.0-rc.9
 	github.com/MontFerret/contrib/modules/net/rest v1.0.0-rc.12
 	github.com/MontFerret/contrib/modules/security/jwt v1.0.0-rc.13
-	github.com/MontFerret/contrib/modules/security/oauth2 v1.0.0-rc.4
+	github.com/MontFerret/contrib/modules/security/oauth2 v1.0.0-rc.5
 	github.com/MontFerret/contrib/modules/toml v1.0.0-rc.15
 	github.com/MontFerret/contrib/modules/web/article v1.0.0-rc.16
 	github.com/MontFerret/contrib/modules/web/html v1.0.0-rc.22